@@ -11,12 +11,18 @@ import (
 )
 
 var (
-	addDatetime string
-	addTitle    string
-	addDuration int
-	addLocation string
-	addNotes    string
-	addTags     []string
+	addDatetime    string
+	addTitle       string
+	addDuration    int
+	addLocation    string
+	addNotes       string
+	addTags        []string
+	addRRule       string
+	addTZ          string
+	addAllDay      bool
+	addEnd         string
+	addNoConflicts bool
+	addRemind      string
 )
 
 var addCmd = &cobra.Command{
@@ -27,7 +33,23 @@ var addCmd = &cobra.Command{
 Examples:
   clical add --user=12345 --datetime="2025-11-20 14:00" --title="Meeting" --duration=60
   clical add --user=12345 --datetime="2025-11-20 14:00" --title="Call" --duration=30 --location="Zoom"
-  clical add --user=12345 --datetime="2025-11-21 09:00" --title="Stand-up" --duration=15 --tags=work,team`,
+  clical add --user=12345 --datetime="2025-11-21 09:00" --title="Stand-up" --duration=15 --tags=work,team
+  clical add --user=12345 --datetime="2025-11-24 09:30" --title="Weekly" --duration=30 --rrule="FREQ=WEEKLY;BYDAY=MO"
+  clical add --user=12345 --datetime="2025-12-01 10:00" --title="Review" --rrule="FREQ=MONTHLY;BYDAY=1MO;COUNT=6"
+
+  # Reminders before the start, delivered by 'clical alarm check'
+  clical add --user=12345 --datetime="2025-12-22 09:00" --title="Dentista" --remind=15m,1d
+
+  # The date is interpreted in the user's time zone, or in --tz if given
+  clical add --user=12345 --datetime="2025-12-05 23:55" --title="Flight MAD-EZE" --duration=780 --tz=Europe/Madrid
+
+  # All-day and multi-day events
+  clical add --user=12345 --datetime="2025-12-25" --title="Navidad" --all-day
+  clical add --user=12345 --datetime="2026-01-05" --end="2026-01-16" --title="Vacaciones" --all-day
+  clical add --user=12345 --datetime="2026-03-10 09:00" --end="2026-03-12 18:00" --title="Conferencia"
+
+Overlapping events are reported as a warning; use --no-conflicts to refuse
+to save an event that overlaps another one.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Validate user ID
 		if userID == "" {
@@ -39,18 +61,74 @@ Examples:
 			return fmt.Errorf("--title is required")
 		}
 
-		// Parse datetime
-		datetime, err := parseDateTime(addDatetime)
+		// Zona horaria del evento: --tz o la del usuario
+		loc := userLocation()
+		if addTZ != "" {
+			l, err := time.LoadLocation(addTZ)
+			if err != nil {
+				return fmt.Errorf("invalid --tz: %w", err)
+			}
+			loc = l
+		}
+
+		// Parse datetime (all-day events accept a plain date)
+		datetime, err := parseDateTimeIn(addDatetime, loc)
+		if addAllDay {
+			if d, derr := time.ParseInLocation("2006-01-02", strings.TrimSpace(addDatetime), loc); derr == nil {
+				datetime, err = d, nil
+			}
+		}
 		if err != nil {
 			return fmt.Errorf("error parsing --datetime: %w", err)
 		}
 
 		// Create entry
 		entry := calendar.NewEntry(userID, addTitle, datetime, addDuration)
+		entry.TZID = zoneID(loc)
+		entry.AllDay = addAllDay
+		if addAllDay {
+			entry.Duration = 0
+		}
+
+		if addEnd != "" {
+			end, err := parseEndDate(addEnd, addAllDay, loc)
+			if err != nil {
+				return fmt.Errorf("error parsing --end: %w", err)
+			}
+			entry.EndDate = &end
+			if !addAllDay {
+				entry.Duration = entry.Minutes()
+			}
+		}
 		entry.Location = addLocation
 		entry.Notes = addNotes
 		entry.Tags = addTags
 
+		if addRemind != "" {
+			reminders, err := calendar.ParseReminders(addRemind)
+			if err != nil {
+				return fmt.Errorf("error parsing --remind: %w", err)
+			}
+			entry.Reminders = reminders
+		}
+
+		if addRRule != "" {
+			rule, err := calendar.ParseRecurrenceRuleIn(addRRule, entry.DateTime.Location())
+			if err != nil {
+				return fmt.Errorf("error parsing --rrule: %w", err)
+			}
+			entry.RRule = rule
+		}
+
+		// Check for double bookings before saving
+		conflicts, err := findConflicts(entry, "")
+		if err != nil {
+			return err
+		}
+		if addNoConflicts {
+			reportConflicts(conflicts, true)
+		}
+
 		// Save
 		if err := store.SaveEntry(userID, entry); err != nil {
 			return fmt.Errorf("error saving entry: %w", err)
@@ -60,14 +138,25 @@ Examples:
 		fmt.Printf("✓ Event created successfully\n\n")
 		fmt.Printf("ID:       %s\n", entry.ID)
 		fmt.Printf("Title:    %s\n", entry.Title)
-		fmt.Printf("Date:     %s\n", entry.DateTime.Format("2006-01-02 15:04"))
-		fmt.Printf("Duration: %d minutes\n", entry.Duration)
+		fmt.Printf("Date:     %s\n", formatWithZone(entry))
+		fmt.Printf("Duration: %s\n", formatDuration(entry))
 		if entry.Location != "" {
 			fmt.Printf("Location: %s\n", entry.Location)
 		}
 		if len(entry.Tags) > 0 {
 			fmt.Printf("Tags:     %s\n", strings.Join(entry.Tags, ", "))
 		}
+		if entry.RRule != nil {
+			fmt.Printf("Repeats:  %s\n", entry.RRule.String())
+		}
+		if len(entry.Reminders) > 0 {
+			fmt.Printf("Remind:   %s before\n", calendar.FormatReminders(entry.Reminders))
+		}
+
+		if len(conflicts) > 0 {
+			fmt.Println()
+			reportConflicts(conflicts, false)
+		}
 
 		return nil
 	},
@@ -80,19 +169,29 @@ func init() {
 	addCmd.Flags().StringVar(&addLocation, "location", "", "Event location")
 	addCmd.Flags().StringVar(&addNotes, "notes", "", "Additional notes")
 	addCmd.Flags().StringSliceVar(&addTags, "tags", []string{}, "Tags (comma-separated)")
+	addCmd.Flags().StringVar(&addRRule, "rrule", "", "Recurrence rule (RFC 5545, eg: 'FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10')")
+	addCmd.Flags().StringVar(&addTZ, "tz", "", "Event time zone (IANA, eg: Europe/Madrid; default: user's time zone)")
+	addCmd.Flags().BoolVar(&addAllDay, "all-day", false, "All-day event (--datetime and --end take dates: YYYY-MM-DD)")
+	addCmd.Flags().StringVar(&addRemind, "remind", "", "Reminders before the start (comma-separated, eg: 15m,1h,1d)")
+	addCmd.Flags().StringVar(&addEnd, "end", "", "End of a multi-day event (last day for --all-day, or YYYY-MM-DD HH:MM)")
+	addCmd.Flags().BoolVar(&addNoConflicts, "no-conflicts", false, "Refuse to save the event if it overlaps another one")
 
 	addCmd.MarkFlagRequired("datetime")
 	addCmd.MarkFlagRequired("title")
 }
 
-// parseDateTime parses a date/time in multiple formats:
+// parseDateTime parses a date/time in the user's time zone (see parseDateTimeIn)
+func parseDateTime(s string) (time.Time, error) {
+	return parseDateTimeIn(s, userLocation())
+}
+
+// parseDateTimeIn parses a date/time in loc, in multiple formats:
 // - Relative: "+5m" (5 minutes), "+2h" (2 hours), "+1d" (1 day)
 // - Absolute: "YYYY-MM-DD HH:MM", "YYYY-MM-DDTHH:MM"
 // - Keywords: "tomorrow HH:MM"
-func parseDateTime(s string) (time.Time, error) {
+func parseDateTimeIn(s string, loc *time.Location) (time.Time, error) {
 	s = strings.TrimSpace(s)
-	now := time.Now()
-	loc := now.Location()
+	now := time.Now().In(loc)
 
 	// Try relative time formats: +5m, +2h, +1d
 	if strings.HasPrefix(s, "+") {
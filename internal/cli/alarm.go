@@ -28,6 +28,11 @@ var (
 	alarmWeekly  string
 	alarmMonthly string
 	alarmYearly  string
+	alarmClamp   bool
+	alarmCron    string
+	alarmEvery   string
+	alarmFrom    string
+	alarmWindow  string
 	alarmExpires string
 )
 
@@ -54,10 +59,22 @@ Examples:
   # Recurrente monthly
   clical alarm add --user alice --monthly "1 09:00" --context "Reporte mensual"
   clical alarm add --user alice --monthly "15 14:30" --context "Revisión quincenal"
+  clical alarm add --user alice --monthly "31 18:00" --clamp --context "Cierre de mes"
+  clical alarm add --user alice --monthly "last weekday 17:00" --context "Último día hábil"
+  clical alarm add --user alice --monthly "2nd tuesday 10:00" --context "Comité"
 
   # Recurrente yearly
   clical alarm add --user alice --yearly "01-01 00:00" --context "Feliz año nuevo"
-  clical alarm add --user alice --yearly "11-21 10:00" --context "Aniversario del proyecto"`,
+  clical alarm add --user alice --yearly "11-21 10:00" --context "Aniversario del proyecto"
+  clical alarm add --user alice --yearly "11 4th thursday 10:00" --context "Thanksgiving"
+
+  # Recurrente cron (minuto hora día-del-mes mes día-de-la-semana)
+  clical alarm add --user alice --cron "0 9 * * 1-5" --context "Stand-up"
+  clical alarm add --user alice --cron "*/15 9-17 * * mon-fri" --context "Revisar la cola"
+
+  # Recurrente por intervalo (desde --from, por defecto ahora; --window limita a una franja diaria)
+  clical alarm add --user alice --every 90m --context "Revisar el deploy"
+  clical alarm add --user alice --every 2h --from "2025-12-22 08:00" --window "08:00-18:00" --context "Medicación"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if userID == "" {
 			return fmt.Errorf("--user is required")
@@ -78,9 +95,13 @@ Examples:
 			return addMonthlyAlarm(userID, alarmMonthly, alarmContext, alarmExpires)
 		} else if alarmYearly != "" {
 			return addYearlyAlarm(userID, alarmYearly, alarmContext, alarmExpires)
+		} else if alarmCron != "" {
+			return addCronAlarm(userID, alarmCron, alarmContext, alarmExpires)
+		} else if alarmEvery != "" {
+			return addIntervalAlarm(userID, alarmEvery, alarmFrom, alarmWindow, alarmContext, alarmExpires)
 		}
 
-		return fmt.Errorf("must specify --at, --daily, --weekly, --monthly or --yearly")
+		return fmt.Errorf("must specify --at, --daily, --weekly, --monthly, --yearly, --cron or --every")
 	},
 }
 
@@ -93,7 +114,7 @@ func addOneTimeAlarm(userID, atStr, context string) error {
 	}
 
 	// Verify it's in the future
-	if alarmTime.Before(time.Now()) {
+	if alarmTime.Before(userNow()) {
 		printRedError("date/time must be in the future")
 		os.Exit(1)
 	}
@@ -151,7 +172,7 @@ func addDailyAlarm(userID, timeStr, context, expiresStr string) error {
 	// Save
 	schedule := alarm.DailySchedule{Hour: hour, Minute: minute}
 	filename := schedule.Filename()
-	if err := store.SaveAlarm(userID, time.Now(), alarm.RecurrenceDaily, filename, alm); err != nil {
+	if err := store.SaveAlarm(userID, userNow(), alarm.RecurrenceDaily, filename, alm); err != nil {
 		return fmt.Errorf("error saving alarm: %w", err)
 	}
 
@@ -209,7 +230,7 @@ func addWeeklyAlarm(userID, scheduleStr, context, expiresStr string) error {
 	// Save
 	schedule := alarm.WeeklySchedule{Weekday: weekday, Hour: hour, Minute: minute}
 	filename := schedule.Filename()
-	if err := store.SaveAlarm(userID, time.Now(), alarm.RecurrenceWeekly, filename, alm); err != nil {
+	if err := store.SaveAlarm(userID, userNow(), alarm.RecurrenceWeekly, filename, alm); err != nil {
 		return fmt.Errorf("error saving alarm: %w", err)
 	}
 
@@ -227,34 +248,56 @@ func addWeeklyAlarm(userID, scheduleStr, context, expiresStr string) error {
 }
 
 func addMonthlyAlarm(userID, scheduleStr, context, expiresStr string) error {
-	// Parsear "15 14:30"
-	parts := strings.Fields(scheduleStr)
-	if len(parts) != 2 {
-		return fmt.Errorf("invalid format para --monthly (debe ser DAY HH:MM)")
+	// Parsear "15 14:30", "last weekday 17:00", "2nd tuesday 09:00"
+	schedule, err := alarm.ParseMonthlySchedule(scheduleStr, alarmClamp)
+	if err != nil {
+		return fmt.Errorf("invalid --monthly: %w", err)
 	}
 
-	day, err := strconv.Atoi(parts[0])
-	if err != nil || day < 1 || day > 31 {
-		return fmt.Errorf("día inválido: %s (debe ser 1-31)", parts[0])
+	// Create alarm
+	alm := alarm.NewAlarm(context, alarm.RecurrenceMonthly)
+
+	// Add expiration if specified
+	if expiresStr != "" {
+		expiresAt, err := parseDateTime(expiresStr)
+		if err != nil {
+			return fmt.Errorf("error parsing --expires: %w", err)
+		}
+		alm.ExpiresAt = &expiresAt
 	}
 
-	timeParts := strings.Split(parts[1], ":")
-	if len(timeParts) != 2 {
-		return fmt.Errorf("invalid format para hora (debe ser HH:MM)")
+	// Save
+	filename := schedule.Filename()
+	if err := store.SaveAlarm(userID, userNow(), alarm.RecurrenceMonthly, filename, alm); err != nil {
+		return fmt.Errorf("error saving alarm: %w", err)
 	}
 
-	hour, err := strconv.Atoi(timeParts[0])
-	if err != nil || hour < 0 || hour > 23 {
-		return fmt.Errorf("invalid hour: %s", timeParts[0])
+	fmt.Printf("✓ Alarm created successfully\n\n")
+	fmt.Printf("ID:         %s\n", alm.ID)
+	fmt.Printf("Type:       monthly\n")
+	fmt.Printf("Day:        %s\n", schedule.DayString())
+	fmt.Printf("Time:       %02d:%02d\n", schedule.Hour, schedule.Minute)
+	fmt.Printf("Next run:   %s\n", schedule.Next(userNow()).Format("2006-01-02 15:04 (Monday)"))
+	fmt.Printf("Context:   %s\n", context)
+	if alm.ExpiresAt != nil {
+		fmt.Printf("Expires:     %s\n", alm.ExpiresAt.Format("2006-01-02"))
+	}
+	if schedule.Day > 28 && !schedule.Clamp {
+		fmt.Printf("\nNote: months without day %d are skipped; use --clamp to run on their last day\n", schedule.Day)
 	}
 
-	minute, err := strconv.Atoi(timeParts[1])
-	if err != nil || minute < 0 || minute > 59 {
-		return fmt.Errorf("invalid minute: %s", timeParts[1])
+	return nil
+}
+
+func addYearlyAlarm(userID, scheduleStr, context, expiresStr string) error {
+	// Parsear "11-21 14:30", "11 4th thursday 10:00"
+	schedule, err := alarm.ParseYearlySchedule(scheduleStr, alarmClamp)
+	if err != nil {
+		return fmt.Errorf("invalid --yearly: %w", err)
 	}
 
 	// Create alarm
-	alm := alarm.NewAlarm(context, alarm.RecurrenceMonthly)
+	alm := alarm.NewAlarm(context, alarm.RecurrenceYearly)
 
 	// Add expiration if specified
 	if expiresStr != "" {
@@ -266,17 +309,17 @@ func addMonthlyAlarm(userID, scheduleStr, context, expiresStr string) error {
 	}
 
 	// Save
-	schedule := alarm.MonthlySchedule{Day: day, Hour: hour, Minute: minute}
 	filename := schedule.Filename()
-	if err := store.SaveAlarm(userID, time.Now(), alarm.RecurrenceMonthly, filename, alm); err != nil {
+	if err := store.SaveAlarm(userID, userNow(), alarm.RecurrenceYearly, filename, alm); err != nil {
 		return fmt.Errorf("error saving alarm: %w", err)
 	}
 
 	fmt.Printf("✓ Alarm created successfully\n\n")
 	fmt.Printf("ID:         %s\n", alm.ID)
-	fmt.Printf("Type:       monthly\n")
-	fmt.Printf("Day:        %d\n", day)
-	fmt.Printf("Time:       %02d:%02d\n", hour, minute)
+	fmt.Printf("Type:       yearly\n")
+	fmt.Printf("Date:      %s %s\n", schedule.Month, schedule.DayString())
+	fmt.Printf("Time:       %02d:%02d\n", schedule.Hour, schedule.Minute)
+	fmt.Printf("Next run:   %s\n", schedule.Next(userNow()).Format("2006-01-02 15:04 (Monday)"))
 	fmt.Printf("Context:   %s\n", context)
 	if alm.ExpiresAt != nil {
 		fmt.Printf("Expires:     %s\n", alm.ExpiresAt.Format("2006-01-02"))
@@ -285,45 +328,70 @@ func addMonthlyAlarm(userID, scheduleStr, context, expiresStr string) error {
 	return nil
 }
 
-func addYearlyAlarm(userID, scheduleStr, context, expiresStr string) error {
-	// Parsear "11-21 14:30"
-	parts := strings.Fields(scheduleStr)
-	if len(parts) != 2 {
-		return fmt.Errorf("invalid format para --yearly (debe ser MM-DD HH:MM)")
+func addCronAlarm(userID, expr, context, expiresStr string) error {
+	schedule, err := alarm.ParseCron(expr)
+	if err != nil {
+		return err
 	}
 
-	dateParts := strings.Split(parts[0], "-")
-	if len(dateParts) != 2 {
-		return fmt.Errorf("invalid format para fecha (debe ser MM-DD)")
+	// Create alarm
+	alm := alarm.NewAlarm(context, alarm.RecurrenceCron)
+
+	// Add expiration if specified
+	if expiresStr != "" {
+		expiresAt, err := parseDateTime(expiresStr)
+		if err != nil {
+			return fmt.Errorf("error parsing --expires: %w", err)
+		}
+		alm.ExpiresAt = &expiresAt
 	}
 
-	month, err := strconv.Atoi(dateParts[0])
-	if err != nil || month < 1 || month > 12 {
-		return fmt.Errorf("mes inválido: %s (debe ser 1-12)", dateParts[0])
+	// Save
+	filename := schedule.Filename()
+	if err := store.SaveAlarm(userID, userNow(), alarm.RecurrenceCron, filename, alm); err != nil {
+		return fmt.Errorf("error saving alarm: %w", err)
 	}
 
-	day, err := strconv.Atoi(dateParts[1])
-	if err != nil || day < 1 || day > 31 {
-		return fmt.Errorf("día inválido: %s (debe ser 1-31)", dateParts[1])
+	fmt.Printf("✓ Alarm created successfully\n\n")
+	fmt.Printf("ID:         %s\n", alm.ID)
+	fmt.Printf("Type:       cron\n")
+	fmt.Printf("Schedule:   %s\n", schedule)
+	fmt.Printf("Next run:   %s\n", schedule.Next(userNow()).Format("2006-01-02 15:04 (Monday)"))
+	fmt.Printf("Context:   %s\n", context)
+	if alm.ExpiresAt != nil {
+		fmt.Printf("Expires:     %s\n", alm.ExpiresAt.Format("2006-01-02"))
 	}
 
-	timeParts := strings.Split(parts[1], ":")
-	if len(timeParts) != 2 {
-		return fmt.Errorf("invalid format para hora (debe ser HH:MM)")
+	return nil
+}
+
+func addIntervalAlarm(userID, everyStr, fromStr, windowStr, context, expiresStr string) error {
+	every, err := time.ParseDuration(everyStr)
+	if err != nil {
+		return fmt.Errorf("error parsing --every: %w", err)
 	}
 
-	hour, err := strconv.Atoi(timeParts[0])
-	if err != nil || hour < 0 || hour > 23 {
-		return fmt.Errorf("invalid hour: %s", timeParts[0])
+	start := userNow()
+	if fromStr != "" {
+		if start, err = parseDateTime(fromStr); err != nil {
+			return fmt.Errorf("error parsing --from: %w", err)
+		}
 	}
 
-	minute, err := strconv.Atoi(timeParts[1])
-	if err != nil || minute < 0 || minute > 59 {
-		return fmt.Errorf("invalid minute: %s", timeParts[1])
+	var window *alarm.TimeWindow
+	if windowStr != "" {
+		if window, err = alarm.ParseTimeWindow(windowStr); err != nil {
+			return err
+		}
+	}
+
+	schedule, err := alarm.NewIntervalSchedule(start, every, window)
+	if err != nil {
+		return err
 	}
 
 	// Create alarm
-	alm := alarm.NewAlarm(context, alarm.RecurrenceYearly)
+	alm := alarm.NewAlarm(context, alarm.RecurrenceInterval)
 
 	// Add expiration if specified
 	if expiresStr != "" {
@@ -335,17 +403,16 @@ func addYearlyAlarm(userID, scheduleStr, context, expiresStr string) error {
 	}
 
 	// Save
-	schedule := alarm.YearlySchedule{Month: time.Month(month), Day: day, Hour: hour, Minute: minute}
 	filename := schedule.Filename()
-	if err := store.SaveAlarm(userID, time.Now(), alarm.RecurrenceYearly, filename, alm); err != nil {
+	if err := store.SaveAlarm(userID, userNow(), alarm.RecurrenceInterval, filename, alm); err != nil {
 		return fmt.Errorf("error saving alarm: %w", err)
 	}
 
 	fmt.Printf("✓ Alarm created successfully\n\n")
 	fmt.Printf("ID:         %s\n", alm.ID)
-	fmt.Printf("Type:       yearly\n")
-	fmt.Printf("Date:      %02d-%02d\n", month, day)
-	fmt.Printf("Time:       %02d:%02d\n", hour, minute)
+	fmt.Printf("Type:       interval\n")
+	fmt.Printf("Schedule:   %s\n", schedule)
+	fmt.Printf("Next run:   %s\n", schedule.Next(userNow()).Format("2006-01-02 15:04 (Monday)"))
 	fmt.Printf("Context:   %s\n", context)
 	if alm.ExpiresAt != nil {
 		fmt.Printf("Expires:     %s\n", alm.ExpiresAt.Format("2006-01-02"))
@@ -359,6 +426,7 @@ var (
 	alarmCheckVerbose bool
 	alarmCheckJSON    bool
 	alarmCheckExecute string
+	alarmCheckNag     time.Duration
 )
 
 var alarmCheckCmd = &cobra.Command{
@@ -366,6 +434,11 @@ var alarmCheckCmd = &cobra.Command{
 	Short: "Check pending alarms",
 	Long: `Check and execute alarms for current time.
 Includes automatic recovery of missed alarms (últimos 60 minutos).
+Event reminders (clical add --remind) are delivered here too.
+
+Fired alarms wait to be acknowledged with 'clical alarm ack'. With --nag
+(or CLICAL_ALARM_NAG) the ones not acknowledged are delivered again every
+nag interval, for up to a day.
 
 This command is designed to run from cron every minute.
 If no alarms, produces no output (silent).
@@ -375,19 +448,33 @@ Examples:
   clical alarm check --user alice --verbose
   clical alarm check --user alice --json
   clical alarm check --user alice --execute="/path/to/script.sh"
-  clical alarm check --user alice --execute="gobot send text"`,
+  clical alarm check --user alice --execute="gobot send text"
+  clical alarm check --user alice --nag=10m`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if userID == "" {
 			return fmt.Errorf("--user is required")
 		}
 
 		// Verificar alarmas en el momento actual
-		now := time.Now()
+		now := userNow()
 		alarms, err := store.CheckAlarms(userID, now)
 		if err != nil {
 			return fmt.Errorf("error verifying alarmas: %w", err)
 		}
 
+		// Volver a entregar las alarmas sin confirmar
+		nag := alarmCheckNag
+		if !cmd.Flags().Changed("nag") && cfg != nil {
+			nag = cfg.AlarmNag
+		}
+		if nag > 0 {
+			unacked, err := store.NagAlarms(userID, now, nag)
+			if err != nil {
+				return fmt.Errorf("error re-delivering alarms: %w", err)
+			}
+			alarms = append(alarms, unacked...)
+		}
+
 		// Si no hay alarmas, salir silenciosamente
 		if len(alarms) == 0 {
 			if alarmCheckVerbose {
@@ -422,6 +509,15 @@ Examples:
 				if !alm.ScheduledFor.IsZero() {
 					fmt.Printf("    Scheduled for: %s\n", alm.ScheduledFor.Format("2006-01-02T15:04:05-07:00"))
 				}
+				if alm.Event != nil {
+					fmt.Printf("    Event: %s (starts %s)\n", alm.Event.ID, alm.Event.Start.Format("2006-01-02T15:04:05-07:00"))
+				}
+				if alm.SnoozedFrom != "" {
+					fmt.Printf("    Snoozed from: %s\n", alm.SnoozedFrom)
+				}
+				if alm.Deliveries > 1 {
+					fmt.Printf("    Not acknowledged: delivery #%d\n", alm.Deliveries)
+				}
 				fmt.Println()
 			}
 		}
@@ -443,6 +539,12 @@ func capitalizeRecurrence(r alarm.Recurrence) string {
 		return "Monthly"
 	case alarm.RecurrenceYearly:
 		return "Yearly"
+	case alarm.RecurrenceCron:
+		return "Cron"
+	case alarm.RecurrenceInterval:
+		return "Interval"
+	case alarm.RecurrenceReminder:
+		return "Reminder"
 	default:
 		return string(r)
 	}
@@ -495,8 +597,9 @@ func executeAlarmScript(scriptOrCmd string, alm *alarm.Alarm, verbose bool) erro
 
 // alarm-list
 var (
-	alarmListPast bool
-	alarmListJSON bool
+	alarmListPast    bool
+	alarmListUnacked bool
+	alarmListJSON    bool
 )
 
 var alarmListCmd = &cobra.Command{
@@ -507,6 +610,7 @@ var alarmListCmd = &cobra.Command{
 Examples:
   clical alarm list --user alice
   clical alarm list --user alice --past
+  clical alarm list --user alice --unacked
   clical alarm list --user alice --json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if userID == "" {
@@ -527,6 +631,14 @@ Examples:
 			}
 		}
 
+		var unackedAlarms []*alarm.Alarm
+		if alarmListUnacked {
+			unackedAlarms, err = store.ListUnackedAlarms(userID)
+			if err != nil {
+				return fmt.Errorf("error listing unacknowledged alarms: %w", err)
+			}
+		}
+
 		// Output JSON
 		if alarmListJSON {
 			output := map[string][]*alarm.Alarm{
@@ -535,6 +647,9 @@ Examples:
 			if alarmListPast {
 				output["past"] = pastAlarms
 			}
+			if alarmListUnacked {
+				output["unacked"] = unackedAlarms
+			}
 
 			jsonData, err := json.MarshalIndent(output, "", "  ")
 			if err != nil {
@@ -546,7 +661,7 @@ Examples:
 		}
 
 		// Output tabla
-		if len(activeAlarms) == 0 && len(pastAlarms) == 0 {
+		if len(activeAlarms) == 0 && len(pastAlarms) == 0 && len(unackedAlarms) == 0 {
 			fmt.Println("No alarms")
 			return nil
 		}
@@ -588,6 +703,22 @@ Examples:
 			fmt.Println()
 		}
 
+		if len(unackedAlarms) > 0 {
+			fmt.Println("UNACKNOWLEDGED ALARMS:")
+			fmt.Println()
+			fmt.Printf("%-25s %-10s %-20s %s\n", "ID", "TIPO", "FIRED", "CONTEXTO")
+			fmt.Println(strings.Repeat("-", 100))
+
+			for _, alm := range unackedAlarms {
+				context := alm.Context
+				if len(context) > 40 {
+					context = context[:37] + "..."
+				}
+				fmt.Printf("%-25s %-10s %-20s %s\n", alm.ID, alm.Recurrence, alm.ScheduledFor.Format("2006-01-02 15:04"), context)
+			}
+			fmt.Println()
+		}
+
 		return nil
 	},
 }
@@ -595,7 +726,7 @@ Examples:
 func formatSchedule(alm *alarm.Alarm) string {
 	// Si tiene información de Schedule, usarla
 	if alm.Schedule != nil && !alm.Schedule.NextRun.IsZero() {
-		now := time.Now()
+		now := userNow()
 		nextRun := alm.Schedule.NextRun
 
 		// Calcular cuándo es "next run" en formato amigable
@@ -651,6 +782,70 @@ Examples:
 		}
 
 		fmt.Printf("✓ Alarm canceled successfully: %s\n", alarmID)
+		fmt.Printf("  Moved to trash; restore with: clical trash restore --id=%s\n", alarmID)
+
+		return nil
+	},
+}
+
+// alarm-snooze
+var alarmSnoozeFor string
+
+var alarmSnoozeCmd = &cobra.Command{
+	Use:          "snooze ALARM_ID",
+	SilenceUsage: true,
+	Short:        "Snooze a fired alarm",
+	Long: `Re-schedule a fired alarm as a new one-time alarm, linked to the original
+(snoozed_from). Snoozing also acknowledges the fired alarm.
+
+Examples:
+  clical alarm snooze --user alice alarm_once_1234567890_abcd1234 --for=10m
+  clical alarm snooze --user alice alarm_daily_1234567890_abcd1234 --for=1h`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+
+		d, err := time.ParseDuration(alarmSnoozeFor)
+		if err != nil {
+			return fmt.Errorf("invalid --for: %w", err)
+		}
+
+		snoozed, err := store.SnoozeAlarm(userID, args[0], userNow(), d)
+		if err != nil {
+			return fmt.Errorf("error snoozing alarm: %w", err)
+		}
+
+		fmt.Printf("✓ Alarm snoozed until %s\n", snoozed.ScheduledFor.Format("2006-01-02 15:04"))
+		fmt.Printf("  New alarm: %s\n", snoozed.ID)
+
+		return nil
+	},
+}
+
+// alarm-ack
+var alarmAckCmd = &cobra.Command{
+	Use:          "ack ALARM_ID...",
+	SilenceUsage: true,
+	Short:        "Acknowledge fired alarms",
+	Long: `Acknowledge fired alarms so 'alarm check --nag' stops delivering them again.
+
+Examples:
+  clical alarm ack --user alice alarm_once_1234567890_abcd1234
+  clical alarm list --user alice --unacked`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+
+		for _, alarmID := range args {
+			if err := store.AckAlarm(userID, alarmID); err != nil {
+				return fmt.Errorf("error acknowledging alarm: %w", err)
+			}
+			fmt.Printf("✓ Alarm acknowledged: %s\n", alarmID)
+		}
 
 		return nil
 	},
@@ -701,16 +896,29 @@ Examples:
 		fmt.Printf("Context:     %s\n", foundAlarm.Context)
 		fmt.Printf("Type:        %s\n", capitalizeRecurrence(foundAlarm.Recurrence))
 		fmt.Printf("Created:     %s\n", foundAlarm.CreatedAt.Format("2006-01-02 15:04:05"))
+		if foundAlarm.SnoozedFrom != "" {
+			fmt.Printf("Snoozed from: %s\n", foundAlarm.SnoozedFrom)
+		}
 
 		if foundAlarm.Schedule != nil {
 			fmt.Printf("\nSCHEDULE\n")
 			fmt.Printf("────────\n")
 			fmt.Printf("Filename:    %s\n", foundAlarm.Schedule.Filename)
+			if foundAlarm.Recurrence == alarm.RecurrenceCron {
+				if schedule, err := alarm.ParseCronFilename(foundAlarm.Schedule.Filename); err == nil {
+					fmt.Printf("Cron:        %s\n", schedule)
+				}
+			}
+			if foundAlarm.Recurrence == alarm.RecurrenceInterval {
+				if schedule, err := alarm.ParseIntervalFilename(foundAlarm.Schedule.Filename, userLocation()); err == nil {
+					fmt.Printf("Interval:    %s\n", schedule)
+				}
+			}
 			if !foundAlarm.Schedule.NextRun.IsZero() {
 				fmt.Printf("Next run:    %s\n", foundAlarm.Schedule.NextRun.Format("2006-01-02 15:04:05 (Monday)"))
 
 				// Calcular tiempo hasta próxima ejecución
-				now := time.Now()
+				now := userNow()
 				diff := foundAlarm.Schedule.NextRun.Sub(now)
 				if diff > 0 {
 					hours := int(diff.Hours())
@@ -750,19 +958,29 @@ func init() {
 	alarmAddCmd.Flags().StringVar(&alarmAt, "at", "", "Date/time for one-time alarm (eg: '2025-11-23 14:30', 'tomorrow 10:00', '+30m')")
 	alarmAddCmd.Flags().StringVar(&alarmDaily, "daily", "", "Time for daily alarm (eg: '14:30')")
 	alarmAddCmd.Flags().StringVar(&alarmWeekly, "weekly", "", "Day and time for weekly alarm (eg: 'monday 14:30')")
-	alarmAddCmd.Flags().StringVar(&alarmMonthly, "monthly", "", "Day of month and time (eg: '15 14:30')")
-	alarmAddCmd.Flags().StringVar(&alarmYearly, "yearly", "", "Yearly date and time (eg: '11-21 14:30')")
+	alarmAddCmd.Flags().StringVar(&alarmMonthly, "monthly", "", "Day of month and time (eg: '15 14:30', 'last 18:00', 'last weekday 17:00', '2nd tuesday 10:00')")
+	alarmAddCmd.Flags().StringVar(&alarmYearly, "yearly", "", "Yearly date and time (eg: '11-21 14:30', '11 4th thursday 10:00')")
+	alarmAddCmd.Flags().BoolVar(&alarmClamp, "clamp", false, "Run --monthly/--yearly days the month doesn't have (eg: 31) on its last day")
+	alarmAddCmd.Flags().StringVar(&alarmCron, "cron", "", "Cron expression: minute hour day-of-month month day-of-week (eg: '0 9 * * 1-5')")
+	alarmAddCmd.Flags().StringVar(&alarmEvery, "every", "", "Repeat interval, in whole minutes (eg: '90m', '2h')")
+	alarmAddCmd.Flags().StringVar(&alarmFrom, "from", "", "First run of an --every alarm (default: now)")
+	alarmAddCmd.Flags().StringVar(&alarmWindow, "window", "", "Daily window of an --every alarm (eg: '08:00-18:00')")
 	alarmAddCmd.Flags().StringVar(&alarmExpires, "expires", "", "Expiration date for recurring alarms (eg: '2025-12-31')")
 
 	// alarm check
 	alarmCheckCmd.Flags().BoolVarP(&alarmCheckVerbose, "verbose", "v", false, "Show debugging logs")
 	alarmCheckCmd.Flags().BoolVar(&alarmCheckJSON, "json", false, "Output in JSON format")
 	alarmCheckCmd.Flags().StringVar(&alarmCheckExecute, "execute", "", "Execute script/command for each alarm (script path or command with args)")
+	alarmCheckCmd.Flags().DurationVar(&alarmCheckNag, "nag", 0, "Deliver unacknowledged alarms again every interval (eg: '10m'; default: CLICAL_ALARM_NAG)")
 
 	// alarm list
 	alarmListCmd.Flags().BoolVar(&alarmListPast, "past", false, "Include past alarms")
+	alarmListCmd.Flags().BoolVar(&alarmListUnacked, "unacked", false, "Include fired alarms not acknowledged yet")
 	alarmListCmd.Flags().BoolVar(&alarmListJSON, "json", false, "Output en formato JSON")
 
+	// alarm snooze
+	alarmSnoozeCmd.Flags().StringVar(&alarmSnoozeFor, "for", "10m", "How long to snooze (eg: '10m', '1h')")
+
 	// alarm details
 	alarmDetailsCmd.Flags().String("id", "", "Alarm ID (required)")
 	alarmDetailsCmd.MarkFlagRequired("id")
@@ -772,5 +990,7 @@ func init() {
 	alarmCmd.AddCommand(alarmCheckCmd)
 	alarmCmd.AddCommand(alarmListCmd)
 	alarmCmd.AddCommand(alarmCancelCmd)
+	alarmCmd.AddCommand(alarmSnoozeCmd)
+	alarmCmd.AddCommand(alarmAckCmd)
 	alarmCmd.AddCommand(alarmDetailsCmd)
 }
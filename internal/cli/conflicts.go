@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/spf13/cobra"
+)
+
+var (
+	conflictsFrom  string
+	conflictsTo    string
+	conflictsRange string
+	conflictsJSON  bool
+)
+
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "List overlapping events",
+	Long: `List every pair of events whose times overlap (double bookings).
+
+All-day events (holidays, vacations) are not considered conflicts.
+Recurring events are expanded within the range.
+
+Examples:
+  clical conflicts --user=12345 --range=week
+  clical conflicts --user=12345 --from="2025-11-01" --to="2025-11-30"
+  clical conflicts --user=12345 --range=month --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+
+		if conflictsRange == "" && conflictsFrom == "" && conflictsTo == "" {
+			conflictsRange = "week"
+		}
+
+		filter, err := buildDateFilter(conflictsFrom, conflictsTo, conflictsRange, nil)
+		if err != nil {
+			return err
+		}
+
+		entries, err := store.ListEntries(userID, filter)
+		if err != nil {
+			return fmt.Errorf("error listing events: %w", err)
+		}
+
+		localizeEntries(entries)
+		conflicts := calendar.FindAllConflicts(entries)
+
+		if conflictsJSON {
+			type conflictJSON struct {
+				A       *calendar.Entry `json:"a"`
+				B       *calendar.Entry `json:"b"`
+				Minutes int             `json:"overlap_minutes"`
+			}
+			output := make([]conflictJSON, len(conflicts))
+			for i, c := range conflicts {
+				output[i] = conflictJSON{A: c.A, B: c.B, Minutes: c.Minutes()}
+			}
+
+			jsonData, err := json.MarshalIndent(output, "", "  ")
+			if err != nil {
+				return fmt.Errorf("error serializing conflicts: %w", err)
+			}
+			fmt.Println(string(jsonData))
+			return nil
+		}
+
+		if len(conflicts) == 0 {
+			fmt.Println("No conflicts found")
+			return nil
+		}
+
+		fmt.Printf("Found %d conflict(s)\n\n", len(conflicts))
+		for _, c := range conflicts {
+			start, end := c.Overlap()
+			fmt.Printf("⚠ %s - %s (%d min overlap)\n", start.Format("2006-01-02 15:04"), end.Format("15:04"), c.Minutes())
+			fmt.Printf("  ")
+			printEntryRow(c.A)
+			fmt.Printf("\n  ")
+			printEntryRow(c.B)
+			fmt.Printf("\n\n")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	conflictsCmd.Flags().StringVar(&conflictsFrom, "from", "", "Start date (YYYY-MM-DD)")
+	conflictsCmd.Flags().StringVar(&conflictsTo, "to", "", "End date (YYYY-MM-DD)")
+	conflictsCmd.Flags().StringVar(&conflictsRange, "range", "", "Predefined range (default: week): today, week, month, ...")
+	conflictsCmd.Flags().BoolVar(&conflictsJSON, "json", false, "Output in JSON format")
+
+	rootCmd.AddCommand(conflictsCmd)
+}
+
+// findConflicts busca los eventos existentes que se superponen con entry.
+// Los eventos recurrentes se comparan durante un año desde su inicio. Las
+// entradas con ID ignoreID (ej: la serie original al dividirla) no se consideran.
+func findConflicts(entry *calendar.Entry, ignoreID string) ([]*calendar.Entry, error) {
+	from, to := entry.DateTime, entry.EndTime()
+	if entry.IsRecurring() {
+		to = from.AddDate(1, 0, 0)
+		if until := entry.RRule.Until; until != nil && until.Before(to) {
+			to = until.Add(entry.EndTime().Sub(entry.DateTime))
+		}
+	}
+
+	filter := calendar.NewFilter().WithDateRange(from, to)
+	existing, err := store.ListEntries(userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error checking conflicts: %w", err)
+	}
+
+	if ignoreID != "" {
+		kept := existing[:0]
+		for _, e := range existing {
+			if e.ID != ignoreID {
+				kept = append(kept, e)
+			}
+		}
+		existing = kept
+	}
+
+	return calendar.FindConflicts(entry, existing), nil
+}
+
+// reportConflicts muestra los eventos en conflicto. Con refuse, termina con
+// error sin guardar el evento.
+func reportConflicts(conflicts []*calendar.Entry, refuse bool) {
+	if len(conflicts) == 0 {
+		return
+	}
+
+	localizeEntries(conflicts)
+
+	fmt.Printf("⚠ Conflicts with %d event(s):\n", len(conflicts))
+	for _, c := range conflicts {
+		fmt.Printf("  ")
+		printEntryRow(c)
+		fmt.Println()
+	}
+
+	if refuse {
+		printRedError("event overlaps %d existing event(s); not saved (--no-conflicts)", len(conflicts))
+		os.Exit(1)
+	}
+	fmt.Println()
+}
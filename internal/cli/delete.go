@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/sebasvalencia/clical/pkg/calendar"
 	"github.com/spf13/cobra"
 )
 
 var (
-	deleteID    string
-	deleteForce bool
+	deleteID         string
+	deleteForce      bool
+	deleteOccurrence string
+	deleteScope      string
 )
 
 var deleteCmd = &cobra.Command{
@@ -21,7 +25,11 @@ var deleteCmd = &cobra.Command{
 
 Examples:
   clical delete --user=12345 --id=abc123def456
-  clical delete --user=12345 --id=abc123def456 --force`,
+  clical delete --user=12345 --id=abc123def456 --force
+
+  # Eventos recurrentes: cancelar una ocurrencia, o esa y las siguientes
+  clical delete --user=12345 --id=abc123def456 --occurrence=2025-11-27
+  clical delete --user=12345 --id=abc123def456 --occurrence=2025-12-01 --scope=following`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Validate arguments
 		if userID == "" {
@@ -37,13 +45,29 @@ Examples:
 			return fmt.Errorf("error getting event: %w", err)
 		}
 
+		// Resolver la ocurrencia a eliminar en eventos recurrentes
+		scope := calendar.ScopeAll
+		var recurrenceID time.Time
+		if cmd.Flags().Changed("occurrence") {
+			scope, recurrenceID, err = resolveOccurrence(entry, deleteOccurrence, deleteScope)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Show event
 		fmt.Printf("Evento a eliminar:\n")
-		fmt.Printf("  %s - %s (%d min)\n",
-			entry.DateTime.Format("2006-01-02 15:04"),
+		fmt.Printf("  %s - %s (%s)\n",
+			formatWithZone(entry),
 			entry.Title,
-			entry.Duration,
+			formatDuration(entry),
 		)
+		switch scope {
+		case calendar.ScopeThis:
+			fmt.Printf("  Solo la ocurrencia del %s\n", recurrenceID.In(userLocation()).Format("2006-01-02 15:04"))
+		case calendar.ScopeFollowing:
+			fmt.Printf("  Ocurrencias desde el %s en adelante\n", recurrenceID.In(userLocation()).Format("2006-01-02 15:04"))
+		}
 
 		// Confirmar a menos que sea --force
 		if !deleteForce {
@@ -62,11 +86,27 @@ Examples:
 		}
 
 		// Delete
-		if err := store.DeleteEntry(userID, deleteID); err != nil {
-			return fmt.Errorf("error deleting evento: %w", err)
+		switch scope {
+		case calendar.ScopeThis:
+			entry.ExcludeOccurrence(recurrenceID)
+			if err := store.UpdateEntry(userID, entry); err != nil {
+				return fmt.Errorf("error deleting occurrence: %w", err)
+			}
+		case calendar.ScopeFollowing:
+			entry.TruncateBefore(recurrenceID)
+			if err := store.UpdateEntry(userID, entry); err != nil {
+				return fmt.Errorf("error deleting occurrences: %w", err)
+			}
+		default:
+			if err := store.DeleteEntry(userID, deleteID); err != nil {
+				return fmt.Errorf("error deleting evento: %w", err)
+			}
 		}
 
 		fmt.Println("✓ Event deleted successfully")
+		if scope == calendar.ScopeAll {
+			fmt.Printf("  Moved to trash; restore with: clical trash restore --id=%s\n", deleteID)
+		}
 
 		return nil
 	},
@@ -75,5 +115,7 @@ Examples:
 func init() {
 	deleteCmd.Flags().StringVar(&deleteID, "id", "", "ID del evento a eliminar")
 	deleteCmd.Flags().BoolVar(&deleteForce, "force", false, "Eliminar sin confirmación")
+	deleteCmd.Flags().StringVar(&deleteOccurrence, "occurrence", "", "Fecha de la ocurrencia a eliminar en eventos recurrentes (YYYY-MM-DD)")
+	deleteCmd.Flags().StringVar(&deleteScope, "scope", "this", "Ocurrencias afectadas con --occurrence: this, following, all")
 	deleteCmd.MarkFlagRequired("id")
 }
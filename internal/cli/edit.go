@@ -2,18 +2,28 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/sebasvalencia/clical/pkg/calendar"
 	"github.com/spf13/cobra"
 )
 
 var (
-	editID       string
-	editTitle    string
-	editDatetime string
-	editDuration int
-	editLocation string
-	editNotes    string
+	editID          string
+	editTitle       string
+	editDatetime    string
+	editDuration    int
+	editLocation    string
+	editNotes       string
+	editRRule       string
+	editOccurrence  string
+	editScope       string
+	editTZ          string
+	editAllDay      bool
+	editEnd         string
+	editNoConflicts bool
+	editRemind      string
 )
 
 var editCmd = &cobra.Command{
@@ -24,7 +34,21 @@ var editCmd = &cobra.Command{
 Examples:
   clical edit --user=12345 --id=abc123 --title="Nuevo título"
   clical edit --user=12345 --id=abc123 --datetime="2025-11-21 15:00"
-  clical edit --user=12345 --id=abc123 --duration=90 --location="Sala 2"`,
+  clical edit --user=12345 --id=abc123 --duration=90 --location="Sala 2"
+  clical edit --user=12345 --id=abc123 --rrule="FREQ=WEEKLY;BYDAY=TU,TH"
+  clical edit --user=12345 --id=abc123 --rrule=none
+  clical edit --user=12345 --id=abc123 --tz=Europe/Madrid --datetime="2025-12-05 23:55"
+  clical edit --user=12345 --id=abc123 --all-day --end="2026-01-16"
+  clical edit --user=12345 --id=abc123 --all-day=false --datetime="2026-01-05 09:00" --duration=60
+  clical edit --user=12345 --id=abc123 --remind=10m,1h
+  clical edit --user=12345 --id=abc123 --remind=""
+
+  # Con --no-conflicts el cambio se rechaza si el evento se superpone con otro
+  clical edit --user=12345 --id=abc123 --datetime="2025-11-21 16:00" --no-conflicts
+
+  # Eventos recurrentes: modificar solo una ocurrencia, o esa y las siguientes
+  clical edit --user=12345 --id=abc123 --occurrence=2025-11-27 --datetime="2025-11-27 16:00"
+  clical edit --user=12345 --id=abc123 --occurrence=2025-12-01 --scope=following --duration=45`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Validate arguments
 		if userID == "" {
@@ -40,35 +64,148 @@ Examples:
 			return fmt.Errorf("error getting event: %w", err)
 		}
 
+		// Resolver la ocurrencia a editar en eventos recurrentes
+		scope := calendar.ScopeAll
+		var recurrenceID time.Time
+		if cmd.Flags().Changed("occurrence") {
+			scope, recurrenceID, err = resolveOccurrence(entry, editOccurrence, editScope)
+			if err != nil {
+				return err
+			}
+		}
+
+		target := entry
+		switch scope {
+		case calendar.ScopeThis:
+			if cmd.Flags().Changed("rrule") {
+				return fmt.Errorf("--rrule cannot be changed for a single occurrence")
+			}
+			target, err = store.GetOccurrence(userID, editID, recurrenceID)
+			if err != nil {
+				return fmt.Errorf("error getting occurrence: %w", err)
+			}
+		case calendar.ScopeFollowing:
+			target = entry.SplitAt(recurrenceID)
+		}
+
 		// Apply changes
 		modified := false
 
 		if cmd.Flags().Changed("title") {
-			entry.Title = editTitle
+			target.Title = editTitle
+			modified = true
+		}
+
+		if cmd.Flags().Changed("tz") {
+			if editTZ != "" {
+				if _, err := time.LoadLocation(editTZ); err != nil {
+					return fmt.Errorf("invalid --tz: %w", err)
+				}
+			}
+			target.TZID = editTZ
+			modified = true
+		}
+
+		// Las fechas nuevas se interpretan en la zona del evento, o en la del usuario
+		loc := userLocation()
+		if target.TZID != "" {
+			loc = target.TimeZone()
+		}
+
+		if cmd.Flags().Changed("all-day") {
+			if !editAllDay && target.AllDay && !cmd.Flags().Changed("duration") && !cmd.Flags().Changed("end") {
+				return fmt.Errorf("--duration or --end is required when removing --all-day")
+			}
+			target.AllDay = editAllDay
+			if editAllDay {
+				target.Duration = 0
+				if target.EndDate != nil && !cmd.Flags().Changed("end") {
+					// El fin pasa a ser el último día del evento
+					last := calendar.StartOfDay(target.EndDate.In(loc))
+					target.EndDate = &last
+				}
+			} else if !cmd.Flags().Changed("end") {
+				target.EndDate = nil
+			}
 			modified = true
 		}
 
 		if cmd.Flags().Changed("datetime") {
-			datetime, err := parseDateTime(editDatetime)
+			datetime, err := parseDateTimeIn(editDatetime, loc)
+			if target.AllDay {
+				if d, derr := time.ParseInLocation("2006-01-02", strings.TrimSpace(editDatetime), loc); derr == nil {
+					datetime, err = d, nil
+				}
+			}
 			if err != nil {
 				return fmt.Errorf("error parsing --datetime: %w", err)
 			}
-			entry.DateTime = datetime
+			// Un evento de varios días con horario se mueve conservando su duración
+			if target.EndDate != nil && !target.AllDay && !cmd.Flags().Changed("end") {
+				end := target.EndDate.Add(datetime.Sub(target.DateTime))
+				target.EndDate = &end
+			}
+			target.DateTime = datetime
 			modified = true
 		}
 
 		if cmd.Flags().Changed("duration") {
-			entry.Duration = editDuration
+			target.Duration = editDuration
+			// La duración reemplaza al fin de un evento con horario
+			if !target.AllDay {
+				target.EndDate = nil
+			}
+			modified = true
+		}
+
+		if cmd.Flags().Changed("end") {
+			if editEnd == "" {
+				if !target.AllDay {
+					target.Duration = target.Minutes()
+				}
+				target.EndDate = nil
+			} else {
+				end, err := parseEndDate(editEnd, target.AllDay, loc)
+				if err != nil {
+					return fmt.Errorf("error parsing --end: %w", err)
+				}
+				target.EndDate = &end
+				if !target.AllDay {
+					target.Duration = target.Minutes()
+				}
+			}
 			modified = true
 		}
 
 		if cmd.Flags().Changed("location") {
-			entry.Location = editLocation
+			target.Location = editLocation
 			modified = true
 		}
 
 		if cmd.Flags().Changed("notes") {
-			entry.Notes = editNotes
+			target.Notes = editNotes
+			modified = true
+		}
+
+		if cmd.Flags().Changed("remind") {
+			reminders, err := calendar.ParseReminders(editRemind)
+			if err != nil {
+				return fmt.Errorf("error parsing --remind: %w", err)
+			}
+			target.Reminders = reminders
+			modified = true
+		}
+
+		if cmd.Flags().Changed("rrule") {
+			if editRRule == "" || editRRule == "none" {
+				target.RRule = nil
+			} else {
+				rule, err := calendar.ParseRecurrenceRuleIn(editRRule, loc)
+				if err != nil {
+					return fmt.Errorf("error parsing --rrule: %w", err)
+				}
+				target.RRule = rule
+			}
 			modified = true
 		}
 
@@ -77,20 +214,59 @@ Examples:
 		}
 
 		// Actualizar timestamp
-		entry.UpdatedAt = time.Now()
+		target.UpdatedAt = time.Now()
+
+		// Detectar superposiciones con otros eventos (al dividir una serie, la
+		// serie original se recorta y no cuenta)
+		ignoreID := ""
+		if scope == calendar.ScopeFollowing {
+			ignoreID = entry.ID
+		}
+		conflicts, err := findConflicts(target, ignoreID)
+		if err != nil {
+			return err
+		}
+		if editNoConflicts {
+			reportConflicts(conflicts, true)
+		}
 
 		// Save
-		if err := store.UpdateEntry(userID, entry); err != nil {
+		if scope == calendar.ScopeFollowing {
+			// Nueva serie desde la ocurrencia, y la original termina antes
+			if err := store.SaveEntry(userID, target); err != nil {
+				return fmt.Errorf("error saving new series: %w", err)
+			}
+			entry.TruncateBefore(recurrenceID)
+			if err := store.UpdateEntry(userID, entry); err != nil {
+				return fmt.Errorf("error updating evento: %w", err)
+			}
+		} else if err := store.UpdateEntry(userID, target); err != nil {
 			return fmt.Errorf("error updating evento: %w", err)
 		}
 
 		fmt.Printf("✓ Event updated successfully\n\n")
-		fmt.Printf("ID:       %s\n", entry.ID)
-		fmt.Printf("Título:   %s\n", entry.Title)
-		fmt.Printf("Fecha:    %s\n", entry.DateTime.Format("2006-01-02 15:04"))
-		fmt.Printf("Duration: %d minutes\n", entry.Duration)
-		if entry.Location != "" {
-			fmt.Printf("Ubicación: %s\n", entry.Location)
+		fmt.Printf("ID:       %s\n", target.ID)
+		fmt.Printf("Título:   %s\n", target.Title)
+		fmt.Printf("Fecha:    %s\n", formatWithZone(target))
+		fmt.Printf("Duration: %s\n", formatDuration(target))
+		if target.Location != "" {
+			fmt.Printf("Ubicación: %s\n", target.Location)
+		}
+		if target.RRule != nil {
+			fmt.Printf("Repeats:  %s\n", target.RRule.String())
+		}
+		if len(target.Reminders) > 0 {
+			fmt.Printf("Remind:   %s before\n", calendar.FormatReminders(target.Reminders))
+		}
+		if target.IsOccurrence() {
+			fmt.Printf("Occurrence of: %s\n", target.RecurrenceID.In(userLocation()).Format("2006-01-02 15:04"))
+		}
+		if scope == calendar.ScopeFollowing {
+			fmt.Printf("\nSeries split: occurrences from %s now belong to the new ID\n", recurrenceID.Format("2006-01-02"))
+		}
+		if len(conflicts) > 0 {
+			fmt.Println()
+			reportConflicts(conflicts, false)
 		}
 
 		return nil
@@ -104,6 +280,41 @@ func init() {
 	editCmd.Flags().IntVar(&editDuration, "duration", 0, "Nueva duración")
 	editCmd.Flags().StringVar(&editLocation, "location", "", "Nueva ubicación")
 	editCmd.Flags().StringVar(&editNotes, "notes", "", "Nuevas notas")
+	editCmd.Flags().StringVar(&editRRule, "rrule", "", "Nueva regla de recurrencia (RFC 5545, 'none' para quitarla)")
+	editCmd.Flags().StringVar(&editRemind, "remind", "", "Nuevos recordatorios antes del inicio (ej: 15m,1d; vacío para quitarlos)")
+
+	editCmd.Flags().StringVar(&editOccurrence, "occurrence", "", "Fecha de la ocurrencia a editar en eventos recurrentes (YYYY-MM-DD)")
+	editCmd.Flags().StringVar(&editScope, "scope", "this", "Ocurrencias afectadas con --occurrence: this, following, all")
+	editCmd.Flags().StringVar(&editTZ, "tz", "", "Zona horaria del evento (IANA, vacío para quitarla)")
+	editCmd.Flags().BoolVar(&editAllDay, "all-day", false, "Evento de día completo (--all-day=false para quitarlo)")
+	editCmd.Flags().StringVar(&editEnd, "end", "", "Fin de un evento de varios días (último día con --all-day, vacío para quitarlo)")
+	editCmd.Flags().BoolVar(&editNoConflicts, "no-conflicts", false, "Rechazar el cambio si el evento se superpone con otro")
 
 	editCmd.MarkFlagRequired("id")
 }
+
+// resolveOccurrence parsea --occurrence y --scope y retorna el inicio de la
+// ocurrencia correspondiente del evento recurrente
+func resolveOccurrence(entry *calendar.Entry, dateStr, scopeStr string) (calendar.OccurrenceScope, time.Time, error) {
+	scope, err := calendar.ParseOccurrenceScope(scopeStr)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", dateStr, userLocation())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error parsing --occurrence: %w", err)
+	}
+
+	recurrenceID, err := entry.FindOccurrence(date)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	// Desde la primera ocurrencia, "following" equivale a toda la serie
+	if scope == calendar.ScopeFollowing && recurrenceID.Equal(entry.DateTime) {
+		scope = calendar.ScopeAll
+	}
+
+	return scope, recurrenceID, nil
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/csvio"
+	"github.com/sebasvalencia/clical/pkg/ical"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+	exportFrom   string
+	exportTo     string
+	exportRange  string
+	exportTags   []string
+
+	exportColumns    string
+	exportDelimiter  string
+	exportDateFormat string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export calendar events",
+	Long: `Export a user's events to a file format readable by other calendar tools.
+
+Supported formats:
+  ics  - iCalendar (RFC 5545), importable into Thunderbird, Google Calendar, etc.
+  csv  - CSV with a header row; columns are chosen with --columns (see import)
+
+Accepts the same filters as list (--from, --to, --range, --tags).
+In iCalendar, recurring events are exported as a whole series (RRULE) with
+their exceptions. In CSV each occurrence within the range is a row.
+
+Examples:
+  clical export --user=12345 --format=ics > calendar.ics
+  clical export --user=12345 --format=ics --range=month --output=month.ics
+  clical export --user=12345 --format=ics --from=2025-01-01 --to=2025-12-31 --tags=trabajo
+  clical export --user=12345 --format=csv --range=month --columns="datetime=Start,title=Subject,meta:project=Project"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+
+		filter, err := buildDateFilter(exportFrom, exportTo, exportRange, exportTags)
+		if err != nil {
+			return err
+		}
+
+		entries, err := store.ListEntries(userID, filter)
+		if err != nil {
+			return fmt.Errorf("error listing events: %w", err)
+		}
+
+		var out io.Writer = cmd.OutOrStdout()
+		if exportOutput != "" && exportOutput != "-" {
+			file, err := os.Create(exportOutput)
+			if err != nil {
+				return fmt.Errorf("error creating output file: %w", err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		// Usar la zona horaria del usuario si está registrado
+		var loc *time.Location
+		name := userID
+		if u, err := store.GetUser(userID); err == nil {
+			if l, err := u.Location(); err == nil {
+				loc = l
+			}
+			name = u.Name
+		}
+
+		switch exportFormat {
+		case "ics":
+			entries, err = collapseOccurrences(entries)
+			if err != nil {
+				return err
+			}
+
+			if err := ical.NewEncoder(out, loc).WithName(name).Encode(entries); err != nil {
+				return fmt.Errorf("error writing iCalendar: %w", err)
+			}
+		case "csv":
+			mapping, err := buildCSVMapping(exportColumns, exportDelimiter, exportDateFormat)
+			if err != nil {
+				return err
+			}
+			if err := csvio.NewWriter(out, mapping, loc).WriteAll(entries); err != nil {
+				return fmt.Errorf("error writing CSV: %w", err)
+			}
+		default:
+			return fmt.Errorf("unsupported format: %s (use: ics, csv)", exportFormat)
+		}
+
+		if exportOutput != "" && exportOutput != "-" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "✓ Exported %d event(s) to %s\n", len(entries), exportOutput)
+		}
+
+		return nil
+	},
+}
+
+// collapseOccurrences reemplaza las ocurrencias de eventos recurrentes por su
+// evento maestro y sus overrides, para exportar la serie completa una sola vez
+func collapseOccurrences(entries []*calendar.Entry) ([]*calendar.Entry, error) {
+	var result []*calendar.Entry
+	seen := make(map[string]bool)
+
+	for _, entry := range entries {
+		if !entry.IsOccurrence() && !entry.IsRecurring() {
+			result = append(result, entry)
+			continue
+		}
+		if seen[entry.ID] {
+			continue
+		}
+		seen[entry.ID] = true
+
+		// Sin rango de fechas, ListEntries retorna el maestro sin expandir
+		master := entry
+		if entry.IsOccurrence() {
+			var err error
+			master, err = store.GetEntry(userID, entry.ID)
+			if err != nil {
+				return nil, fmt.Errorf("error getting recurring event: %w", err)
+			}
+		}
+		overrides, err := store.ListOverrides(userID, entry.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting occurrence overrides: %w", err)
+		}
+
+		result = append(result, master)
+		result = append(result, overrides...)
+	}
+
+	return result, nil
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "ics", "Output format: ics, csv")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file (default: stdout)")
+	exportCmd.Flags().StringVar(&exportFrom, "from", "", "Start date (YYYY-MM-DD)")
+	exportCmd.Flags().StringVar(&exportTo, "to", "", "End date (YYYY-MM-DD)")
+	exportCmd.Flags().StringVar(&exportRange, "range", "", "Predefined range (same as list)")
+	exportCmd.Flags().StringSliceVar(&exportTags, "tags", []string{}, "Filter by tags")
+	exportCmd.Flags().StringVar(&exportColumns, "columns", "", "CSV column mapping (field=Header,...)")
+	exportCmd.Flags().StringVar(&exportDelimiter, "delimiter", ",", "CSV field delimiter")
+	exportCmd.Flags().StringVar(&exportDateFormat, "date-format", csvio.DefaultDateFormat, "CSV datetime format (Go layout)")
+
+	rootCmd.AddCommand(exportCmd)
+}
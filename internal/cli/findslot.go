@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/user"
+	"github.com/spf13/cobra"
+)
+
+var (
+	findSlotDuration int
+	findSlotWithin   string
+	findSlotAfter    string
+	findSlotBefore   string
+	findSlotBuffer   int
+	findSlotWeekdays bool
+	findSlotCount    int
+	findSlotJSON     bool
+)
+
+var findSlotCmd = &cobra.Command{
+	Use:   "find-slot",
+	Short: "Find the earliest free time slots",
+	Long: `Find the earliest free slots of a given duration in the user's calendar.
+
+Slots are searched within the user's working hours (see "clical user config":
+08:00-18:00 Monday to Friday by default, minus lunch), leaving buffer_minutes
+free before and after every event. All-day events do not block time.
+
+Examples:
+  clical find-slot --user=12345 --duration=45
+  clical find-slot --user=12345 --duration=45 --within=week --after=10:00 --before=17:00
+  clical find-slot --user=12345 --duration=30 --weekdays --count=5 --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+
+		u, err := store.GetUser(userID)
+		if err != nil {
+			return fmt.Errorf("error loading user: %w", err)
+		}
+
+		duration := findSlotDuration
+		if duration == 0 {
+			duration = u.Config.DefaultDuration
+		}
+
+		buffer := u.Config.BufferMinutes
+		if cmd.Flags().Changed("buffer") {
+			buffer = findSlotBuffer
+		}
+
+		// --after/--before recortan la jornada de cada día
+		after, before := 0, 24*60
+		if findSlotAfter != "" {
+			if after, err = user.ParseClock(findSlotAfter); err != nil {
+				return fmt.Errorf("invalid --after: %w", err)
+			}
+		}
+		if findSlotBefore != "" {
+			if before, err = user.ParseClock(findSlotBefore); err != nil {
+				return fmt.Errorf("invalid --before: %w", err)
+			}
+		}
+
+		hours := make(map[time.Weekday][]calendar.DayWindow)
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if findSlotWeekdays && (day == time.Saturday || day == time.Sunday) {
+				continue
+			}
+			for _, w := range u.Config.WorkWindows(day) {
+				start, end := max(w.Start, after), min(w.End, before)
+				if end > start {
+					hours[day] = append(hours[day], calendar.DayWindow{Start: start, End: end})
+				}
+			}
+		}
+
+		from, to, err := parseRange(findSlotWithin)
+		if err != nil {
+			return err
+		}
+
+		// No proponer horarios pasados: empezar en el próximo múltiplo de 5 minutos
+		now := userNow().Truncate(time.Minute)
+		if rem := now.Minute() % 5; rem != 0 {
+			now = now.Add(time.Duration(5-rem) * time.Minute)
+		}
+		if now.After(from) {
+			from = now
+		}
+
+		query := calendar.SlotQuery{
+			Duration: time.Duration(duration) * time.Minute,
+			From:     from,
+			To:       to,
+			Hours:    hours,
+			Buffer:   time.Duration(buffer) * time.Minute,
+			Count:    findSlotCount,
+		}
+		if err := query.Validate(); err != nil {
+			return err
+		}
+
+		// Incluir los eventos que terminan justo antes o empiezan justo después,
+		// para respetar el buffer en los bordes
+		margin := query.Buffer
+		filter := calendar.NewFilter().WithDateRange(from.Add(-margin), to.Add(margin))
+		entries, err := store.ListEntries(userID, filter)
+		if err != nil {
+			return fmt.Errorf("error listing events: %w", err)
+		}
+
+		localizeEntries(entries)
+		slots := calendar.FindFreeSlots(entries, query)
+
+		if findSlotJSON {
+			if slots == nil {
+				slots = []calendar.Slot{}
+			}
+			jsonData, err := json.MarshalIndent(slots, "", "  ")
+			if err != nil {
+				return fmt.Errorf("error serializing slots: %w", err)
+			}
+			fmt.Println(string(jsonData))
+			return nil
+		}
+
+		if len(slots) == 0 {
+			fmt.Printf("No free slots of %d min found\n", duration)
+			return nil
+		}
+
+		fmt.Printf("Free slots of %d min:\n\n", duration)
+		for _, s := range slots {
+			fmt.Printf("  %s %s - %s (free until %s)\n",
+				s.Start.Format("Mon"),
+				s.Start.Format("2006-01-02 15:04"),
+				s.End.Format("15:04"),
+				s.FreeUntil.Format("15:04"),
+			)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	findSlotCmd.Flags().IntVar(&findSlotDuration, "duration", 0, "Slot duration in minutes (default: user's default duration)")
+	findSlotCmd.Flags().StringVar(&findSlotWithin, "within", "week", "Range to search: today, week, month")
+	findSlotCmd.Flags().StringVar(&findSlotAfter, "after", "", "Earliest start time (HH:MM, default: start of the user's working hours)")
+	findSlotCmd.Flags().StringVar(&findSlotBefore, "before", "", "Latest end time (HH:MM, default: end of the user's working hours)")
+	findSlotCmd.Flags().IntVar(&findSlotBuffer, "buffer", 0, "Free minutes around events (default: user's buffer_minutes)")
+	findSlotCmd.Flags().BoolVar(&findSlotWeekdays, "weekdays", false, "Only Monday to Friday")
+	findSlotCmd.Flags().IntVar(&findSlotCount, "count", 3, "Number of slots to return (0 = all)")
+	findSlotCmd.Flags().BoolVar(&findSlotJSON, "json", false, "Output in JSON format")
+
+	rootCmd.AddCommand(findSlotCmd)
+}
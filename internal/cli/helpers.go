@@ -3,6 +3,10 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
 )
 
 // ANSI color codes
@@ -17,3 +21,101 @@ func printRedError(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
 	fmt.Fprintf(os.Stderr, "%sError: %s%s\n", colorRed, msg, colorReset)
 }
+
+// cachedLocation guarda la zona horaria resuelta para cachedLocationUser
+var (
+	cachedLocation     *time.Location
+	cachedLocationUser string
+)
+
+// userLocation retorna la zona horaria del usuario actual (--user). Si el usuario
+// no está registrado o su zona es inválida, se usa la zona local del proceso.
+func userLocation() *time.Location {
+	if cachedLocation != nil && cachedLocationUser == userID {
+		return cachedLocation
+	}
+
+	loc := time.Local
+	if userID != "" && store != nil {
+		if u, err := store.GetUser(userID); err == nil {
+			if l, err := u.Location(); err == nil {
+				loc = l
+			}
+		}
+	}
+
+	cachedLocation, cachedLocationUser = loc, userID
+	return loc
+}
+
+// userNow retorna la hora actual en la zona del usuario
+func userNow() time.Time {
+	return time.Now().In(userLocation())
+}
+
+// localizeEntries convierte las fechas de las entradas a la zona del usuario para mostrarlas
+func localizeEntries(entries []*calendar.Entry) {
+	loc := userLocation()
+	for _, entry := range entries {
+		entry.DateTime = entry.DateTime.In(loc)
+	}
+}
+
+// zoneID retorna el TZID a guardar para loc ("" para la zona local sin nombre)
+func zoneID(loc *time.Location) string {
+	if loc == nil || loc == time.Local || loc.String() == "Local" {
+		return ""
+	}
+	return loc.String()
+}
+
+// formatWithZone formatea el inicio de una entrada en la zona del usuario y,
+// si el evento tiene otra zona, agrega la hora en su zona de origen.
+// Los eventos de día completo se muestran como fechas y los de varios días
+// incluyen su fin.
+func formatWithZone(entry *calendar.Entry) string {
+	if entry.AllDay {
+		return formatAllDay(entry)
+	}
+
+	loc := userLocation()
+	s := entry.DateTime.In(loc).Format("2006-01-02 15:04")
+	if entry.IsMultiDay() {
+		s += " → " + entry.EndTime().In(loc).Format("2006-01-02 15:04")
+	}
+	if entry.TZID != "" && entry.TZID != loc.String() {
+		s += fmt.Sprintf(" (%s %s)", entry.LocalDateTime().Format("2006-01-02 15:04"), entry.TZID)
+	}
+	return s
+}
+
+// formatAllDay formatea los días de un evento de día completo, en su propia zona
+func formatAllDay(entry *calendar.Entry) string {
+	s := entry.LocalDateTime().Format("2006-01-02")
+	if entry.IsMultiDay() {
+		last := entry.EndTime().In(entry.TimeZone()).AddDate(0, 0, -1)
+		s += " → " + last.Format("2006-01-02")
+	}
+	return s + " (all day)"
+}
+
+// formatDuration describe la duración de una entrada
+func formatDuration(entry *calendar.Entry) string {
+	if entry.AllDay {
+		days := int(entry.EndTime().Sub(entry.DateTime).Hours()+12) / 24
+		if days == 1 {
+			return "all day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	return fmt.Sprintf("%d minutes", entry.Minutes())
+}
+
+// parseEndDate parsea --end: una fecha (YYYY-MM-DD) para eventos de día completo,
+// o fecha y hora para el resto
+func parseEndDate(s string, allDay bool, loc *time.Location) (time.Time, error) {
+	if allDay {
+		return time.ParseInLocation("2006-01-02", strings.TrimSpace(s), loc)
+	}
+	return parseDateTimeIn(s, loc)
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyID      string
+	historyJSON    bool
+	undoSteps      int
+	restoreID      string
+	restoreVersion int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the change history of an event",
+	Long: `Show every change made to an event, oldest first: what changed, when, by
+whom and with which clical command. Each change is a numbered version that
+can be brought back with 'clical restore'.
+
+Set CLICAL_ACTOR to identify who makes changes (eg: the name of an AI agent);
+by default the system user name is recorded.
+
+Examples:
+  clical history --user=12345 --id=abc123def456
+  clical history --user=12345 --id=abc123def456 --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+		if historyID == "" {
+			return fmt.Errorf("--id is required")
+		}
+
+		keeper, err := historyKeeper()
+		if err != nil {
+			return err
+		}
+
+		records, err := keeper.History(userID, historyID)
+		if err != nil {
+			return fmt.Errorf("error reading history: %w", err)
+		}
+
+		if historyJSON {
+			jsonData, err := json.MarshalIndent(records, "", "  ")
+			if err != nil {
+				return fmt.Errorf("error serializing history: %w", err)
+			}
+			fmt.Println(string(jsonData))
+			return nil
+		}
+
+		if len(records) == 0 {
+			fmt.Printf("No history for event %s\n", historyID)
+			return nil
+		}
+
+		loc := userLocation()
+		fmt.Printf("History of %s (%d versions)\n\n", historyID, len(records))
+		for i, r := range records {
+			fmt.Printf("v%-3d %s  %-7s %s\n", i+1, r.Time.In(loc).Format("2006-01-02 15:04:05"), r.Op, r.Title())
+			if r.Actor != "" {
+				fmt.Printf("     by:      %s\n", r.Actor)
+			}
+			if r.Command != "" {
+				fmt.Printf("     command: %s\n", r.Command)
+			}
+			for _, change := range describeChange(r.Before, r.After) {
+				fmt.Printf("     %s\n", change)
+			}
+		}
+
+		return nil
+	},
+}
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last changes to events",
+	Long: `Revert the last changes made to the user's events (adds, edits and
+deletes), newest first. Undoing is itself recorded in the history; changes
+that were already undone are skipped.
+
+Examples:
+  clical undo --user=12345
+  clical undo --user=12345 --steps=3`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+
+		keeper, err := historyKeeper()
+		if err != nil {
+			return err
+		}
+
+		records, err := keeper.Undo(userID, undoSteps)
+		for _, r := range records {
+			fmt.Printf("✓ Undone: %s %s [ID: %s]\n", r.Op, r.Title(), r.EntryID)
+		}
+		if err != nil {
+			return fmt.Errorf("error undoing: %w", err)
+		}
+
+		return nil
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore an event to a previous version",
+	Long: `Bring an event back to one of the versions listed by 'clical history'.
+Works for deleted events too. The restore is recorded as a new version.
+
+Examples:
+  clical restore --user=12345 --id=abc123def456 --version=2`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+		if restoreID == "" {
+			return fmt.Errorf("--id is required")
+		}
+
+		keeper, err := historyKeeper()
+		if err != nil {
+			return err
+		}
+
+		r, err := keeper.Restore(userID, restoreID, restoreVersion)
+		if err != nil {
+			return fmt.Errorf("error restoring: %w", err)
+		}
+
+		fmt.Printf("✓ Event restored to version %d: %s\n", restoreVersion, r.Title())
+		return nil
+	},
+}
+
+// historyKeeper retorna el storage como HistoryKeeper
+func historyKeeper() (storage.HistoryKeeper, error) {
+	keeper, ok := store.(storage.HistoryKeeper)
+	if !ok {
+		return nil, fmt.Errorf("change history is not available")
+	}
+	return keeper, nil
+}
+
+// describeChange lista los campos que cambiaron entre dos versiones de un evento
+func describeChange(before, after *calendar.Entry) []string {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	var changes []string
+	field := func(name, old, new string) {
+		if old != new {
+			changes = append(changes, fmt.Sprintf("%s: %q → %q", name, old, new))
+		}
+	}
+
+	field("title", before.Title, after.Title)
+	field("start", formatWithZone(before), formatWithZone(after))
+	field("duration", formatDuration(before), formatDuration(after))
+	field("location", before.Location, after.Location)
+	field("tags", strings.Join(before.Tags, " "), strings.Join(after.Tags, " "))
+	field("reminders", calendar.FormatReminders(before.Reminders), calendar.FormatReminders(after.Reminders))
+	field("notes", before.Notes, after.Notes)
+
+	rrule := func(e *calendar.Entry) string {
+		if e.RRule == nil {
+			return ""
+		}
+		return e.RRule.String()
+	}
+	field("rrule", rrule(before), rrule(after))
+	if len(before.ExDates) != len(after.ExDates) {
+		changes = append(changes, fmt.Sprintf("cancelled occurrences: %d → %d", len(before.ExDates), len(after.ExDates)))
+	}
+
+	return changes
+}
+
+func init() {
+	historyCmd.Flags().StringVar(&historyID, "id", "", "Event ID")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "Output in JSON format")
+
+	undoCmd.Flags().IntVar(&undoSteps, "steps", 1, "Number of changes to undo")
+
+	restoreCmd.Flags().StringVar(&restoreID, "id", "", "Event ID")
+	restoreCmd.Flags().IntVar(&restoreVersion, "version", 0, "Version to restore (see clical history)")
+	restoreCmd.MarkFlagRequired("version")
+
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
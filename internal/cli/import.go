@@ -0,0 +1,377 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/csvio"
+	"github.com/sebasvalencia/clical/pkg/ical"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormat     string
+	importDryRun     bool
+	importColumns    string
+	importDelimiter  string
+	importDateFormat string
+)
+
+// importStats acumula el resultado de una importación
+type importStats struct {
+	created int
+	updated int
+	skipped int
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import calendar events from a file",
+	Long: `Import events from a file exported by another calendar tool.
+
+Supported formats:
+  ics  - iCalendar (RFC 5545)
+  csv  - CSV with a header row
+
+Each event imported from iCalendar remembers its source UID, so importing the
+same file again updates the existing events instead of creating duplicates.
+Events that did not change are skipped. Use "-" as FILE to read from stdin.
+
+CSV columns are mapped with --columns as field=Header pairs. Fields: datetime,
+title, duration, location, notes, tags (separated by ";"), all_day (true/false),
+end (last day of all-day events, or end date and time) and meta:KEY for
+metadata. datetime and title are required. Every row is validated; invalid rows
+are reported with their line number and the rest are still imported.
+
+Examples:
+  clical import --user=12345 --format=ics calendar.ics
+  clical import --user=12345 --format=ics --dry-run calendar.ics
+  clical import --user=12345 --format=csv events.csv
+  clical import --user=12345 --format=csv --columns="datetime=Start,title=Subject,meta:project=Project" \
+      --date-format="02/01/2006 15:04" --delimiter=";" events.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+
+		var in io.Reader = os.Stdin
+		if args[0] != "-" {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("error opening file: %w", err)
+			}
+			defer file.Close()
+			in = file
+		}
+
+		switch importFormat {
+		case "ics":
+			return importICS(in)
+		case "csv":
+			return importCSV(in)
+		default:
+			return fmt.Errorf("unsupported format: %s (use: ics, csv)", importFormat)
+		}
+	},
+}
+
+// userImportSettings retorna la zona horaria y la duración por defecto del usuario
+func userImportSettings() (*time.Location, int) {
+	duration := 0
+	if u, err := store.GetUser(userID); err == nil {
+		duration = u.Config.DefaultDuration
+	}
+	return userLocation(), duration
+}
+
+// importICS importa un archivo iCalendar, actualizando por UID los eventos ya importados
+func importICS(in io.Reader) error {
+	// Interpretar fechas flotantes en la zona del usuario
+	loc, duration := userImportSettings()
+
+	result, err := ical.NewDecoder(in, userID, loc).WithDefaultDuration(duration).Decode()
+	if err != nil {
+		return fmt.Errorf("error reading iCalendar: %w", err)
+	}
+
+	existing, err := store.ListEntries(userID, nil)
+	if err != nil {
+		return fmt.Errorf("error listing events: %w", err)
+	}
+
+	byUID := make(map[string]*calendar.Entry, len(existing))
+	for _, entry := range existing {
+		byUID[ical.UID(entry)] = entry
+	}
+
+	// Los eventos maestros primero, para poder asociarles sus overrides
+	entries := result.Entries
+	sort.SliceStable(entries, func(i, j int) bool {
+		return !entries[i].IsOccurrence() && entries[j].IsOccurrence()
+	})
+
+	if importDryRun {
+		fmt.Printf("Dry run: no changes will be saved\n\n")
+	}
+
+	// Las horas se muestran en la zona del usuario, con o sin --dry-run
+	// (al guardar, DateTime pasa a la zona del TZID del evento)
+	stats := &importStats{skipped: len(result.Skipped)}
+	created := make(map[string]bool)
+
+	for _, entry := range entries {
+		uid := entry.Metadata[ical.MetadataUID]
+
+		var action string
+		var err error
+		if entry.IsOccurrence() {
+			action, err = importOverride(entry, byUID[uid], created)
+		} else {
+			action, err = importEntry(entry, byUID[uid])
+			if err == nil {
+				byUID[uid] = entry
+				if action == "created" {
+					created[entry.ID] = true
+				}
+			}
+		}
+
+		if err != nil {
+			stats.skipped++
+			fmt.Printf("  = skipped  %s  %s (%v)\n", entry.DateTime.In(loc).Format("2006-01-02 15:04"), entry.Title, err)
+			continue
+		}
+
+		switch action {
+		case "created":
+			stats.created++
+			fmt.Printf("  + created  %s  %s\n", entry.DateTime.In(loc).Format("2006-01-02 15:04"), entry.Title)
+		case "updated":
+			stats.updated++
+			fmt.Printf("  ~ updated  %s  %s\n", entry.DateTime.In(loc).Format("2006-01-02 15:04"), entry.Title)
+		default:
+			stats.skipped++
+			fmt.Printf("  = skipped  %s  %s (unchanged)\n", entry.DateTime.In(loc).Format("2006-01-02 15:04"), entry.Title)
+		}
+	}
+
+	for _, skip := range result.Skipped {
+		fmt.Printf("  = skipped  %s (line %d: %s)\n", skip.UID, skip.Line, skip.Reason)
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("  ! %s\n", warning)
+	}
+
+	fmt.Printf("\nCreated: %d  Updated: %d  Skipped: %d\n", stats.created, stats.updated, stats.skipped)
+
+	return nil
+}
+
+// importCSV importa un archivo CSV. Las filas inválidas o que no se pueden
+// guardar se reportan con su número de línea sin detener la importación de
+// las demás.
+func importCSV(in io.Reader) error {
+	mapping, err := buildCSVMapping(importColumns, importDelimiter, importDateFormat)
+	if err != nil {
+		return err
+	}
+
+	loc, duration := userImportSettings()
+
+	result, err := csvio.NewReader(in, mapping, userID, loc).WithDefaultDuration(duration).ReadAll()
+	if err != nil {
+		return fmt.Errorf("error reading CSV: %w", err)
+	}
+
+	if importDryRun {
+		fmt.Printf("Dry run: no changes will be saved\n\n")
+	}
+
+	created := 0
+	for i, entry := range result.Entries {
+		if !importDryRun {
+			if err := store.SaveEntry(userID, entry); err != nil {
+				result.Errors = append(result.Errors, &csvio.RowError{Line: result.Lines[i], Err: fmt.Errorf("error saving entry: %w", err)})
+				continue
+			}
+		}
+		created++
+		fmt.Printf("  + created  %s  %s\n", entry.DateTime.In(loc).Format("2006-01-02 15:04"), entry.Title)
+	}
+
+	sort.Slice(result.Errors, func(i, j int) bool { return result.Errors[i].Line < result.Errors[j].Line })
+	for _, rowErr := range result.Errors {
+		fmt.Printf("  = skipped  line %d: %v\n", rowErr.Line, rowErr.Err)
+	}
+
+	fmt.Printf("\nCreated: %d  Skipped: %d\n", created, len(result.Errors))
+
+	if len(result.Errors) > 0 {
+		printRedError("%d row(s) could not be imported", len(result.Errors))
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// buildCSVMapping arma el mapeo de columnas CSV a partir de los flags
+func buildCSVMapping(columns, delimiter, dateFormat string) (*csvio.Mapping, error) {
+	mapping, err := csvio.ParseMapping(columns)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing --columns: %w", err)
+	}
+
+	if delimiter != "" {
+		r := []rune(delimiter)
+		if delimiter == `\t` {
+			r = []rune{'\t'}
+		}
+		if len(r) != 1 {
+			return nil, fmt.Errorf("--delimiter must be a single character")
+		}
+		mapping.Comma = r[0]
+	}
+
+	if dateFormat != "" {
+		mapping.DateFormat = dateFormat
+	}
+
+	return mapping, nil
+}
+
+// importEntry crea o actualiza un evento importado. Retorna "created",
+// "updated" o "unchanged".
+func importEntry(entry, current *calendar.Entry) (string, error) {
+	if current == nil {
+		if importDryRun {
+			return "created", nil
+		}
+		if err := store.SaveEntry(userID, entry); err != nil {
+			return "", err
+		}
+		return "created", nil
+	}
+
+	// Conservar la identidad y los metadatos propios del evento existente
+	entry.ID = current.ID
+	entry.CreatedAt = current.CreatedAt
+	if _, ok := current.Metadata[ical.MetadataUID]; !ok {
+		// Evento propio re-importado desde un export: su UID ya es el ID
+		delete(entry.Metadata, ical.MetadataUID)
+	}
+	for k, v := range current.Metadata {
+		if _, ok := entry.Metadata[k]; !ok {
+			entry.Metadata[k] = v
+		}
+	}
+
+	if sameEntryContent(entry, current) {
+		*entry = *current
+		return "unchanged", nil
+	}
+
+	if importDryRun {
+		return "updated", nil
+	}
+	if err := store.UpdateEntry(userID, entry); err != nil {
+		return "", err
+	}
+	return "updated", nil
+}
+
+// importOverride guarda el override de una ocurrencia de un evento importado
+func importOverride(entry, master *calendar.Entry, created map[string]bool) (string, error) {
+	if master == nil || !master.IsRecurring() {
+		return "", fmt.Errorf("recurring event not found")
+	}
+	if !master.HasOccurrence(*entry.RecurrenceID) {
+		return "", fmt.Errorf("occurrence %s not found in series", entry.RecurrenceID.Format("2006-01-02 15:04"))
+	}
+	entry.ID = master.ID
+
+	action := "created"
+	if !created[master.ID] {
+		overrides, err := store.ListOverrides(userID, master.ID)
+		if err != nil {
+			return "", err
+		}
+		for _, o := range overrides {
+			if !o.RecurrenceID.Equal(*entry.RecurrenceID) {
+				continue
+			}
+			if sameEntryContent(entry, o) {
+				return "unchanged", nil
+			}
+			entry.CreatedAt = o.CreatedAt
+			action = "updated"
+			break
+		}
+	}
+
+	if importDryRun {
+		return action, nil
+	}
+	if err := store.SaveEntry(userID, entry); err != nil {
+		return "", err
+	}
+	return action, nil
+}
+
+// sameEntryContent compara los campos que provienen del archivo importado
+func sameEntryContent(a, b *calendar.Entry) bool {
+	if a.Title != b.Title || !a.DateTime.Equal(b.DateTime) || a.Duration != b.Duration ||
+		a.Location != b.Location || a.Notes != b.Notes || a.TZID != b.TZID {
+		return false
+	}
+
+	if a.AllDay != b.AllDay || !a.EndTime().Equal(b.EndTime()) {
+		return false
+	}
+
+	if strings.Join(a.Tags, ",") != strings.Join(b.Tags, ",") {
+		return false
+	}
+
+	if (a.RRule == nil) != (b.RRule == nil) {
+		return false
+	}
+	if a.RRule != nil && a.RRule.String() != b.RRule.String() {
+		return false
+	}
+
+	if len(a.ExDates) != len(b.ExDates) {
+		return false
+	}
+	for i := range a.ExDates {
+		if !a.ExDates[i].Equal(b.ExDates[i]) {
+			return false
+		}
+	}
+
+	if len(a.Metadata) != len(b.Metadata) {
+		return false
+	}
+	for k, v := range a.Metadata {
+		if b.Metadata[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFormat, "format", "ics", "Input format: ics, csv")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be imported without saving")
+	importCmd.Flags().StringVar(&importColumns, "columns", "", "CSV column mapping (field=Header,...)")
+	importCmd.Flags().StringVar(&importDelimiter, "delimiter", ",", "CSV field delimiter")
+	importCmd.Flags().StringVar(&importDateFormat, "date-format", csvio.DefaultDateFormat, "CSV datetime format (Go layout)")
+
+	rootCmd.AddCommand(importCmd)
+}
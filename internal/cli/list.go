@@ -14,6 +14,7 @@ var (
 	listTo    string
 	listRange string
 	listTags  []string
+	listMatch string
 )
 
 var listCmd = &cobra.Command{
@@ -39,7 +40,11 @@ Examples:
   clical list --user=12345 --range=yesterday
   clical list --user=12345 --range=48
   clical list --user=12345 --range=month-to-date
-  clical list --user=12345 --tags=trabajo,reunion`,
+  clical list --user=12345 --tags=trabajo,reunion
+
+By default an event is listed if it overlaps the range, so an event from 23:00
+to 01:00 shows up on both days. Use --match=starts-within to list only events
+that start inside the range.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Validate user ID
 		if userID == "" {
@@ -47,40 +52,14 @@ Examples:
 		}
 
 		// Build filter
-		filter := calendar.NewFilter()
-
-		// Apply predefined range
-		if listRange != "" {
-			from, to, err := parseRange(listRange)
-			if err != nil {
-				return err
-			}
-			filter.From = &from
-			filter.To = &to
-		}
-
-		// Apply manual from/to (overrides range)
-		if listFrom != "" {
-			from, err := time.Parse("2006-01-02", listFrom)
-			if err != nil {
-				return fmt.Errorf("error parsing --from: %w", err)
-			}
-			filter.From = &from
-		}
-
-		if listTo != "" {
-			to, err := time.Parse("2006-01-02", listTo)
-			if err != nil {
-				return fmt.Errorf("error parsing --to: %w", err)
-			}
-			// Include entire final day
-			to = to.Add(24 * time.Hour)
-			filter.To = &to
+		filter, err := buildDateFilter(listFrom, listTo, listRange, listTags)
+		if err != nil {
+			return err
 		}
 
-		// Apply tags
-		if len(listTags) > 0 {
-			filter.Tags = listTags
+		filter.RangeMode, err = calendar.ParseRangeMode(listMatch)
+		if err != nil {
+			return fmt.Errorf("invalid --match: %w", err)
 		}
 
 		// Get events
@@ -97,6 +76,8 @@ Examples:
 
 		fmt.Printf("Found %d event(s)\n\n", len(entries))
 
+		localizeEntries(entries)
+
 		for _, entry := range entries {
 			printEntryRow(entry)
 			fmt.Println()
@@ -111,32 +92,77 @@ func init() {
 	listCmd.Flags().StringVar(&listTo, "to", "", "End date (YYYY-MM-DD)")
 	listCmd.Flags().StringVar(&listRange, "range", "", "Predefined range: today, yesterday, 48, week, past-week, month, past-month, month-to-date, year-to-date")
 	listCmd.Flags().StringSliceVar(&listTags, "tags", []string{}, "Filter by tags")
+	listCmd.Flags().StringVar(&listMatch, "match", "overlap", "How events match the range: overlap, starts-within")
 }
 
-// parseRange parsea rangos predefinidos como "today", "week", "month"
+// buildDateFilter construye un filtro a partir de los flags --from, --to,
+// --range y --tags (compartidos por list y export)
+func buildDateFilter(fromStr, toStr, rangeStr string, tags []string) (*calendar.Filter, error) {
+	filter := calendar.NewFilter()
+
+	// Apply predefined range
+	if rangeStr != "" {
+		from, to, err := parseRange(rangeStr)
+		if err != nil {
+			return nil, err
+		}
+		filter.From = &from
+		filter.To = &to
+	}
+
+	// Apply manual from/to (overrides range), as days in the user's time zone
+	loc := userLocation()
+	if fromStr != "" {
+		from, err := time.ParseInLocation("2006-01-02", fromStr, loc)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing --from: %w", err)
+		}
+		filter.From = &from
+	}
+
+	if toStr != "" {
+		to, err := time.ParseInLocation("2006-01-02", toStr, loc)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing --to: %w", err)
+		}
+		// Include entire final day
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	// Apply tags
+	if len(tags) > 0 {
+		filter.Tags = tags
+	}
+
+	return filter, nil
+}
+
+// parseRange parsea rangos predefinidos como "today", "week", "month" en la
+// zona horaria del usuario
 func parseRange(r string) (time.Time, time.Time, error) {
-	now := time.Now()
+	now := userNow()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	yesterday := today.Add(-24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
 
 	switch r {
 	case "today":
-		return today, today.Add(24 * time.Hour), nil
+		return today, today.AddDate(0, 0, 1), nil
 
 	case "yesterday":
 		return yesterday, today, nil
 
 	case "48":
 		// Yesterday + today (48 hours)
-		return yesterday, today.Add(24 * time.Hour), nil
+		return yesterday, today.AddDate(0, 0, 1), nil
 
 	case "week":
 		// From today for next 7 days
-		return today, today.Add(7 * 24 * time.Hour), nil
+		return today, today.AddDate(0, 0, 7), nil
 
 	case "past-week":
 		// Last 7 days (including today)
-		return today.Add(-7 * 24 * time.Hour), today.Add(24 * time.Hour), nil
+		return today.AddDate(0, 0, -7), today.AddDate(0, 0, 1), nil
 
 	case "month":
 		// From today until end of month
@@ -145,17 +171,17 @@ func parseRange(r string) (time.Time, time.Time, error) {
 
 	case "past-month":
 		// Last 30 days (including today)
-		return today.Add(-30 * 24 * time.Hour), today.Add(24 * time.Hour), nil
+		return today.AddDate(0, 0, -30), today.AddDate(0, 0, 1), nil
 
 	case "month-to-date":
 		// From start of current month until today
 		startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-		return startOfMonth, today.Add(24 * time.Hour), nil
+		return startOfMonth, today.AddDate(0, 0, 1), nil
 
 	case "year-to-date":
 		// From start of year until today
 		startOfYear := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
-		return startOfYear, today.Add(24 * time.Hour), nil
+		return startOfYear, today.AddDate(0, 0, 1), nil
 
 	default:
 		return time.Time{}, time.Time{}, fmt.Errorf("invalid range: %s (use: today, yesterday, 48, week, past-week, month, past-month, month-to-date, year-to-date)", r)
@@ -164,17 +190,36 @@ func parseRange(r string) (time.Time, time.Time, error) {
 
 // printEntryRow imprime una entrada en formato compacto
 func printEntryRow(entry *calendar.Entry) {
-	fmt.Printf("[%s] %s",
-		entry.DateTime.Format("2006-01-02 15:04"),
-		entry.Title,
-	)
+	switch {
+	case entry.AllDay:
+		fmt.Printf("[%s] %s", formatAllDay(entry), entry.Title)
+	case entry.IsMultiDay():
+		fmt.Printf("[%s → %s] %s",
+			entry.DateTime.Format("2006-01-02 15:04"),
+			entry.EndTime().In(entry.DateTime.Location()).Format("2006-01-02 15:04"),
+			entry.Title,
+		)
+	default:
+		fmt.Printf("[%s] %s",
+			entry.DateTime.Format("2006-01-02 15:04"),
+			entry.Title,
+		)
+	}
 
-	if entry.Duration > 0 {
-		fmt.Printf(" (%d min)", entry.Duration)
+	if !entry.AllDay && entry.Minutes() > 0 {
+		fmt.Printf(" (%d min)", entry.Minutes())
+	}
+
+	if !entry.AllDay && entry.TZID != "" && entry.TZID != userLocation().String() {
+		fmt.Printf(" {%s %s}", entry.LocalDateTime().Format("15:04"), entry.TZID)
 	}
 
 	fmt.Printf(" [ID: %s]", entry.ID)
 
+	if entry.IsOccurrence() || entry.IsRecurring() {
+		fmt.Printf(" ↻")
+	}
+
 	if entry.Location != "" {
 		fmt.Printf(" - %s", entry.Location)
 	}
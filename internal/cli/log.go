@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sebasvalencia/clical/pkg/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logID    string
+	logLimit int
+	logJSON  bool
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the git log of the data directory",
+	Long: `Show the commits made to the data directory, newest first. Requires
+CLICAL_GIT=true, which makes clical commit every change to its data.
+
+With --user only the commits that changed that user's data are shown; with
+--id only those of one event or alarm.
+
+Examples:
+  clical log
+  clical log --user=12345 --limit=50
+  clical log --user=12345 --id=abc123def456 --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versioned, err := gitVersioned()
+		if err != nil {
+			return err
+		}
+
+		commits, err := versioned.GitLog(storage.GitLogOptions{UserID: userID, EntryID: logID, Limit: logLimit})
+		if err != nil {
+			return fmt.Errorf("error reading log: %w", err)
+		}
+
+		if logJSON {
+			if commits == nil {
+				commits = []*storage.GitCommit{}
+			}
+			jsonData, err := json.MarshalIndent(commits, "", "  ")
+			if err != nil {
+				return fmt.Errorf("error serializing log: %w", err)
+			}
+			fmt.Println(string(jsonData))
+			return nil
+		}
+
+		if len(commits) == 0 {
+			fmt.Println("No commits")
+			return nil
+		}
+
+		loc := userLocation()
+		for _, c := range commits {
+			fmt.Printf("%s %s  %-12s %s\n", c.Hash[:7], c.Time.In(loc).Format("2006-01-02 15:04"), c.Author, c.Subject)
+		}
+
+		return nil
+	},
+}
+
+// gitVersioned retorna el storage como GitVersioned
+func gitVersioned() (storage.GitVersioned, error) {
+	versioned, ok := store.(storage.GitVersioned)
+	if !ok || !cfg.Git {
+		return nil, fmt.Errorf("git versioning is not enabled (set CLICAL_GIT=true)")
+	}
+	return versioned, nil
+}
+
+func init() {
+	logCmd.Flags().StringVar(&logID, "id", "", "Only commits of this event or alarm ID")
+	logCmd.Flags().IntVar(&logLimit, "limit", 20, "Maximum number of commits (0 = all)")
+	logCmd.Flags().BoolVar(&logJSON, "json", false, "Output in JSON format")
+
+	rootCmd.AddCommand(logCmd)
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/sebasvalencia/clical/pkg/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateFrom  string
+	migrateTo    string
+	migrateForce bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy all data to another storage backend",
+	Long: `Copy all users, events, alarms and report state from one storage backend
+to another, inside the same data directory.
+
+Backends:
+  fs      Markdown + JSON files (default)
+  sqlite  Embedded SQLite database (<data-dir>/clical.db)
+
+The source defaults to the configured backend (CLICAL_STORAGE). Every user is
+verified after being copied; the source is never modified. Users that already
+exist in the destination are only replaced with --force. After migrating,
+set CLICAL_STORAGE to the new backend to start using it.
+
+Examples:
+  clical migrate --to=sqlite
+  clical migrate --from=sqlite --to=fs --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if migrateTo == "" {
+			return fmt.Errorf("--to is required (fs or sqlite)")
+		}
+
+		from := migrateFrom
+		if from == "" {
+			from = cfg.Storage
+		}
+		if from == "" {
+			from = storage.BackendFilesystem
+		}
+		if from == migrateTo {
+			return fmt.Errorf("source and destination backend are the same: %s", from)
+		}
+
+		src, err := storage.New(from, cfg.DataDir)
+		if err != nil {
+			return err
+		}
+		defer storage.Close(src)
+		dst, err := storage.New(migrateTo, cfg.DataDir)
+		if err != nil {
+			return err
+		}
+		defer storage.Close(dst)
+		dst = storage.WithLocking(dst, cfg.DataDir, cfg.LockTimeout)
+
+		result, err := storage.Migrate(src, dst, migrateForce)
+		if err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+
+		fmt.Printf("✓ Migrated %d users (%d events, %d alarm files) from %s to %s\n",
+			result.Users, result.Entries, result.AlarmFiles, from, migrateTo)
+		fmt.Printf("  Set CLICAL_STORAGE=%s to use the new backend\n", migrateTo)
+
+		return nil
+	},
+}
+
+func init() {
+	migrateCmd.Flags().StringVar(&migrateFrom, "from", "", "Source backend: fs or sqlite (default: configured backend)")
+	migrateCmd.Flags().StringVar(&migrateTo, "to", "", "Destination backend: fs or sqlite (required)")
+	migrateCmd.Flags().BoolVar(&migrateForce, "force", false, "Overwrite users that already exist in the destination")
+
+	rootCmd.AddCommand(migrateCmd)
+}
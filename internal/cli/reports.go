@@ -38,10 +38,10 @@ Examples:
 			return fmt.Errorf("--user is required")
 		}
 
-		// Parse date (default today)
-		date := time.Now()
+		// Parse date (default today), in the user's time zone
+		date := userNow()
 		if dailyReportDate != "" {
-			parsed, err := time.Parse("2006-01-02", dailyReportDate)
+			parsed, err := time.ParseInLocation("2006-01-02", dailyReportDate, userLocation())
 			if err != nil {
 				return fmt.Errorf("error parsing --date: %w", err)
 			}
@@ -78,7 +78,7 @@ Examples:
 		}
 
 		// Tomorrow
-		tomorrow := time.Now().Add(24 * time.Hour)
+		tomorrow := userNow().AddDate(0, 0, 1)
 
 		// Generate tomorrow report
 		report, err := reporter.GenerateDailyReport(store, userID, tomorrow)
@@ -132,6 +132,8 @@ Examples:
 			return fmt.Errorf("error getting events: %w", err)
 		}
 
+		localizeEntries(events)
+
 		// Show events
 		if len(events) == 0 {
 			fmt.Printf("No hay eventos próximos en las siguientes %d horas\n", upcomingHours)
@@ -147,8 +149,13 @@ Examples:
 				continue // Skip eventos que ya empezaron
 			}
 
-			fmt.Printf("⏰ **In %d minutes** (%s)\n", minutesUntil, event.DateTime.Format("15:04"))
-			fmt.Printf("   %s (%d min)\n", event.Title, event.Duration)
+			if event.AllDay {
+				fmt.Printf("📆 **%s**\n", formatAllDay(event))
+				fmt.Printf("   %s\n", event.Title)
+			} else {
+				fmt.Printf("⏰ **In %d minutes** (%s)\n", minutesUntil, event.DateTime.Format("15:04"))
+				fmt.Printf("   %s (%d min)\n", event.Title, event.Minutes())
+			}
 			fmt.Printf("   🆔 %s\n", event.ID)
 
 			if event.Location != "" {
@@ -181,8 +188,8 @@ Examples:
 			return fmt.Errorf("--user is required")
 		}
 
-		// Start and end of week
-		now := time.Now()
+		// Start and end of week, in the user's time zone
+		now := userNow()
 		weekday := int(now.Weekday())
 		if weekday == 0 {
 			weekday = 7 // Sunday = 7
@@ -197,12 +204,15 @@ Examples:
 
 		// Get week events
 		filter := calendar.NewFilter()
-		filter.WithDateRange(monday, sunday)
+		filter.WithDateRange(monday, sunday).WithRangeMode(calendar.RangeOverlap)
 		events, err := store.ListEntries(userID, filter)
 		if err != nil {
 			return fmt.Errorf("error getting events: %w", err)
 		}
 
+		localizeEntries(events)
+		config := reporter.UserConfig(store, userID)
+
 		// Show report
 		fmt.Printf("# Reporte Semanal: %s al %s\n\n",
 			monday.Format("2006-01-02"),
@@ -210,37 +220,41 @@ Examples:
 
 		fmt.Printf("**Total de eventos:** %d\n\n", len(events))
 
-		// Group by day
-		eventsByDay := make(map[string][]*calendar.Entry)
-		for _, event := range events {
-			day := event.DateTime.Format("2006-01-02")
-			eventsByDay[day] = append(eventsByDay[day], event)
-		}
-
-		// Show each day
+		// Show each day (multi-day events appear on every day they span)
 		weekdays := []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
 		currentDay := monday
 
 		for i := 0; i < 7; i++ {
-			dayStr := currentDay.Format("2006-01-02")
-			dayEvents := eventsByDay[dayStr]
+			nextDay := currentDay.AddDate(0, 0, 1)
+			allDay, timed := eventsForDay(events, currentDay, nextDay)
 
 			fmt.Printf("## %s %s\n\n", weekdays[i], currentDay.Format("02/01"))
 
-			if len(dayEvents) == 0 {
+			if len(allDay) == 0 && len(timed) == 0 {
 				fmt.Printf("*No events*\n\n")
 			} else {
-				for _, event := range dayEvents {
+				for _, event := range allDay {
+					fmt.Printf("- [all day] %s [ID: %s]\n", event.Title, event.ID)
+				}
+				for _, event := range timed {
 					fmt.Printf("- [%s] %s (%d min) [ID: %s]\n",
-						event.DateTime.Format("15:04"),
+						formatDayTime(event, currentDay),
 						event.Title,
-						event.Duration,
+						event.Minutes(),
 						event.ID)
 				}
 				fmt.Println()
 			}
 
-			currentDay = currentDay.AddDate(0, 0, 1)
+			// Free time within the user's working hours
+			if workHours := config.WorkWindows(currentDay.Weekday()); len(workHours) > 0 {
+				free := reporter.FreeMinutes(append(allDay, timed...), currentDay, workHours)
+				fmt.Printf("*Free: %.1f of %.1f working hours*\n\n",
+					float64(free)/60.0,
+					float64(config.WorkMinutes(currentDay.Weekday()))/60.0)
+			}
+
+			currentDay = nextDay
 		}
 
 		return nil
@@ -262,10 +276,10 @@ Examples:
 			return fmt.Errorf("--user is required")
 		}
 
-		now := time.Now()
+		now := userNow()
 		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-		yesterday := today.Add(-24 * time.Hour)
-		tomorrow := today.Add(24 * time.Hour)
+		yesterday := today.AddDate(0, 0, -1)
+		tomorrow := today.AddDate(0, 0, 1)
 
 		// Create filter for yesterday + today
 		filter := calendar.NewFilter()
@@ -278,6 +292,8 @@ Examples:
 			return fmt.Errorf("error listing eventos: %w", err)
 		}
 
+		localizeEntries(entries)
+
 		// Generar reporte
 		fmt.Println("# REPORT: YESTERDAY + TODAY")
 		fmt.Println()
@@ -289,19 +305,11 @@ Examples:
 			return nil
 		}
 
-		// Separate events by day
-		var yesterdayEvents []*calendar.Entry
-		var todayEvents []*calendar.Entry
-
-		for _, entry := range entries {
-			// Normalize to same location for comparison
-			entryDate := time.Date(entry.DateTime.Year(), entry.DateTime.Month(), entry.DateTime.Day(), 0, 0, 0, 0, now.Location())
-			if entryDate.Equal(yesterday) {
-				yesterdayEvents = append(yesterdayEvents, entry)
-			} else if entryDate.Equal(today) {
-				todayEvents = append(todayEvents, entry)
-			}
-		}
+		// Separate events by day (multi-day events appear on both days)
+		yesterdayAllDay, yesterdayTimed := eventsForDay(entries, yesterday, today)
+		todayAllDay, todayTimed := eventsForDay(entries, today, tomorrow)
+		yesterdayEvents := append(yesterdayAllDay, yesterdayTimed...)
+		todayEvents := append(todayAllDay, todayTimed...)
 
 		// Show events de ayer
 		fmt.Printf("## YESTERDAY (%s)\n\n", yesterday.Format("Monday, 02 Jan 2006"))
@@ -309,9 +317,10 @@ Examples:
 			fmt.Println("No events")
 		} else {
 			for _, event := range yesterdayEvents {
-				fmt.Printf("- [%s] %s", event.DateTime.Format("15:04"), event.Title)
-				if event.Duration > 0 {
-					fmt.Printf(" (%d min)", event.Duration)
+				if event.AllDay {
+					fmt.Printf("- [all day] %s", event.Title)
+				} else {
+					fmt.Printf("- [%s] %s (%d min)", formatDayTime(event, yesterday), event.Title, event.Minutes())
 				}
 				fmt.Printf(" [ID: %s]", event.ID)
 				if event.Location != "" {
@@ -328,9 +337,10 @@ Examples:
 			fmt.Println("No events")
 		} else {
 			for _, event := range todayEvents {
-				fmt.Printf("- [%s] %s", event.DateTime.Format("15:04"), event.Title)
-				if event.Duration > 0 {
-					fmt.Printf(" (%d min)", event.Duration)
+				if event.AllDay {
+					fmt.Printf("- [all day] %s", event.Title)
+				} else {
+					fmt.Printf("- [%s] %s (%d min)", formatDayTime(event, today), event.Title, event.Minutes())
 				}
 				fmt.Printf(" [ID: %s]", event.ID)
 				if event.Location != "" {
@@ -351,6 +361,31 @@ Examples:
 	},
 }
 
+// eventsForDay retorna los eventos que se superponen con [dayStart, dayEnd),
+// separando los de día completo (o que ocupan todo el día) de los que tienen horario
+func eventsForDay(events []*calendar.Entry, dayStart, dayEnd time.Time) (allDay, timed []*calendar.Entry) {
+	for _, event := range events {
+		if !event.Overlaps(dayStart, dayEnd) {
+			continue
+		}
+		if event.AllDay || event.CoversDay(dayStart, dayEnd) {
+			allDay = append(allDay, event)
+		} else {
+			timed = append(timed, event)
+		}
+	}
+	return allDay, timed
+}
+
+// formatDayTime formatea la hora de un evento dentro del día dayStart; si
+// comenzó un día anterior muestra hasta qué hora dura
+func formatDayTime(event *calendar.Entry, dayStart time.Time) string {
+	if event.DateTime.Before(dayStart) {
+		return "→ " + event.EndTime().In(dayStart.Location()).Format("15:04")
+	}
+	return event.DateTime.Format("15:04")
+}
+
 func init() {
 	// daily-report
 	dailyReportCmd.Flags().StringVar(&dailyReportDate, "date", "", "Report date (YYYY-MM-DD, default: today)")
@@ -3,6 +3,8 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/sebasvalencia/clical/internal/config"
 	"github.com/sebasvalencia/clical/pkg/storage"
@@ -25,7 +27,8 @@ var rootCmd = &cobra.Command{
 designed for AI assistance.
 
 Data is stored in Markdown + JSON files, organized by date,
-which allows manual navigation and editing of events.`,
+which allows manual navigation and editing of events. Set
+CLICAL_STORAGE=sqlite to use an embedded SQLite database instead.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		// Cargar configuración desde archivo
 		var err error
@@ -50,17 +53,45 @@ which allows manual navigation and editing of events.`,
 		}
 
 		// Inicializar storage
-		store, err = storage.NewFilesystemStorage(cfg.DataDir)
+		store, err = storage.New(cfg.Storage, cfg.DataDir)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
 			os.Exit(1)
 		}
+		store = storage.WithTrash(store, cfg.DataDir, time.Duration(cfg.TrashDays)*24*time.Hour)
+		command := "clical " + strings.Join(os.Args[1:], " ")
+		store = storage.WithHistory(store, cfg.DataDir, command, cfg.Actor)
+		if cfg.Git {
+			store = storage.WithGit(store, cfg.DataDir, command, cfg.Actor, cfg.LockTimeout)
+		}
+		store = storage.WithLocking(store, cfg.DataDir, cfg.LockTimeout)
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		return closeStore()
 	},
 }
 
 // Execute ejecuta el comando raíz
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	// PersistentPostRunE no se ejecuta si el comando falla
+	if closeErr := closeStore(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// closeStore cierra el storage (con SQLite, la base y su WAL) una sola vez
+func closeStore() error {
+	if store == nil {
+		return nil
+	}
+	err := storage.Close(store)
+	store = nil
+	if err != nil {
+		return fmt.Errorf("error closing storage: %w", err)
+	}
+	return nil
 }
 
 func init() {
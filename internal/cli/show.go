@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/sebasvalencia/clical/pkg/calendar"
 	"github.com/spf13/cobra"
 )
 
@@ -31,25 +32,48 @@ Examples:
 			return fmt.Errorf("error getting event: %w", err)
 		}
 
+		localizeEntries([]*calendar.Entry{entry})
+
 		// Show complete details
 		fmt.Printf("═══════════════════════════════════════════\n")
 		fmt.Printf(" %s\n", entry.Title)
 		fmt.Printf("═══════════════════════════════════════════\n\n")
 
 		fmt.Printf("ID:        %s\n", entry.ID)
-		fmt.Printf("Fecha:     %s\n", entry.DateTime.Format("2006-01-02"))
-		fmt.Printf("Hora:      %s\n", entry.DateTime.Format("15:04"))
-		fmt.Printf("Duration:  %d minutes\n", entry.Duration)
-		fmt.Printf("Fin:       %s\n", entry.EndTime().Format("15:04"))
+		if entry.AllDay {
+			fmt.Printf("Fecha:     %s\n", formatAllDay(entry))
+			fmt.Printf("Duration:  %s\n", formatDuration(entry))
+		} else {
+			fmt.Printf("Fecha:     %s\n", entry.DateTime.Format("2006-01-02"))
+			fmt.Printf("Hora:      %s\n", entry.DateTime.Format("15:04"))
+			fmt.Printf("Duration:  %s\n", formatDuration(entry))
+			if entry.IsMultiDay() {
+				fmt.Printf("Fin:       %s\n", entry.EndTime().In(entry.DateTime.Location()).Format("2006-01-02 15:04"))
+			} else {
+				fmt.Printf("Fin:       %s\n", entry.EndTime().Format("15:04"))
+			}
+		}
+
+		if entry.TZID != "" && entry.TZID != userLocation().String() {
+			fmt.Printf("Zona:      %s (%s)\n", entry.TZID, entry.LocalDateTime().Format("2006-01-02 15:04"))
+		}
 
 		if entry.Location != "" {
 			fmt.Printf("Ubicación: %s\n", entry.Location)
 		}
 
+		if entry.RRule != nil {
+			fmt.Printf("Repite:    %s\n", entry.RRule.String())
+		}
+
 		if len(entry.Tags) > 0 {
 			fmt.Printf("Tags:      #%s\n", strings.Join(entry.Tags, " #"))
 		}
 
+		if len(entry.Reminders) > 0 {
+			fmt.Printf("Avisos:    %s antes\n", calendar.FormatReminders(entry.Reminders))
+		}
+
 		if entry.Notes != "" {
 			fmt.Printf("\nNotas:\n%s\n", entry.Notes)
 		}
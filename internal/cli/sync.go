@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/sebasvalencia/clical/pkg/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncDryRun bool
+	syncPrefer string
+	syncRemote string
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Apply hand edits made to the Markdown event files, or sync with a git remote",
+	Long: `Reconcile each event's Markdown file with its JSON file.
+
+Events are saved twice: a .md file meant to be read and edited by hand and a
+.json file that clical reads. After editing .md files in an editor, run sync
+to apply the changes:
+
+  - only the .md changed: the event is updated from it
+  - only the .json changed: the .md is regenerated
+  - both changed: reported as a conflict and left untouched
+    (use --prefer=md or --prefer=json to pick a side)
+
+The ID and the occurrence of an override identify the file and cannot be
+edited. Only available with the filesystem storage backend.
+
+With --remote (requires CLICAL_GIT=true) the data directory is instead
+pulled from and pushed to a git remote, for example a bare repository shared
+by several machines. Pending changes are committed first. The first time, give
+the path or URL of the repository as --remote=PATH (with the "="; a bare
+--remote means origin); it is saved as the 'origin' remote. Since every event
+is its own file, most changes merge cleanly; when the same file changed on
+both sides:
+
+  - an event keeps the version with the latest update (its .md is regenerated)
+  - an alarm file keeps the alarms of both sides
+  - a file deleted on one side and changed on the other is kept
+  - anything else keeps the local version
+
+Examples:
+  clical sync --user=12345
+  clical sync --user=12345 --dry-run
+  clical sync --user=12345 --prefer=md
+  clical sync --remote=/srv/git/clical-data.git
+  clical sync --remote`,
+	Args: syncArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("remote") {
+			return syncWithRemote()
+		}
+
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+
+		syncer, ok := store.(storage.MarkdownSyncer)
+		if !ok {
+			return fmt.Errorf("sync is only available with the filesystem storage backend")
+		}
+
+		result, err := syncer.SyncMarkdown(userID, storage.SyncOptions{DryRun: syncDryRun, Prefer: syncPrefer})
+		if err != nil {
+			return fmt.Errorf("error syncing: %w", err)
+		}
+
+		for _, item := range result.Imported {
+			fmt.Printf("✓ imported     %s\n", item.Path)
+		}
+		for _, item := range result.Regenerated {
+			fmt.Printf("✓ regenerated  %s\n", item.Path)
+		}
+		for _, item := range result.Conflicts {
+			fmt.Printf("⚠ conflict     %s (both the .md and the .json changed)\n", item.Path)
+		}
+		for _, item := range result.Invalid {
+			fmt.Printf("✗ invalid      %s: %v\n", item.Path, item.Err)
+		}
+
+		fmt.Printf("\n%d imported, %d regenerated, %d conflicts, %d invalid, %d unchanged\n",
+			len(result.Imported), len(result.Regenerated), len(result.Conflicts), len(result.Invalid), result.Unchanged)
+
+		if syncDryRun {
+			fmt.Println("Dry run: no files were changed")
+		}
+
+		if len(result.Conflicts) > 0 || len(result.Invalid) > 0 {
+			return fmt.Errorf("some files were not synced")
+		}
+		return nil
+	},
+}
+
+// syncArgs rechaza argumentos posicionales. Como --remote puede ir sin valor,
+// "--remote /ruta" deja la ruta como argumento y sincronizaría con origin en
+// silencio; el remoto tiene que ir como --remote=/ruta.
+func syncArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	if cmd.Flags().Changed("remote") {
+		return fmt.Errorf("unexpected argument %q: give the remote as --remote=%s", args[0], args[0])
+	}
+	return fmt.Errorf("sync takes no arguments, got %q", args[0])
+}
+
+// syncWithRemote hace pull y push del directorio de datos con el remoto de git
+func syncWithRemote() error {
+	versioned, err := gitVersioned()
+	if err != nil {
+		return err
+	}
+
+	result, err := versioned.SyncRemote(syncRemote)
+	if err != nil {
+		return fmt.Errorf("error syncing with remote: %w", err)
+	}
+
+	for _, r := range result.Resolved {
+		fmt.Printf("⚠ conflict     %s (kept %s)\n", r.Path, r.Kept)
+	}
+	fmt.Printf("✓ Synced %s with %s: %d commit(s) pulled, %d pushed\n", result.Branch, result.Remote, result.Pulled, result.Pushed)
+	return nil
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Only report what would change")
+	syncCmd.Flags().StringVar(&syncPrefer, "prefer", "", "Resolve conflicts keeping md or json")
+	syncCmd.Flags().StringVar(&syncRemote, "remote", "", "Pull from and push to a git remote (name, path or URL; default: origin)")
+	syncCmd.Flags().Lookup("remote").NoOptDefVal = "origin"
+
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trashJSON      bool
+	trashRestoreID string
+	trashOlderThan int
+	trashForce     bool
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted events and cancelled alarms",
+	Long: `Deleted events and cancelled alarms are moved to a per-user trash instead
+of being removed. Items are purged automatically after CLICAL_TRASH_DAYS days
+(default 30; 0 keeps them until purged by hand) whenever clical changes data
+or runs 'alarm check'.`,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the trash",
+	Long: `List deleted events and cancelled alarms, oldest first.
+
+Examples:
+  clical trash list --user=12345
+  clical trash list --user=12345 --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+
+		bin, err := trashBin()
+		if err != nil {
+			return err
+		}
+
+		items, err := bin.ListTrash(userID)
+		if err != nil {
+			return fmt.Errorf("error listing trash: %w", err)
+		}
+
+		if trashJSON {
+			if items == nil {
+				items = []*storage.TrashItem{}
+			}
+			jsonData, err := json.MarshalIndent(items, "", "  ")
+			if err != nil {
+				return fmt.Errorf("error serializing trash: %w", err)
+			}
+			fmt.Println(string(jsonData))
+			return nil
+		}
+
+		if len(items) == 0 {
+			fmt.Println("Trash is empty")
+			return nil
+		}
+
+		loc := userLocation()
+		fmt.Printf("%d item(s) in trash\n\n", len(items))
+		for _, item := range items {
+			detail := ""
+			if item.Entry != nil {
+				detail = formatWithZone(item.Entry)
+			} else if item.Alarm != nil {
+				detail = fmt.Sprintf("%s %s", item.Recurrence, strings.TrimSuffix(item.Filename, ".json"))
+			}
+			fmt.Printf("[%s] %-5s %s (%s) [ID: %s]\n",
+				item.DeletedAt.In(loc).Format("2006-01-02 15:04"), item.Kind, item.Title(), detail, item.ID)
+		}
+
+		return nil
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore an event or alarm from the trash",
+	Long: `Restore a deleted event (with its occurrence overrides) or a cancelled
+alarm, by its event or alarm ID.
+
+Examples:
+  clical trash restore --user=12345 --id=abc123def456
+  clical trash restore --user=12345 --id=alarm_once_1234567890_abcd1234`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+		if trashRestoreID == "" {
+			return fmt.Errorf("--id is required")
+		}
+
+		bin, err := trashBin()
+		if err != nil {
+			return err
+		}
+
+		item, err := bin.RestoreTrash(userID, trashRestoreID)
+		if err != nil {
+			return fmt.Errorf("error restoring: %w", err)
+		}
+
+		fmt.Printf("✓ Restored %s: %s\n", item.Kind, item.Title())
+		return nil
+	},
+}
+
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently delete items from the trash",
+	Long: `Permanently delete every item in the trash, or only those deleted more
+than --older-than days ago.
+
+Examples:
+  clical trash purge --user=12345
+  clical trash purge --user=12345 --older-than=7 --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userID == "" {
+			return fmt.Errorf("--user is required")
+		}
+		if trashOlderThan < 0 {
+			return fmt.Errorf("--older-than must be 0 or more days")
+		}
+
+		bin, err := trashBin()
+		if err != nil {
+			return err
+		}
+
+		if !trashForce {
+			fmt.Printf("¿Eliminar definitivamente los elementos de la papelera? (s/N): ")
+			reader := bufio.NewReader(os.Stdin)
+			response, err := reader.ReadString('\n')
+			if err != nil {
+				return fmt.Errorf("error reading respuesta: %w", err)
+			}
+
+			response = strings.ToLower(strings.TrimSpace(response))
+			if response != "s" && response != "si" && response != "sí" {
+				fmt.Println("Operación cancelada")
+				return nil
+			}
+		}
+
+		before := time.Now().AddDate(0, 0, -trashOlderThan)
+		purged, err := bin.PurgeTrash(userID, before)
+		if err != nil {
+			return fmt.Errorf("error purging trash: %w", err)
+		}
+
+		fmt.Printf("✓ Purged %d item(s)\n", purged)
+		return nil
+	},
+}
+
+// trashBin retorna el storage como TrashBin
+func trashBin() (storage.TrashBin, error) {
+	bin, ok := store.(storage.TrashBin)
+	if !ok {
+		return nil, fmt.Errorf("trash is not available")
+	}
+	return bin, nil
+}
+
+func init() {
+	trashListCmd.Flags().BoolVar(&trashJSON, "json", false, "Output in JSON format")
+
+	trashRestoreCmd.Flags().StringVar(&trashRestoreID, "id", "", "ID of the event or alarm to restore")
+
+	trashPurgeCmd.Flags().IntVar(&trashOlderThan, "older-than", 0, "Only purge items deleted more than this many days ago")
+	trashPurgeCmd.Flags().BoolVar(&trashForce, "force", false, "Purge without confirmation")
+
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashPurgeCmd)
+	rootCmd.AddCommand(trashCmd)
+}
@@ -1,7 +1,12 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/sebasvalencia/clical/pkg/user"
 	"github.com/spf13/cobra"
@@ -131,11 +136,410 @@ Examples:
 			firstDay = "Monday"
 		}
 		fmt.Printf("  Primer día semana:    %s\n", firstDay)
+		fmt.Println()
+		printWorkSchedule(u.Config)
 
 		return nil
 	},
 }
 
+// user config
+var userConfigID string
+
+// Opciones de jornada laboral, compartidas por user config y user edit
+var (
+	userWorkStart string
+	userWorkEnd   string
+	userWorkdays  string
+	userLunch     string
+	userDays      []string
+	userBuffer    int
+)
+
+var userConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Ver o modificar la jornada laboral de un usuario",
+	Long: `Muestra o modifica la jornada laboral del usuario, usada por los reportes
+(tiempo libre) y por find-slot.
+
+La jornada por defecto (--work-start/--work-end) aplica a los días laborables
+(--workdays). --day reemplaza la jornada de un día puntual, con una o más
+franjas separadas por coma (turno partido) u "off" para un día no laborable;
+"default" vuelve a la jornada por defecto. El almuerzo (--lunch) se descuenta
+de todos los días.
+
+Examples:
+  clical user config --id=12345
+  clical user config --id=12345 --work-start=09:00 --work-end=18:00 --workdays=mon,tue,wed,thu,fri
+  clical user config --id=12345 --day=fri=09:00-14:00 --lunch=13:00-14:00
+  clical user config --id=12345 --day=sat=09:00-13:00,15:00-19:00 --day=sun=off
+  clical user config --id=12345 --lunch=off --buffer=10`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userConfigID == "" {
+			return fmt.Errorf("--id is required")
+		}
+
+		u, err := store.GetUser(userConfigID)
+		if err != nil {
+			return fmt.Errorf("error getting user: %w", err)
+		}
+
+		changed, err := applyScheduleFlags(cmd, &u.Config)
+		if err != nil {
+			return err
+		}
+
+		// Sin cambios, solo mostrar la configuración
+		if !changed {
+			printWorkSchedule(u.Config)
+			return nil
+		}
+
+		if err := u.Validate(); err != nil {
+			return fmt.Errorf("configuración inválida: %w", err)
+		}
+		if err := store.SaveUser(u); err != nil {
+			return fmt.Errorf("error saving user: %w", err)
+		}
+
+		fmt.Printf("✓ Configuration updated\n\n")
+		printWorkSchedule(u.Config)
+
+		return nil
+	},
+}
+
+// addScheduleFlags registra las opciones de jornada laboral en cmd
+func addScheduleFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&userWorkStart, "work-start", "", "Inicio de la jornada (HH:MM)")
+	cmd.Flags().StringVar(&userWorkEnd, "work-end", "", "Fin de la jornada (HH:MM)")
+	cmd.Flags().StringVar(&userWorkdays, "workdays", "", "Días laborables separados por coma (eg: mon,tue,wed,thu,fri)")
+	cmd.Flags().StringVar(&userLunch, "lunch", "", "Almuerzo (HH:MM-HH:MM u off)")
+	cmd.Flags().StringArrayVar(&userDays, "day", nil, "Jornada de un día: DAY=HH:MM-HH:MM[,HH:MM-HH:MM], DAY=off o DAY=default (repetible)")
+	cmd.Flags().IntVar(&userBuffer, "buffer", 0, "Minutos libres entre reuniones")
+}
+
+// applyScheduleFlags aplica a config las opciones de jornada indicadas.
+// Retorna si se modificó alguna.
+func applyScheduleFlags(cmd *cobra.Command, config *user.UserConfig) (bool, error) {
+	flags := cmd.Flags()
+	changed := false
+
+	if flags.Changed("work-start") {
+		config.WorkStart = userWorkStart
+		changed = true
+	}
+	if flags.Changed("work-end") {
+		config.WorkEnd = userWorkEnd
+		changed = true
+	}
+	if flags.Changed("workdays") {
+		config.Workdays = nil
+		for _, name := range strings.Split(userWorkdays, ",") {
+			day, err := user.ParseWeekday(name)
+			if err != nil {
+				return false, err
+			}
+			config.Workdays = append(config.Workdays, user.WeekdayName(day))
+		}
+		changed = true
+	}
+	if flags.Changed("lunch") {
+		config.LunchStart, config.LunchEnd = "", ""
+		if userLunch != user.DayOff {
+			w, err := user.ParseWindow(userLunch)
+			if err != nil {
+				return false, fmt.Errorf("invalid --lunch: %w", err)
+			}
+			config.LunchStart, config.LunchEnd = user.FormatClock(w.Start), user.FormatClock(w.End)
+		}
+		changed = true
+	}
+	for _, spec := range userDays {
+		name, hours, ok := strings.Cut(spec, "=")
+		if !ok {
+			return false, fmt.Errorf("invalid --day %q (use DAY=HH:MM-HH:MM, DAY=off or DAY=default)", spec)
+		}
+		day, err := user.ParseWeekday(name)
+		if err != nil {
+			return false, err
+		}
+		key := user.WeekdayName(day)
+		changed = true
+
+		if hours == "default" {
+			delete(config.Schedule, key)
+			continue
+		}
+		windows, err := user.ParseWindows(hours)
+		if err != nil {
+			return false, fmt.Errorf("invalid --day %q: %w", spec, err)
+		}
+		if config.Schedule == nil {
+			config.Schedule = make(map[string]string)
+		}
+		config.Schedule[key] = user.FormatWindows(windows)
+	}
+	if flags.Changed("buffer") {
+		config.BufferMinutes = userBuffer
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// user edit
+var (
+	userEditID              string
+	userEditName            string
+	userEditTimezone        string
+	userEditDefaultDuration int
+	userEditDateFormat      string
+	userEditTimeFormat      string
+	userEditFirstDay        string
+)
+
+var userEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Editar un usuario",
+	Long: `Modifica el nombre, la zona horaria o la configuración de un usuario.
+Solo se cambian los campos indicados.
+
+También acepta las opciones de jornada laboral de "user config".
+
+Examples:
+  clical user edit --id=12345 --name="Juan P. Pérez"
+  clical user edit --id=12345 --timezone="Europe/Madrid"
+  clical user edit --id=12345 --default-duration=30 --first-day=sunday
+  clical user edit --id=12345 --date-format="02/01/2006" --time-format="3:04PM"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userEditID == "" {
+			return fmt.Errorf("--id is required")
+		}
+
+		u, err := store.GetUser(userEditID)
+		if err != nil {
+			return fmt.Errorf("error getting user: %w", err)
+		}
+
+		flags := cmd.Flags()
+		changed, err := applyScheduleFlags(cmd, &u.Config)
+		if err != nil {
+			return err
+		}
+
+		if flags.Changed("name") {
+			u.Name = userEditName
+			changed = true
+		}
+		if flags.Changed("timezone") {
+			u.Timezone = userEditTimezone
+			changed = true
+		}
+		if flags.Changed("default-duration") {
+			u.Config.DefaultDuration = userEditDefaultDuration
+			changed = true
+		}
+		if flags.Changed("date-format") {
+			if userEditDateFormat == "" {
+				return fmt.Errorf("--date-format cannot be empty")
+			}
+			u.Config.DateFormat = userEditDateFormat
+			changed = true
+		}
+		if flags.Changed("time-format") {
+			if userEditTimeFormat == "" {
+				return fmt.Errorf("--time-format cannot be empty")
+			}
+			u.Config.TimeFormat = userEditTimeFormat
+			changed = true
+		}
+		if flags.Changed("first-day") {
+			day, err := user.ParseWeekday(userEditFirstDay)
+			if err != nil {
+				return fmt.Errorf("invalid --first-day: %w", err)
+			}
+			u.Config.FirstDayOfWeek = int(day)
+			changed = true
+		}
+
+		if !changed {
+			return fmt.Errorf("nothing to change (see --help)")
+		}
+
+		if err := u.Validate(); err != nil {
+			return fmt.Errorf("usuario inválido: %w", err)
+		}
+		if err := store.SaveUser(u); err != nil {
+			return fmt.Errorf("error saving user: %w", err)
+		}
+
+		fmt.Printf("✓ User updated successfully\n\n")
+		fmt.Printf("ID:       %s\n", u.ID)
+		fmt.Printf("Nombre:   %s\n", u.Name)
+		fmt.Printf("Timezone: %s\n", u.Timezone)
+
+		return nil
+	},
+}
+
+// user delete
+var (
+	userDeleteID      string
+	userDeleteForce   bool
+	userDeleteArchive string
+)
+
+var userDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Eliminar un usuario y todos sus datos",
+	Long: `Elimina un usuario con todos sus eventos, alarmas y estado.
+
+Con --archive, antes de eliminar se guarda un .tar.gz con todos los datos del
+usuario: sin valor se crea user-ID-FECHA.tar.gz en el directorio actual; si el
+valor es un directorio, el archivo se crea dentro de él.
+
+Examples:
+  clical user delete --id=12345
+  clical user delete --id=12345 --archive
+  clical user delete --id=12345 --archive=/backups --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userDeleteID == "" {
+			return fmt.Errorf("--id is required")
+		}
+
+		u, err := store.GetUser(userDeleteID)
+		if err != nil {
+			return fmt.Errorf("error getting user: %w", err)
+		}
+
+		entries, err := store.ListEntries(u.ID, nil)
+		if err != nil {
+			return fmt.Errorf("error listing events: %w", err)
+		}
+		alarms, err := store.ListActiveAlarms(u.ID)
+		if err != nil {
+			return fmt.Errorf("error listing alarms: %w", err)
+		}
+
+		fmt.Printf("Usuario a eliminar:\n")
+		fmt.Printf("  %s - %s\n", u.ID, u.Name)
+		fmt.Printf("  %d evento(s), %d alarma(s) activa(s)\n", len(entries), len(alarms))
+
+		if !userDeleteForce {
+			fmt.Printf("\n¿Está seguro que desea eliminar este usuario y todos sus datos? (s/N): ")
+			reader := bufio.NewReader(os.Stdin)
+			response, err := reader.ReadString('\n')
+			if err != nil {
+				return fmt.Errorf("error reading respuesta: %w", err)
+			}
+
+			response = strings.ToLower(strings.TrimSpace(response))
+			if response != "s" && response != "si" && response != "sí" {
+				fmt.Println("Operación cancelada")
+				return nil
+			}
+		}
+
+		if cmd.Flags().Changed("archive") {
+			path, err := archiveUser(u.ID, userDeleteArchive)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✓ Data archived to %s\n", path)
+		}
+
+		if err := store.DeleteUser(u.ID); err != nil {
+			return fmt.Errorf("error deleting user: %w", err)
+		}
+
+		fmt.Println("✓ User deleted successfully")
+
+		return nil
+	},
+}
+
+// archiveUser guarda los datos del usuario en un .tar.gz y retorna su ruta.
+// Si dest es un directorio, el archivo se crea dentro con un nombre por defecto.
+func archiveUser(id, dest string) (string, error) {
+	path := dest
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		name := fmt.Sprintf("user-%s-%s.tar.gz", id, time.Now().Format("20060102-150405"))
+		path = filepath.Join(dest, name)
+	}
+
+	// No sobrescribir archivos existentes
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", fmt.Errorf("error creating archive: %w", err)
+	}
+
+	if err := store.ArchiveUser(id, f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("error writing archive: %w", err)
+	}
+
+	return path, nil
+}
+
+// user rename-id
+var (
+	userRenameID    string
+	userRenameNewID string
+)
+
+var userRenameIDCmd = &cobra.Command{
+	Use:   "rename-id",
+	Short: "Cambiar el ID de un usuario",
+	Long: `Cambia el ID de un usuario, moviendo todos sus eventos, alarmas y estado.
+
+El cambio es atómico: si algo falla, el usuario queda con su ID original.
+
+Examples:
+  clical user rename-id --id=12345 --new-id=67890`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if userRenameID == "" {
+			return fmt.Errorf("--id is required")
+		}
+		if userRenameNewID == "" {
+			return fmt.Errorf("--new-id is required")
+		}
+		if userRenameID == userRenameNewID {
+			return fmt.Errorf("--new-id must be different from --id")
+		}
+
+		if err := store.RenameUser(userRenameID, userRenameNewID); err != nil {
+			return fmt.Errorf("error renaming user: %w", err)
+		}
+
+		fmt.Printf("✓ User %s renamed to %s\n", userRenameID, userRenameNewID)
+		if cfg != nil && cfg.UserID == userRenameID {
+			fmt.Printf("\nNote: the default user (CLICAL_USER_ID) is still %s; update your configuration\n", userRenameID)
+		}
+
+		return nil
+	},
+}
+
+// printWorkSchedule muestra la jornada laboral de cada día de la semana,
+// comenzando por el primer día de la semana del usuario
+func printWorkSchedule(config user.UserConfig) {
+	fmt.Printf("Jornada laboral:\n")
+	for i := 0; i < 7; i++ {
+		day := time.Weekday((config.FirstDayOfWeek + i) % 7)
+		fmt.Printf("  %s  %s\n", day.String()[:3], strings.ReplaceAll(user.FormatWindows(config.WorkWindows(day)), ",", ", "))
+	}
+	if config.LunchStart != "" {
+		fmt.Printf("  Almuerzo:  %s-%s\n", config.LunchStart, config.LunchEnd)
+	}
+	fmt.Printf("  Buffer entre reuniones: %d minutes\n", config.BufferMinutes)
+}
+
 func init() {
 	// user add
 	userAddCmd.Flags().StringVar(&userAddID, "id", "", "ID del usuario")
@@ -149,8 +553,41 @@ func init() {
 	userShowCmd.Flags().StringVar(&userShowID, "id", "", "ID del usuario")
 	userShowCmd.MarkFlagRequired("id")
 
+	// user config
+	userConfigCmd.Flags().StringVar(&userConfigID, "id", "", "ID del usuario")
+	addScheduleFlags(userConfigCmd)
+	userConfigCmd.MarkFlagRequired("id")
+
+	// user edit
+	userEditCmd.Flags().StringVar(&userEditID, "id", "", "ID del usuario")
+	userEditCmd.Flags().StringVar(&userEditName, "name", "", "Nuevo nombre")
+	userEditCmd.Flags().StringVar(&userEditTimezone, "timezone", "", "Nueva timezone (eg: America/Argentina/Buenos_Aires)")
+	userEditCmd.Flags().IntVar(&userEditDefaultDuration, "default-duration", 0, "Duración por defecto de los eventos (minutos)")
+	userEditCmd.Flags().StringVar(&userEditDateFormat, "date-format", "", "Formato de fecha (layout de Go, eg: 2006-01-02)")
+	userEditCmd.Flags().StringVar(&userEditTimeFormat, "time-format", "", "Formato de hora (layout de Go, eg: 15:04)")
+	userEditCmd.Flags().StringVar(&userEditFirstDay, "first-day", "", "Primer día de la semana (eg: monday, sunday)")
+	addScheduleFlags(userEditCmd)
+	userEditCmd.MarkFlagRequired("id")
+
+	// user delete
+	userDeleteCmd.Flags().StringVar(&userDeleteID, "id", "", "ID del usuario")
+	userDeleteCmd.Flags().BoolVar(&userDeleteForce, "force", false, "Eliminar sin confirmación")
+	userDeleteCmd.Flags().StringVar(&userDeleteArchive, "archive", "", "Guardar un .tar.gz con los datos antes de eliminar (archivo o directorio)")
+	userDeleteCmd.Flags().Lookup("archive").NoOptDefVal = "."
+	userDeleteCmd.MarkFlagRequired("id")
+
+	// user rename-id
+	userRenameIDCmd.Flags().StringVar(&userRenameID, "id", "", "ID actual del usuario")
+	userRenameIDCmd.Flags().StringVar(&userRenameNewID, "new-id", "", "Nuevo ID")
+	userRenameIDCmd.MarkFlagRequired("id")
+	userRenameIDCmd.MarkFlagRequired("new-id")
+
 	// Agregar subcomandos a user
 	userCmd.AddCommand(userAddCmd)
 	userCmd.AddCommand(userListCmd)
 	userCmd.AddCommand(userShowCmd)
+	userCmd.AddCommand(userConfigCmd)
+	userCmd.AddCommand(userEditCmd)
+	userCmd.AddCommand(userDeleteCmd)
+	userCmd.AddCommand(userRenameIDCmd)
 }
@@ -4,8 +4,11 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	osuser "os/user"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Config contiene la configuración global de la aplicación
@@ -13,6 +16,21 @@ type Config struct {
 	DataDir  string
 	UserID   string // Usuario por defecto si no se especifica
 	LogLevel string
+	Storage  string // Backend de almacenamiento: "fs" o "sqlite"
+	// LockTimeout es cuánto se espera el lock de un usuario ocupado por otro
+	// proceso de clical antes de fallar
+	LockTimeout time.Duration
+	// Actor identifica quién hace los cambios en el historial de eventos
+	// (por ejemplo el nombre de un agente de IA); por defecto el usuario del sistema
+	Actor string
+	// TrashDays es cuántos días se guardan los eventos eliminados y las
+	// alarmas canceladas en la papelera (0: no se purgan automáticamente)
+	TrashDays int
+	// Git hace un commit de git en el directorio de datos por cada cambio
+	Git bool
+	// AlarmNag es cada cuánto alarm check vuelve a entregar las alarmas
+	// disparadas que no se confirmaron con alarm ack (0: no se repiten)
+	AlarmNag time.Duration
 }
 
 // DefaultConfig retorna la configuración por defecto
@@ -21,7 +39,20 @@ func DefaultConfig() *Config {
 		DataDir:  DefaultDataDir(),
 		UserID:   "",
 		LogLevel: "info",
+		Storage:  "fs",
+
+		LockTimeout: 10 * time.Second,
+		Actor:       defaultActor(),
+		TrashDays:   30,
+	}
+}
+
+// defaultActor retorna el nombre del usuario del sistema, o "" si no se puede obtener
+func defaultActor() string {
+	if u, err := osuser.Current(); err == nil {
+		return u.Username
 	}
+	return ""
 }
 
 // DefaultDataDir retorna el directorio de datos por defecto (~/.clical/data),
@@ -71,9 +102,93 @@ func LoadConfig(configPath string) (*Config, error) {
 		cfg.LogLevel = logLevel
 	}
 
+	if backend := os.Getenv("CLICAL_STORAGE"); backend != "" {
+		cfg.Storage = backend
+	}
+
+	if actor := os.Getenv("CLICAL_ACTOR"); actor != "" {
+		cfg.Actor = actor
+	}
+
+	if days := os.Getenv("CLICAL_TRASH_DAYS"); days != "" {
+		if err := setTrashDays(cfg, days); err != nil {
+			return nil, err
+		}
+	}
+
+	if git := os.Getenv("CLICAL_GIT"); git != "" {
+		if err := setGit(cfg, git); err != nil {
+			return nil, err
+		}
+	}
+
+	if timeout := os.Getenv("CLICAL_LOCK_TIMEOUT"); timeout != "" {
+		if err := setLockTimeout(cfg, timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	if nag := os.Getenv("CLICAL_ALARM_NAG"); nag != "" {
+		if err := setAlarmNag(cfg, nag); err != nil {
+			return nil, err
+		}
+	}
+
+	// Git versiona archivos de texto: una base SQLite se commitearía como un
+	// binario opaco cuyos conflictos de merge no se pueden resolver
+	if cfg.Git && cfg.Storage == "sqlite" {
+		return nil, fmt.Errorf("CLICAL_GIT no es compatible con CLICAL_STORAGE=sqlite: use el backend fs para versionar con git")
+	}
+
 	return cfg, nil
 }
 
+// setLockTimeout parsea un timeout de lock ("30s", "2m", "0" para no esperar)
+func setLockTimeout(cfg *Config, value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("CLICAL_LOCK_TIMEOUT inválido %q: %w", value, err)
+	}
+	if d < 0 {
+		return fmt.Errorf("CLICAL_LOCK_TIMEOUT no puede ser negativo: %s", value)
+	}
+	cfg.LockTimeout = d
+	return nil
+}
+
+// setTrashDays parsea la retención de la papelera en días
+func setTrashDays(cfg *Config, value string) error {
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return fmt.Errorf("CLICAL_TRASH_DAYS inválido %q: debe ser un número de días", value)
+	}
+	cfg.TrashDays = days
+	return nil
+}
+
+// setGit parsea si se versiona el directorio de datos con git ("true", "1", ...)
+func setGit(cfg *Config, value string) error {
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("CLICAL_GIT inválido %q: use true o false", value)
+	}
+	cfg.Git = enabled
+	return nil
+}
+
+// setAlarmNag parsea el intervalo de re-entrega de alarmas sin confirmar ("10m", "0" para no repetirlas)
+func setAlarmNag(cfg *Config, value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("CLICAL_ALARM_NAG inválido %q: %w", value, err)
+	}
+	if d != 0 && d < time.Minute {
+		return fmt.Errorf("CLICAL_ALARM_NAG debe ser de al menos 1m: %s", value)
+	}
+	cfg.AlarmNag = d
+	return nil
+}
+
 // loadFromFile carga configuración desde archivo .env
 func loadFromFile(cfg *Config, path string) error {
 	file, err := os.Open(path)
@@ -114,6 +229,38 @@ func loadFromFile(cfg *Config, path string) error {
 			if value != "" {
 				cfg.LogLevel = value
 			}
+		case "CLICAL_STORAGE":
+			if value != "" {
+				cfg.Storage = value
+			}
+		case "CLICAL_ACTOR":
+			if value != "" {
+				cfg.Actor = value
+			}
+		case "CLICAL_TRASH_DAYS":
+			if value != "" {
+				if err := setTrashDays(cfg, value); err != nil {
+					return err
+				}
+			}
+		case "CLICAL_GIT":
+			if value != "" {
+				if err := setGit(cfg, value); err != nil {
+					return err
+				}
+			}
+		case "CLICAL_LOCK_TIMEOUT":
+			if value != "" {
+				if err := setLockTimeout(cfg, value); err != nil {
+					return err
+				}
+			}
+		case "CLICAL_ALARM_NAG":
+			if value != "" {
+				if err := setAlarmNag(cfg, value); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
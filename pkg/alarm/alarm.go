@@ -16,7 +16,21 @@ type Alarm struct {
 	ExpiresAt    *time.Time    `json:"expires_at,omitempty"`
 	ScheduledFor time.Time     `json:"scheduled_for,omitempty"` // Solo para output
 	ExecutedAt   *time.Time    `json:"executed_at,omitempty"`   // Solo para past alarms
-	Schedule     *ScheduleInfo `json:"-"`                        // Metadata, no serializado
+	Event        *EventRef     `json:"event,omitempty"`         // Solo para recordatorios de eventos
+	SnoozedFrom  string        `json:"snoozed_from,omitempty"`  // ID de la alarma pospuesta (alarm snooze)
+	DeliveredAt  *time.Time    `json:"delivered_at,omitempty"`  // Última entrega sin confirmar (alarm ack)
+	Deliveries   int           `json:"deliveries,omitempty"`    // Entregas sin confirmar
+	Schedule     *ScheduleInfo `json:"-"`                       // Metadata, no serializado
+}
+
+// EventRef identifica el evento de un recordatorio
+type EventRef struct {
+	ID       string    `json:"id"`
+	Title    string    `json:"title"`
+	Location string    `json:"location,omitempty"`
+	Start    time.Time `json:"start"`
+	// Reminder es la anticipación del recordatorio, en minutos
+	Reminder int `json:"reminder"`
 }
 
 // ScheduleInfo contiene información de scheduling para alarmas recurrentes
@@ -124,6 +138,8 @@ func (a *Alarm) Clone() *Alarm {
 		CreatedAt:   a.CreatedAt,
 		Recurrence:  a.Recurrence,
 		ScheduledFor: a.ScheduledFor,
+		SnoozedFrom:  a.SnoozedFrom,
+		Deliveries:   a.Deliveries,
 	}
 
 	if a.ExpiresAt != nil {
@@ -136,5 +152,30 @@ func (a *Alarm) Clone() *Alarm {
 		clone.ExecutedAt = &executedAt
 	}
 
+	if a.Event != nil {
+		event := *a.Event
+		clone.Event = &event
+	}
+
+	if a.DeliveredAt != nil {
+		deliveredAt := *a.DeliveredAt
+		clone.DeliveredAt = &deliveredAt
+	}
+
 	return clone
 }
+
+// Snooze crea la alarma one-time que repite una alarma disparada. Queda
+// vinculada a la alarma original aunque se posponga varias veces.
+func (a *Alarm) Snooze() *Alarm {
+	snoozed := NewAlarm(a.Context, RecurrenceOnce)
+	snoozed.SnoozedFrom = a.ID
+	if a.SnoozedFrom != "" {
+		snoozed.SnoozedFrom = a.SnoozedFrom
+	}
+	if a.Event != nil {
+		event := *a.Event
+		snoozed.Event = &event
+	}
+	return snoozed
+}
@@ -311,6 +311,30 @@ func TestClone(t *testing.T) {
 	}
 }
 
+func TestSnooze(t *testing.T) {
+	original := NewAlarm("tomar medicación", RecurrenceDaily)
+	original.Event = &EventRef{ID: "abc", Title: "Dentista"}
+
+	snoozed := original.Snooze()
+	if snoozed.Recurrence != RecurrenceOnce || snoozed.Context != original.Context {
+		t.Errorf("Unexpected snoozed alarm: %+v", snoozed)
+	}
+	if snoozed.ID == original.ID || snoozed.SnoozedFrom != original.ID {
+		t.Errorf("Expected new ID linked to %s, got %s from %s", original.ID, snoozed.ID, snoozed.SnoozedFrom)
+	}
+	if snoozed.Event == original.Event || snoozed.Event.ID != "abc" {
+		t.Error("Expected a copy of the event reference")
+	}
+	if err := snoozed.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	// Posponer otra vez mantiene el vínculo con la alarma original
+	if again := snoozed.Snooze(); again.SnoozedFrom != original.ID {
+		t.Errorf("Expected link to %s, got %s", original.ID, again.SnoozedFrom)
+	}
+}
+
 // Helper function to create time pointer
 func ptrTime(t time.Time) *time.Time {
 	return &t
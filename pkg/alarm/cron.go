@@ -0,0 +1,223 @@
+package alarm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros son las abreviaturas de cron y su expresión de 5 campos
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField describe uno de los 5 campos de una expresión cron
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // nombres aceptados en lugar de números (desde min)
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// cronFilenameReplacer y cronExprReplacer convierten una expresión cron en
+// nombre de archivo y viceversa (los nombres de meses y días no usan x, ~ ni _)
+var (
+	cronFilenameReplacer = strings.NewReplacer(" ", "_", "*", "x", "/", "~")
+	cronExprReplacer     = strings.NewReplacer("_", " ", "x", "*", "~", "/")
+)
+
+// CronSchedule representa un horario cron estándar de 5 campos:
+// minuto hora día-del-mes mes día-de-la-semana.
+// Soporta *, listas (1,15), rangos (1-5), pasos (*/15, 9-17/2), nombres de
+// meses y días (jan, mon-fri) y las abreviaturas @hourly, @daily, @weekly,
+// @monthly y @yearly. Como en cron, si se restringen tanto el día del mes
+// como el de la semana, alcanza con que se cumpla uno de los dos.
+type CronSchedule struct {
+	expr                          string // expresión normalizada
+	minute, hour, dom, month, dow uint64 // bit i: el valor i está permitido
+	domAny, dowAny                bool   // el campo empieza con "*"
+}
+
+// ParseCron parsea una expresión cron de 5 campos
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	if macro, ok := cronMacros[expr]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: must have 5 fields (minute hour day-of-month month day-of-week)", expr)
+	}
+
+	s := &CronSchedule{
+		expr:   strings.Join(fields, " "),
+		domAny: strings.HasPrefix(fields[2], "*"),
+		dowAny: strings.HasPrefix(fields[4], "*"),
+	}
+	bits := [5]*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		*bits[i] = b
+	}
+
+	// 7 también es domingo
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	if s.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never matches a date", expr)
+	}
+
+	return s, nil
+}
+
+// parseCronField parsea un campo y retorna los valores permitidos como bits
+func parseCronField(f string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(f, ",") {
+		rng, step := part, 1
+		hasStep := false
+		if i := strings.Index(part, "/"); i >= 0 {
+			rng = part[:i]
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in %s: %s", field.name, part)
+			}
+			step, hasStep = n, true
+		}
+
+		var lo, hi int
+		switch {
+		case rng == "*":
+			lo, hi = field.min, field.max
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = parseCronValue(bounds[0], field); err != nil {
+				return 0, err
+			}
+			if hi, err = parseCronValue(bounds[1], field); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range in %s: %s", field.name, rng)
+			}
+		default:
+			var err error
+			if lo, err = parseCronValue(rng, field); err != nil {
+				return 0, err
+			}
+			hi = lo
+			// "5/15" es desde 5 hasta el final de a 15
+			if hasStep {
+				hi = field.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseCronValue parsea un número o nombre de un campo
+func parseCronValue(s string, field cronField) (int, error) {
+	for i, name := range field.names {
+		if s == name {
+			return field.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < field.min || v > field.max {
+		return 0, fmt.Errorf("invalid %s: %s (must be %d-%d)", field.name, s, field.min, field.max)
+	}
+	return v, nil
+}
+
+// ParseCronFilename parsea el nombre de archivo de una alarma cron
+func ParseCronFilename(filename string) (*CronSchedule, error) {
+	return ParseCron(cronExprReplacer.Replace(strings.TrimSuffix(filename, ".json")))
+}
+
+// String retorna la expresión normalizada
+func (s *CronSchedule) String() string {
+	return s.expr
+}
+
+// Filename retorna el nombre de archivo para una alarma cron: la expresión
+// con "_" entre campos, "x" por "*" y "~" por "/"
+// Formato: 0_9_x_x_1-5.json ("0 9 * * 1-5")
+func (s *CronSchedule) Filename() string {
+	return cronFilenameReplacer.Replace(s.expr) + ".json"
+}
+
+// Matches indica si el horario se cumple en el minuto de t
+func (s *CronSchedule) Matches(t time.Time) bool {
+	return s.minute&(1<<uint(t.Minute())) != 0 &&
+		s.hour&(1<<uint(t.Hour())) != 0 &&
+		s.month&(1<<uint(t.Month())) != 0 &&
+		s.dayMatches(t)
+}
+
+// dayMatches aplica la regla de cron para día del mes y día de la semana
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next retorna el primer minuto posterior a after en que se cumple el
+// horario (en la zona horaria de after), o el tiempo cero si no hay
+// ninguno en los próximos 5 años
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := RoundToMinute(after).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	// advance evita quedarse en el lugar si time.Date cae en un cambio de horario
+	advance := func(next time.Time) time.Time {
+		if next.After(t) {
+			return next
+		}
+		return t.Add(time.Minute)
+	}
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = advance(time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+		case !s.dayMatches(t):
+			t = advance(time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,102 @@
+package alarm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"too few fields", "0 9 * *"},
+		{"too many fields", "0 9 * * * *"},
+		{"minute out of range", "60 9 * * *"},
+		{"hour out of range", "0 24 * * *"},
+		{"day of month zero", "0 9 0 * *"},
+		{"invalid step", "*/0 * * * *"},
+		{"reversed range", "0 17-9 * * *"},
+		{"unknown name", "0 9 * * foo"},
+		{"never matches", "0 9 31 2 *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCron(tt.expr); err == nil {
+				t.Errorf("ParseCron(%q) expected error", tt.expr)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// 2025-12-19 es viernes
+	base := time.Date(2025, 12, 19, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"weekdays skips weekend", "0 9 * * 1-5", base, time.Date(2025, 12, 22, 9, 0, 0, 0, time.UTC)},
+		{"weekday names", "0 9 * * mon-fri", base.Add(-time.Minute), base},
+		{"every 15 minutes", "*/15 * * * *", base.Add(time.Minute), time.Date(2025, 12, 19, 9, 15, 0, 0, time.UTC)},
+		{"step from offset", "5/20 * * * *", base, time.Date(2025, 12, 19, 9, 5, 0, 0, time.UTC)},
+		{"hour range with list", "30 8,18 * * *", base, time.Date(2025, 12, 19, 18, 30, 0, 0, time.UTC)},
+		{"7 is sunday", "0 10 * * 7", base, time.Date(2025, 12, 21, 10, 0, 0, 0, time.UTC)},
+		{"month name", "0 0 1 mar *", base, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"yearly macro", "@yearly", base, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 12 29 2 *", base, time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)},
+		// Con día del mes y de la semana restringidos alcanza con uno
+		{"day of month or week", "0 9 1 * sun", base, time.Date(2025, 12, 21, 9, 0, 0, 0, time.UTC)},
+		{"day of month or week (dom)", "0 9 20 * mon", base, time.Date(2025, 12, 20, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+			}
+			if got := s.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+			if !s.Matches(tt.want) {
+				t.Errorf("Matches(%v) = false", tt.want)
+			}
+		})
+	}
+}
+
+func TestCronScheduleFilename(t *testing.T) {
+	tests := []struct {
+		expr     string
+		want     string
+		wantExpr string
+	}{
+		{"0 9 * * 1-5", "0_9_x_x_1-5.json", "0 9 * * 1-5"},
+		{"*/15  9-17 * * MON-FRI", "x~15_9-17_x_x_mon-fri.json", "*/15 9-17 * * mon-fri"},
+		{"@daily", "0_0_x_x_x.json", "0 0 * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Filename(); got != tt.want {
+				t.Errorf("Filename() = %v, want %v", got, tt.want)
+			}
+			parsed, err := ParseCronFilename(s.Filename())
+			if err != nil {
+				t.Fatalf("ParseCronFilename() error = %v", err)
+			}
+			if parsed.String() != tt.wantExpr {
+				t.Errorf("ParseCronFilename() = %v, want %v", parsed, tt.wantExpr)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package alarm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeWindow es una franja horaria diaria, de Start a End inclusive
+type TimeWindow struct {
+	Start DailySchedule
+	End   DailySchedule
+}
+
+// ParseTimeWindow parsea una franja "HH:MM-HH:MM"
+func ParseTimeWindow(s string) (*TimeWindow, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid window %q: expected HH:MM-HH:MM", s)
+	}
+
+	var bounds [2]DailySchedule
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid window %q: expected HH:MM-HH:MM", s)
+		}
+		bounds[i] = DailySchedule{Hour: t.Hour(), Minute: t.Minute()}
+	}
+
+	w := &TimeWindow{Start: bounds[0], End: bounds[1]}
+	if w.End.minutes() < w.Start.minutes() {
+		return nil, fmt.Errorf("invalid window %q: end must not be before start", s)
+	}
+	return w, nil
+}
+
+// String retorna la franja en formato HH:MM-HH:MM
+func (w TimeWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start.Hour, w.Start.Minute, w.End.Hour, w.End.Minute)
+}
+
+// minutes retorna los minutos desde la medianoche
+func (d DailySchedule) minutes() int {
+	return d.Hour*60 + d.Minute
+}
+
+// IntervalSchedule representa una alarma que se repite cada Every a partir
+// de Start. Sin Window se repite sin interrupciones (Start, Start+Every, ...).
+// Con Window la serie se reinicia cada día al inicio de la franja y se
+// ejecuta mientras no pase del final (08:00, 10:00, ... 18:00), sin
+// ejecuciones anteriores a Start.
+type IntervalSchedule struct {
+	Start  time.Time // al minuto; su zona horaria es la del horario
+	Every  time.Duration
+	Window *TimeWindow
+}
+
+// NewIntervalSchedule valida y crea un horario por intervalo
+func NewIntervalSchedule(start time.Time, every time.Duration, window *TimeWindow) (*IntervalSchedule, error) {
+	if every < time.Minute || every%time.Minute != 0 {
+		return nil, fmt.Errorf("invalid interval %s: must be a whole number of minutes", every)
+	}
+	return &IntervalSchedule{Start: RoundToMinute(start), Every: every, Window: window}, nil
+}
+
+// ParseIntervalFilename parsea el nombre de archivo de una alarma por intervalo.
+// El inicio y la franja se interpretan en loc (la zona del usuario).
+func ParseIntervalFilename(filename string, loc *time.Location) (*IntervalSchedule, error) {
+	parts := strings.Split(strings.TrimSuffix(filename, ".json"), "_")
+	if (len(parts) != 4 && len(parts) != 6) || parts[2] != "every" {
+		return nil, fmt.Errorf("invalid interval filename: %s", filename)
+	}
+
+	start, err := time.ParseInLocation("2006-01-02_15-04", parts[0]+"_"+parts[1], loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval filename: %s", filename)
+	}
+	every, err := time.ParseDuration(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval filename: %s", filename)
+	}
+
+	var window *TimeWindow
+	if len(parts) == 6 {
+		window, err = ParseTimeWindow(strings.ReplaceAll(parts[4], "-", ":") + "-" + strings.ReplaceAll(parts[5], "-", ":"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval filename: %s", filename)
+		}
+	}
+
+	return NewIntervalSchedule(start, every, window)
+}
+
+// Filename retorna el nombre de archivo para una alarma por intervalo
+// Formato: 2025-12-22_08-00_every_90m.json
+// Con franja: 2025-12-22_08-00_every_120m_08-00_18-00.json
+func (s *IntervalSchedule) Filename() string {
+	name := fmt.Sprintf("%s_every_%dm", s.Start.Format("2006-01-02_15-04"), int(s.Every/time.Minute))
+	if s.Window != nil {
+		name += fmt.Sprintf("_%02d-%02d_%02d-%02d", s.Window.Start.Hour, s.Window.Start.Minute, s.Window.End.Hour, s.Window.End.Minute)
+	}
+	return name + ".json"
+}
+
+// String retorna una descripción legible del horario
+func (s *IntervalSchedule) String() string {
+	desc := fmt.Sprintf("every %s from %s", formatInterval(s.Every), s.Start.Format("2006-01-02 15:04"))
+	if s.Window != nil {
+		desc += fmt.Sprintf(", daily %s", s.Window)
+	}
+	return desc
+}
+
+// formatInterval formatea un intervalo sin unidades en cero (2h, 1h30m, 45m)
+func formatInterval(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+// Next retorna la primera ejecución posterior a after, en la zona horaria de
+// Start (la franja es diaria en esa zona, sea cual sea la de after)
+func (s *IntervalSchedule) Next(after time.Time) time.Time {
+	loc := s.Start.Location()
+	start := s.Start
+	after = after.In(loc)
+
+	if s.Window == nil {
+		if after.Before(start) {
+			return start
+		}
+		k := after.Sub(start)/s.Every + 1
+		return start.Add(k * s.Every)
+	}
+
+	// Con franja: la primera ejecución posterior a from en cada día, desde
+	// el día de from (siempre hay una en los dos primeros días)
+	from := after
+	if from.Before(start) {
+		from = start.Add(-time.Nanosecond)
+	}
+	for day := 0; day < 2; day++ {
+		base := time.Date(from.Year(), from.Month(), from.Day()+day, s.Window.Start.Hour, s.Window.Start.Minute, 0, 0, loc)
+		end := time.Date(from.Year(), from.Month(), from.Day()+day, s.Window.End.Hour, s.Window.End.Minute, 0, 0, loc)
+
+		t := base
+		if !from.Before(base) {
+			t = base.Add((from.Sub(base)/s.Every + 1) * s.Every)
+		}
+		if !t.After(end) {
+			return t
+		}
+	}
+	return time.Time{}
+}
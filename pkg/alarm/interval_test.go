@@ -0,0 +1,127 @@
+package alarm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntervalScheduleNext(t *testing.T) {
+	start := time.Date(2025, 12, 22, 8, 0, 0, 0, time.UTC)
+	window, err := ParseTimeWindow("08:00-18:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		every  time.Duration
+		window *TimeWindow
+		after  time.Time
+		want   time.Time
+	}{
+		{"before start", 90 * time.Minute, nil, start.Add(-time.Hour), start},
+		{"at start", 90 * time.Minute, nil, start, time.Date(2025, 12, 22, 9, 30, 0, 0, time.UTC)},
+		{"continues overnight", 90 * time.Minute, nil, time.Date(2025, 12, 22, 23, 0, 0, 0, time.UTC), time.Date(2025, 12, 23, 0, 30, 0, 0, time.UTC)},
+		{"inside window", 2 * time.Hour, window, time.Date(2025, 12, 22, 11, 0, 0, 0, time.UTC), time.Date(2025, 12, 22, 12, 0, 0, 0, time.UTC)},
+		{"last of the day", 2 * time.Hour, window, time.Date(2025, 12, 22, 17, 0, 0, 0, time.UTC), time.Date(2025, 12, 22, 18, 0, 0, 0, time.UTC)},
+		{"restarts next day", 2 * time.Hour, window, time.Date(2025, 12, 22, 18, 0, 0, 0, time.UTC), time.Date(2025, 12, 23, 8, 0, 0, 0, time.UTC)},
+		{"before window", 2 * time.Hour, window, time.Date(2025, 12, 23, 6, 0, 0, 0, time.UTC), time.Date(2025, 12, 23, 8, 0, 0, 0, time.UTC)},
+		{"uneven interval restarts", 7 * time.Hour, window, time.Date(2025, 12, 22, 15, 0, 0, 0, time.UTC), time.Date(2025, 12, 23, 8, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewIntervalSchedule(start, tt.every, tt.window)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+		})
+	}
+
+	// Con franja no hay ejecuciones antes de Start aunque caigan en la franja
+	late, _ := NewIntervalSchedule(time.Date(2025, 12, 22, 11, 0, 0, 0, time.UTC), 2*time.Hour, window)
+	if got, want := late.Next(start), time.Date(2025, 12, 22, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next() antes de Start = %v, want %v", got, want)
+	}
+}
+
+func TestIntervalScheduleLocation(t *testing.T) {
+	// El nombre de archivo está en la zona del usuario, distinta de la del
+	// proceso y de la de after
+	loc, err := time.LoadLocation("Pacific/Kiritimati")
+	if err != nil {
+		t.Skip(err)
+	}
+	s, err := ParseIntervalFilename("2025-12-22_08-00_every_120m_08-00_18-00.json", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 12, 22, 8, 0, 0, 0, loc); !s.Start.Equal(want) {
+		t.Errorf("Start = %v, want %v", s.Start, want)
+	}
+
+	// Las 9:00 del usuario son las 19:00 UTC del día anterior
+	after := time.Date(2025, 12, 21, 19, 0, 0, 0, time.UTC)
+	if got, want := s.Next(after), time.Date(2025, 12, 22, 10, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", after, got, want)
+	}
+	// Las 18:00 del usuario son las 04:00 UTC: la próxima es a las 8:00 del día siguiente
+	after = time.Date(2025, 12, 22, 4, 0, 0, 0, time.UTC)
+	if got, want := s.Next(after), time.Date(2025, 12, 23, 8, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", after, got, want)
+	}
+}
+
+func TestIntervalScheduleFilename(t *testing.T) {
+	start := time.Date(2025, 12, 22, 8, 0, 0, 0, time.Local)
+	window, _ := ParseTimeWindow("08:00-18:30")
+
+	tests := []struct {
+		name   string
+		every  time.Duration
+		window *TimeWindow
+		want   string
+	}{
+		{"without window", 90 * time.Minute, nil, "2025-12-22_08-00_every_90m.json"},
+		{"with window", 2 * time.Hour, window, "2025-12-22_08-00_every_120m_08-00_18-30.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewIntervalSchedule(start, tt.every, tt.window)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Filename(); got != tt.want {
+				t.Errorf("Filename() = %v, want %v", got, tt.want)
+			}
+			parsed, err := ParseIntervalFilename(tt.want, time.Local)
+			if err != nil {
+				t.Fatalf("ParseIntervalFilename() error = %v", err)
+			}
+			if parsed.String() != s.String() {
+				t.Errorf("ParseIntervalFilename() = %v, want %v", parsed, s)
+			}
+		})
+	}
+}
+
+func TestIntervalScheduleErrors(t *testing.T) {
+	if _, err := NewIntervalSchedule(time.Now(), 30*time.Second, nil); err == nil {
+		t.Error("NewIntervalSchedule(30s) expected error")
+	}
+	if _, err := NewIntervalSchedule(time.Now(), 90*time.Second, nil); err == nil {
+		t.Error("NewIntervalSchedule(90s) expected error")
+	}
+	for _, w := range []string{"18:00-08:00", "08:00", "8-18", "08:00-25:00"} {
+		if _, err := ParseTimeWindow(w); err == nil {
+			t.Errorf("ParseTimeWindow(%q) expected error", w)
+		}
+	}
+	if _, err := ParseIntervalFilename("0_9_x_x_1-5.json", time.Local); err == nil {
+		t.Error("ParseIntervalFilename() de un archivo cron expected error")
+	}
+}
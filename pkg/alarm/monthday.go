@@ -0,0 +1,336 @@
+package alarm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Valores especiales de MonthlySchedule.Day y YearlySchedule.Day
+const (
+	LastDay         = -1 // último día del mes
+	LastBusinessDay = -2 // último día hábil (lunes a viernes) del mes
+)
+
+// LastWeek en MonthlySchedule.Nth y YearlySchedule.Nth indica el último
+// día de la semana del mes ("last friday")
+const LastWeek = -1
+
+var ordinals = []string{"1st", "2nd", "3rd", "4th", "5th"}
+
+// monthDay es el día del mes en que se ejecuta una alarma mensual o anual:
+// un día fijo (opcionalmente ajustado al fin de mes), el último día, el
+// último día hábil o el enésimo día de la semana
+type monthDay struct {
+	day     int          // 1-31, LastDay o LastBusinessDay; 0 si se usa nth
+	clamp   bool         // con day 29-31: en meses más cortos, el último día
+	nth     int          // 1-5 o LastWeek, con weekday
+	weekday time.Weekday // con nth
+}
+
+// dayIn retorna el día en que se ejecuta en el mes dado, o 0 si ese mes no
+// tiene ejecución (día 31 sin clamp en un mes de 30 días, 5º martes...)
+func (md monthDay) dayIn(year int, month time.Month) int {
+	days := daysIn(year, month)
+
+	switch {
+	case md.nth > 0:
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()
+		day := 1 + (int(md.weekday)-int(first)+7)%7 + 7*(md.nth-1)
+		if day > days {
+			return 0
+		}
+		return day
+	case md.nth == LastWeek:
+		last := time.Date(year, month, days, 0, 0, 0, 0, time.UTC).Weekday()
+		return days - (int(last)-int(md.weekday)+7)%7
+	case md.day == LastDay:
+		return days
+	case md.day == LastBusinessDay:
+		day := days
+		for {
+			switch time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() {
+			case time.Saturday, time.Sunday:
+				day--
+				continue
+			}
+			return day
+		}
+	case md.day > days:
+		if md.clamp {
+			return days
+		}
+		return 0
+	default:
+		return md.day
+	}
+}
+
+// daysIn retorna la cantidad de días del mes
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// filenamePart retorna la parte del nombre de archivo que describe el día:
+// 15, 31-clamp, last-day, last-weekday, 2nd-tuesday o last-friday
+func (md monthDay) filenamePart() string {
+	switch {
+	case md.nth > 0:
+		return ordinals[md.nth-1] + "-" + strings.ToLower(md.weekday.String())
+	case md.nth == LastWeek:
+		return "last-" + strings.ToLower(md.weekday.String())
+	case md.day == LastDay:
+		return "last-day"
+	case md.day == LastBusinessDay:
+		return "last-weekday"
+	case md.clamp:
+		return fmt.Sprintf("%02d-clamp", md.day)
+	default:
+		return fmt.Sprintf("%02d", md.day)
+	}
+}
+
+// String retorna una descripción legible del día
+func (md monthDay) String() string {
+	switch {
+	case md.nth > 0:
+		return ordinals[md.nth-1] + " " + md.weekday.String()
+	case md.nth == LastWeek:
+		return "last " + md.weekday.String()
+	case md.day == LastDay:
+		return "last day"
+	case md.day == LastBusinessDay:
+		return "last weekday"
+	case md.clamp:
+		return fmt.Sprintf("%d (or last day)", md.day)
+	default:
+		return strconv.Itoa(md.day)
+	}
+}
+
+// parseMonthDay parsea la descripción de un día del mes, en palabras
+// ("2nd tuesday") o en el formato de filenamePart ("2nd-tuesday")
+func parseMonthDay(words []string, clamp bool) (monthDay, error) {
+	invalid := fmt.Errorf("invalid day %q: must be 1-31, 'last', 'last weekday', '2nd tuesday' or 'last friday'", strings.Join(words, " "))
+	if len(words) == 0 {
+		return monthDay{}, invalid
+	}
+
+	switch len(words) {
+	case 1:
+		if words[0] == "last" {
+			return monthDay{day: LastDay}, nil
+		}
+		day, err := strconv.Atoi(words[0])
+		if err != nil || day < 1 || day > 31 {
+			return monthDay{}, invalid
+		}
+		return monthDay{day: day, clamp: clamp && day > 28}, nil
+
+	case 2:
+		if words[1] == "clamp" {
+			return parseMonthDay(words[:1], true)
+		}
+		if words[0] == "last" {
+			switch words[1] {
+			case "day":
+				return monthDay{day: LastDay}, nil
+			case "weekday":
+				return monthDay{day: LastBusinessDay}, nil
+			}
+		}
+
+		weekday, err := ParseWeekday(words[1])
+		if err != nil {
+			return monthDay{}, invalid
+		}
+		if words[0] == "last" {
+			return monthDay{nth: LastWeek, weekday: weekday}, nil
+		}
+		for i, ord := range ordinals {
+			if words[0] == ord {
+				return monthDay{nth: i + 1, weekday: weekday}, nil
+			}
+		}
+	}
+
+	return monthDay{}, invalid
+}
+
+// monthDayFilenameParts retorna las partes de nombre de archivo de todos los
+// días que se ejecutan en la fecha de t (el día fijo primero)
+func monthDayFilenameParts(t time.Time) []string {
+	year, month, day := t.Date()
+	days := daysIn(year, month)
+
+	parts := []string{fmt.Sprintf("%02d", day)}
+	if day == days {
+		parts = append(parts, "last-day")
+		// Días 29-31 ajustados al fin de un mes que no los tiene (o que sí)
+		for d := max(day, 29); d <= 31; d++ {
+			parts = append(parts, fmt.Sprintf("%02d-clamp", d))
+		}
+	} else if day >= 29 {
+		parts = append(parts, fmt.Sprintf("%02d-clamp", day))
+	}
+	if (monthDay{day: LastBusinessDay}).dayIn(year, month) == day {
+		parts = append(parts, "last-weekday")
+	}
+
+	weekday := strings.ToLower(t.Weekday().String())
+	parts = append(parts, ordinals[(day-1)/7]+"-"+weekday)
+	if day+7 > days {
+		parts = append(parts, "last-"+weekday)
+	}
+	return parts
+}
+
+// nextMonthDay retorna la primera ejecución posterior a after de un día del
+// mes a la hora dada, probando desde month del año de after y avanzando de a
+// step meses (1 para mensuales, 12 para anuales), o el tiempo cero si no hay
+// ninguna en los próximos 8 años
+func nextMonthDay(md monthDay, hour, minute int, after time.Time, month time.Month, step int) time.Time {
+	loc := after.Location()
+	for i := 0; i <= 8*12/step; i++ {
+		first := time.Date(after.Year(), month+time.Month(i*step), 1, 0, 0, 0, 0, loc)
+		day := md.dayIn(first.Year(), first.Month())
+		if day == 0 {
+			continue
+		}
+		if t := time.Date(first.Year(), first.Month(), day, hour, minute, 0, 0, loc); t.After(after) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseDaySchedule parsea "<día> HH:MM", donde el día es el de parseMonthDay
+func parseDaySchedule(s string, clamp bool) (md monthDay, hour, minute int, err error) {
+	words := strings.Fields(strings.ToLower(s))
+	if len(words) < 2 {
+		return monthDay{}, 0, 0, fmt.Errorf("invalid schedule %q: expected DAY HH:MM", s)
+	}
+
+	t, err := time.Parse("15:04", words[len(words)-1])
+	if err != nil {
+		return monthDay{}, 0, 0, fmt.Errorf("invalid time %q: expected HH:MM", words[len(words)-1])
+	}
+
+	md, err = parseMonthDay(words[:len(words)-1], clamp)
+	if err != nil {
+		return monthDay{}, 0, 0, err
+	}
+	return md, t.Hour(), t.Minute(), nil
+}
+
+// ParseMonthlySchedule parsea un horario mensual "DAY HH:MM", donde DAY es
+// un día 1-31, "last" (último día), "last weekday" (último día hábil),
+// "2nd tuesday" (1st-5th) o "last friday". Con clamp, los días 29-31 se
+// ejecutan el último día de los meses que no los tienen.
+func ParseMonthlySchedule(s string, clamp bool) (MonthlySchedule, error) {
+	md, hour, minute, err := parseDaySchedule(s, clamp)
+	if err != nil {
+		return MonthlySchedule{}, err
+	}
+	return MonthlySchedule{Day: md.day, Clamp: md.clamp, Nth: md.nth, Weekday: md.weekday, Hour: hour, Minute: minute}, nil
+}
+
+// ParseYearlySchedule parsea un horario anual "MM-DD HH:MM" o "MM DAY HH:MM",
+// con DAY como en ParseMonthlySchedule ("11 4th thursday 10:00"). Con clamp,
+// un día que el mes no tiene (02-29) se ejecuta su último día.
+func ParseYearlySchedule(s string, clamp bool) (YearlySchedule, error) {
+	s = strings.TrimSpace(s)
+	monthStr, rest, _ := strings.Cut(s, " ")
+	if m, d, ok := strings.Cut(monthStr, "-"); ok {
+		monthStr, rest = m, d+" "+rest
+	}
+
+	month, err := strconv.Atoi(monthStr)
+	if err != nil || month < 1 || month > 12 {
+		return YearlySchedule{}, fmt.Errorf("invalid month: %s (must be 1-12)", monthStr)
+	}
+
+	md, hour, minute, err := parseDaySchedule(rest, clamp)
+	if err != nil {
+		return YearlySchedule{}, err
+	}
+
+	y := YearlySchedule{Month: time.Month(month), Day: md.day, Clamp: md.clamp, Nth: md.nth, Weekday: md.weekday, Hour: hour, Minute: minute}
+	if y.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Minute)).IsZero() {
+		return YearlySchedule{}, fmt.Errorf("invalid date: %02d-%d never exists (use --clamp for the last day of the month)", month, md.day)
+	}
+	return y, nil
+}
+
+// ParseMonthlyFilename parsea el nombre de archivo de una alarma mensual
+// Formato: 15_14-30-00.json, 2nd-tuesday_14-30-00.json, last-day_14-30-00.json
+func ParseMonthlyFilename(filename string) (MonthlySchedule, error) {
+	dayPart, timePart, ok := strings.Cut(strings.TrimSuffix(filename, ".json"), "_")
+	if !ok {
+		return MonthlySchedule{}, fmt.Errorf("invalid monthly filename: %s", filename)
+	}
+	hour, minute, err := parseFilenameTime(timePart)
+	if err != nil {
+		return MonthlySchedule{}, fmt.Errorf("invalid monthly filename: %s", filename)
+	}
+	md, err := parseMonthDay(strings.Split(dayPart, "-"), false)
+	if err != nil {
+		return MonthlySchedule{}, fmt.Errorf("invalid monthly filename: %s", filename)
+	}
+	return MonthlySchedule{Day: md.day, Clamp: md.clamp, Nth: md.nth, Weekday: md.weekday, Hour: hour, Minute: minute}, nil
+}
+
+// ParseYearlyFilename parsea el nombre de archivo de una alarma anual
+// Formato: 11-21_14-30-00.json, 11-4th-thursday_10-00-00.json
+func ParseYearlyFilename(filename string) (YearlySchedule, error) {
+	datePart, timePart, ok := strings.Cut(strings.TrimSuffix(filename, ".json"), "_")
+	if !ok {
+		return YearlySchedule{}, fmt.Errorf("invalid yearly filename: %s", filename)
+	}
+	monthStr, dayPart, ok := strings.Cut(datePart, "-")
+	month, err := strconv.Atoi(monthStr)
+	if !ok || err != nil || month < 1 || month > 12 {
+		return YearlySchedule{}, fmt.Errorf("invalid yearly filename: %s", filename)
+	}
+	hour, minute, err := parseFilenameTime(timePart)
+	if err != nil {
+		return YearlySchedule{}, fmt.Errorf("invalid yearly filename: %s", filename)
+	}
+	md, err := parseMonthDay(strings.Split(dayPart, "-"), false)
+	if err != nil {
+		return YearlySchedule{}, fmt.Errorf("invalid yearly filename: %s", filename)
+	}
+	return YearlySchedule{Month: time.Month(month), Day: md.day, Clamp: md.clamp, Nth: md.nth, Weekday: md.weekday, Hour: hour, Minute: minute}, nil
+}
+
+// parseFilenameTime parsea la hora de un nombre de archivo (14-30-00)
+func parseFilenameTime(s string) (hour, minute int, err error) {
+	t, err := time.Parse("15-04-05", s)
+	if err != nil {
+		return 0, 0, err
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// MonthlyFilenames retorna los nombres de archivo de todas las alarmas
+// mensuales que se ejecutan en t: el de CurrentMonthlyFilename primero y
+// luego los de último día, día ajustado, último día hábil y día de la semana
+func MonthlyFilenames(t time.Time) []string {
+	suffix := fmt.Sprintf("_%02d-%02d-00.json", t.Hour(), t.Minute())
+	var filenames []string
+	for _, part := range monthDayFilenameParts(t) {
+		filenames = append(filenames, part+suffix)
+	}
+	return filenames
+}
+
+// YearlyFilenames es como MonthlyFilenames para las alarmas anuales
+func YearlyFilenames(t time.Time) []string {
+	prefix := fmt.Sprintf("%02d-", t.Month())
+	var filenames []string
+	for _, filename := range MonthlyFilenames(t) {
+		filenames = append(filenames, prefix+filename)
+	}
+	return filenames
+}
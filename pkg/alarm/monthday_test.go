@@ -0,0 +1,165 @@
+package alarm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMonthlySchedule(t *testing.T) {
+	tests := []struct {
+		input    string
+		clamp    bool
+		filename string
+		next     time.Time // después del 2025-12-01 00:00
+	}{
+		{"15 14:30", false, "15_14-30-00.json", time.Date(2025, 12, 15, 14, 30, 0, 0, time.UTC)},
+		{"31 09:00", false, "31_09-00-00.json", time.Date(2025, 12, 31, 9, 0, 0, 0, time.UTC)},
+		{"30 09:00", true, "30-clamp_09-00-00.json", time.Date(2025, 12, 30, 9, 0, 0, 0, time.UTC)},
+		{"10 09:00", true, "10_09-00-00.json", time.Date(2025, 12, 10, 9, 0, 0, 0, time.UTC)},
+		{"last 18:00", false, "last-day_18-00-00.json", time.Date(2025, 12, 31, 18, 0, 0, 0, time.UTC)},
+		{"last day 18:00", false, "last-day_18-00-00.json", time.Date(2025, 12, 31, 18, 0, 0, 0, time.UTC)},
+		{"last weekday 17:00", false, "last-weekday_17-00-00.json", time.Date(2025, 12, 31, 17, 0, 0, 0, time.UTC)},
+		{"2nd Tuesday 10:00", false, "2nd-tuesday_10-00-00.json", time.Date(2025, 12, 9, 10, 0, 0, 0, time.UTC)},
+		{"last friday 08:00", false, "last-friday_08-00-00.json", time.Date(2025, 12, 26, 8, 0, 0, 0, time.UTC)},
+		{"5th monday 08:00", false, "5th-monday_08-00-00.json", time.Date(2025, 12, 29, 8, 0, 0, 0, time.UTC)},
+	}
+
+	after := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			m, err := ParseMonthlySchedule(tt.input, tt.clamp)
+			if err != nil {
+				t.Fatalf("ParseMonthlySchedule() error = %v", err)
+			}
+			if got := m.Filename(); got != tt.filename {
+				t.Errorf("Filename() = %v, want %v", got, tt.filename)
+			}
+			if got := m.Next(after); !got.Equal(tt.next) {
+				t.Errorf("Next() = %v, want %v", got, tt.next)
+			}
+			parsed, err := ParseMonthlyFilename(tt.filename)
+			if err != nil || parsed != m {
+				t.Errorf("ParseMonthlyFilename() = %+v, %v; want %+v", parsed, err, m)
+			}
+		})
+	}
+
+	for _, input := range []string{"32 09:00", "0 09:00", "6th monday 09:00", "last 25:00", "3rd 09:00", "15"} {
+		if _, err := ParseMonthlySchedule(input, false); err == nil {
+			t.Errorf("ParseMonthlySchedule(%q) expected error", input)
+		}
+	}
+}
+
+func TestMonthlyScheduleShortMonths(t *testing.T) {
+	after := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule MonthlySchedule
+		want     time.Time
+	}{
+		{"31 skips short months", MonthlySchedule{Day: 31, Hour: 9}, time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC)},
+		{"31 clamped to february", MonthlySchedule{Day: 31, Clamp: true, Hour: 9}, time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC)},
+		{"last day of february", MonthlySchedule{Day: LastDay, Hour: 9}, time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC)},
+		// 2026-02-28 es sábado
+		{"last weekday skips weekend", MonthlySchedule{Day: LastBusinessDay, Hour: 9}, time.Date(2026, 2, 27, 9, 0, 0, 0, time.UTC)},
+		{"5th friday skips months without it", MonthlySchedule{Nth: 5, Weekday: time.Friday, Hour: 9}, time.Date(2026, 5, 29, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Next(after); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYearlySchedule(t *testing.T) {
+	after := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		clamp    bool
+		filename string
+		next     time.Time
+	}{
+		{"11-21 10:00", false, "11-21_10-00-00.json", time.Date(2026, 11, 21, 10, 0, 0, 0, time.UTC)},
+		{"11 4th thursday 10:00", false, "11-4th-thursday_10-00-00.json", time.Date(2026, 11, 26, 10, 0, 0, 0, time.UTC)},
+		{"02-29 09:00", false, "02-29_09-00-00.json", time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC)},
+		{"02-29 09:00", true, "02-29-clamp_09-00-00.json", time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC)},
+		{"12 last weekday 17:00", false, "12-last-weekday_17-00-00.json", time.Date(2025, 12, 31, 17, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			y, err := ParseYearlySchedule(tt.input, tt.clamp)
+			if err != nil {
+				t.Fatalf("ParseYearlySchedule() error = %v", err)
+			}
+			if got := y.Filename(); got != tt.filename {
+				t.Errorf("Filename() = %v, want %v", got, tt.filename)
+			}
+			if got := y.Next(after); !got.Equal(tt.next) {
+				t.Errorf("Next() = %v, want %v", got, tt.next)
+			}
+			parsed, err := ParseYearlyFilename(tt.filename)
+			if err != nil || parsed != y {
+				t.Errorf("ParseYearlyFilename() = %+v, %v; want %+v", parsed, err, y)
+			}
+		})
+	}
+
+	for _, input := range []string{"02-30 09:00", "04-31 09:00", "13-01 09:00", "11 fifth thursday 10:00"} {
+		if _, err := ParseYearlySchedule(input, false); err == nil {
+			t.Errorf("ParseYearlySchedule(%q) expected error", input)
+		}
+	}
+}
+
+// TestMonthlyFilenamesAgreeWithNext verifica que CheckAlarms (que busca los
+// archivos de MonthlyFilenames minuto a minuto) y calculateNextRun (Next)
+// coincidan en todos los días de varios años
+func TestMonthlyFilenamesAgreeWithNext(t *testing.T) {
+	var schedules []MonthlySchedule
+	for day := 1; day <= 31; day++ {
+		schedules = append(schedules, MonthlySchedule{Day: day, Hour: 9})
+		if day > 28 {
+			schedules = append(schedules, MonthlySchedule{Day: day, Clamp: true, Hour: 9})
+		}
+	}
+	schedules = append(schedules, MonthlySchedule{Day: LastDay, Hour: 9}, MonthlySchedule{Day: LastBusinessDay, Hour: 9})
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		for _, nth := range []int{1, 2, 3, 4, 5, LastWeek} {
+			schedules = append(schedules, MonthlySchedule{Nth: nth, Weekday: weekday, Hour: 9})
+		}
+	}
+
+	for _, m := range schedules {
+		filename := m.Filename()
+		yearly := YearlySchedule{Month: time.February, Day: m.Day, Clamp: m.Clamp, Nth: m.Nth, Weekday: m.Weekday, Hour: 9}
+		yearlyFilename := yearly.Filename()
+
+		for d := time.Date(2027, 1, 1, 9, 0, 0, 0, time.UTC); d.Year() < 2029; d = d.AddDate(0, 0, 1) {
+			fires := m.Next(d.Add(-time.Minute)).Equal(d)
+			if got := contains(MonthlyFilenames(d), filename); got != fires {
+				t.Errorf("%s el %s: en MonthlyFilenames = %v, Next = %v", filename, d.Format("2006-01-02"), got, fires)
+			}
+
+			fires = yearly.Next(d.Add(-time.Minute)).Equal(d)
+			if got := contains(YearlyFilenames(d), yearlyFilename); got != fires {
+				t.Errorf("%s el %s: en YearlyFilenames = %v, Next = %v", yearlyFilename, d.Format("2006-01-02"), got, fires)
+			}
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
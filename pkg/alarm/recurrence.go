@@ -10,17 +10,22 @@ import (
 type Recurrence string
 
 const (
-	RecurrenceOnce    Recurrence = "once"
-	RecurrenceDaily   Recurrence = "daily"
-	RecurrenceWeekly  Recurrence = "weekly"
-	RecurrenceMonthly Recurrence = "monthly"
-	RecurrenceYearly  Recurrence = "yearly"
+	RecurrenceOnce     Recurrence = "once"
+	RecurrenceDaily    Recurrence = "daily"
+	RecurrenceWeekly   Recurrence = "weekly"
+	RecurrenceMonthly  Recurrence = "monthly"
+	RecurrenceYearly   Recurrence = "yearly"
+	RecurrenceCron     Recurrence = "cron"
+	RecurrenceInterval Recurrence = "interval"
+	// RecurrenceReminder son los recordatorios de eventos, que no se guardan
+	// como alarmas sino que se calculan a partir de los eventos
+	RecurrenceReminder Recurrence = "reminder"
 )
 
 // Valid retorna true si la recurrencia es válida
 func (r Recurrence) Valid() bool {
 	switch r {
-	case RecurrenceOnce, RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly, RecurrenceYearly:
+	case RecurrenceOnce, RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly, RecurrenceYearly, RecurrenceCron, RecurrenceInterval, RecurrenceReminder:
 		return true
 	default:
 		return false
@@ -46,31 +51,70 @@ func (w WeeklySchedule) Filename() string {
 	return fmt.Sprintf("%s_%02d-%02d-00.json", weekdayName, w.Hour, w.Minute)
 }
 
-// MonthlySchedule representa un horario mensual
+// MonthlySchedule representa un horario mensual. Day es un día fijo 1-31
+// (con Clamp, los días 29-31 se ejecutan el último día de los meses más
+// cortos), LastDay o LastBusinessDay; con Day en 0, Nth (1-5 o LastWeek)
+// y Weekday indican el enésimo día de la semana del mes.
 type MonthlySchedule struct {
-	Day    int // 1-31
-	Hour   int
-	Minute int
+	Day     int
+	Clamp   bool
+	Nth     int
+	Weekday time.Weekday
+	Hour    int
+	Minute  int
 }
 
 // Filename retorna el nombre de archivo para una alarma mensual
-// Formato: 15_14-30-00.json
+// Formato: 15_14-30-00.json, 31-clamp_14-30-00.json, last-day_14-30-00.json,
+// last-weekday_14-30-00.json, 2nd-tuesday_14-30-00.json
 func (m MonthlySchedule) Filename() string {
-	return fmt.Sprintf("%02d_%02d-%02d-00.json", m.Day, m.Hour, m.Minute)
+	return fmt.Sprintf("%s_%02d-%02d-00.json", m.monthDay().filenamePart(), m.Hour, m.Minute)
+}
+
+// DayString retorna una descripción legible del día (15, last weekday, 2nd Tuesday)
+func (m MonthlySchedule) DayString() string {
+	return m.monthDay().String()
+}
+
+// Next retorna la primera ejecución posterior a after (en la zona horaria de after)
+func (m MonthlySchedule) Next(after time.Time) time.Time {
+	return nextMonthDay(m.monthDay(), m.Hour, m.Minute, after, after.Month(), 1)
+}
+
+func (m MonthlySchedule) monthDay() monthDay {
+	return monthDay{day: m.Day, clamp: m.Clamp, nth: m.Nth, weekday: m.Weekday}
 }
 
-// YearlySchedule representa un horario anual
+// YearlySchedule representa un horario anual; Day, Clamp, Nth y Weekday
+// indican el día de Month como en MonthlySchedule
 type YearlySchedule struct {
-	Month  time.Month
-	Day    int
-	Hour   int
-	Minute int
+	Month   time.Month
+	Day     int
+	Clamp   bool
+	Nth     int
+	Weekday time.Weekday
+	Hour    int
+	Minute  int
 }
 
 // Filename retorna el nombre de archivo para una alarma anual
-// Formato: 11-21_14-30-00.json
+// Formato: 11-21_14-30-00.json, 11-4th-thursday_10-00-00.json
 func (y YearlySchedule) Filename() string {
-	return fmt.Sprintf("%02d-%02d_%02d-%02d-00.json", y.Month, y.Day, y.Hour, y.Minute)
+	return fmt.Sprintf("%02d-%s_%02d-%02d-00.json", y.Month, y.monthDay().filenamePart(), y.Hour, y.Minute)
+}
+
+// DayString retorna una descripción legible del día (21, 4th Thursday)
+func (y YearlySchedule) DayString() string {
+	return y.monthDay().String()
+}
+
+// Next retorna la primera ejecución posterior a after (en la zona horaria de after)
+func (y YearlySchedule) Next(after time.Time) time.Time {
+	return nextMonthDay(y.monthDay(), y.Hour, y.Minute, after, y.Month, 12)
+}
+
+func (y YearlySchedule) monthDay() monthDay {
+	return monthDay{day: y.Day, clamp: y.Clamp, nth: y.Nth, weekday: y.Weekday}
 }
 
 // DailySchedule representa un horario diario
@@ -16,6 +16,9 @@ func TestRecurrenceValid(t *testing.T) {
 		{"weekly is valid", RecurrenceWeekly, true},
 		{"monthly is valid", RecurrenceMonthly, true},
 		{"yearly is valid", RecurrenceYearly, true},
+		{"cron is valid", RecurrenceCron, true},
+		{"interval is valid", RecurrenceInterval, true},
+		{"reminder is valid", RecurrenceReminder, true},
 		{"invalid recurrence", Recurrence("invalid"), false},
 		{"empty recurrence", Recurrence(""), false},
 	}
@@ -0,0 +1,106 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// Conflict representa dos entradas cuyos horarios se superponen
+type Conflict struct {
+	A *Entry `json:"a"`
+	B *Entry `json:"b"`
+}
+
+// Overlap retorna el intervalo en que se superponen las dos entradas
+func (c Conflict) Overlap() (time.Time, time.Time) {
+	start, end := c.A.DateTime, c.A.EndTime()
+	if c.B.DateTime.After(start) {
+		start = c.B.DateTime
+	}
+	if c.B.EndTime().Before(end) {
+		end = c.B.EndTime()
+	}
+	return start, end
+}
+
+// Minutes retorna los minutos de superposición
+func (c Conflict) Minutes() int {
+	start, end := c.Overlap()
+	return int(end.Sub(start).Minutes())
+}
+
+// blocksTime indica si una entrada ocupa horario. Los eventos de día completo
+// (feriados, vacaciones) no generan conflictos.
+func blocksTime(e *Entry) bool {
+	return !e.AllDay
+}
+
+// FindConflicts retorna las entradas de existing que se superponen con candidate.
+// Si candidate es recurrente se comparan todas sus ocurrencias dentro del rango
+// cubierto por existing, por lo que existing debe tener las ocurrencias ya
+// expandidas. Se ignoran las entradas con el mismo ID que candidate (el propio
+// evento o su serie).
+func FindConflicts(candidate *Entry, existing []*Entry) []*Entry {
+	if !blocksTime(candidate) || len(existing) == 0 {
+		return nil
+	}
+
+	from, to := existing[0].DateTime, existing[0].EndTime()
+	for _, e := range existing {
+		if e.DateTime.Before(from) {
+			from = e.DateTime
+		}
+		if e.EndTime().After(to) {
+			to = e.EndTime()
+		}
+	}
+
+	var result []*Entry
+	seen := make(map[*Entry]bool)
+	for _, occ := range candidate.ExpandOccurrences(from, to) {
+		for _, e := range existing {
+			if seen[e] || e.ID == candidate.ID || !blocksTime(e) {
+				continue
+			}
+			if e.Overlaps(occ.DateTime, occ.EndTime()) {
+				seen[e] = true
+				result = append(result, e)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DateTime.Before(result[j].DateTime)
+	})
+
+	return result
+}
+
+// FindAllConflicts retorna todos los pares de entradas que se superponen,
+// ordenados por inicio. Las entradas deben tener las ocurrencias expandidas.
+func FindAllConflicts(entries []*Entry) []Conflict {
+	sorted := make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		if blocksTime(e) {
+			sorted = append(sorted, e)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DateTime.Before(sorted[j].DateTime)
+	})
+
+	// Barrido por inicio: cada entrada solo se compara con las siguientes que
+	// comienzan antes de su fin
+	var conflicts []Conflict
+	for i, a := range sorted {
+		end := a.EndTime()
+		for _, b := range sorted[i+1:] {
+			if !b.DateTime.Before(end) {
+				break
+			}
+			conflicts = append(conflicts, Conflict{A: a, B: b})
+		}
+	}
+
+	return conflicts
+}
@@ -0,0 +1,84 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindConflicts(t *testing.T) {
+	day := time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+
+	standup := NewEntry("12345", "Stand-up", at(9, 0), 15)
+	review := NewEntry("12345", "Review", at(10, 0), 60)
+	lunch := NewEntry("12345", "Almuerzo", at(13, 0), 60)
+	holiday := NewEntry("12345", "Feriado", day, 0)
+	holiday.AllDay = true
+	existing := []*Entry{standup, review, lunch, holiday}
+
+	tests := []struct {
+		name      string
+		candidate *Entry
+		want      []string
+	}{
+		{"no overlap", NewEntry("12345", "Call", at(11, 0), 30), nil},
+		{"touching ends", NewEntry("12345", "Call", at(9, 15), 45), nil},
+		{"overlaps one", NewEntry("12345", "Call", at(10, 30), 60), []string{"Review"}},
+		{"overlaps two", NewEntry("12345", "Workshop", at(9, 0), 90), []string{"Stand-up", "Review"}},
+		{"same event", &Entry{ID: review.ID, DateTime: at(10, 15), Duration: 60}, nil},
+		{"all-day candidate", &Entry{DateTime: day, AllDay: true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindConflicts(tt.candidate, existing)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d conflicts, got %d", len(tt.want), len(got))
+			}
+			for i, title := range tt.want {
+				if got[i].Title != title {
+					t.Errorf("Conflict %d: expected %s, got %s", i, title, got[i].Title)
+				}
+			}
+		})
+	}
+}
+
+func TestFindConflictsRecurring(t *testing.T) {
+	series := newDailySeries(t, "FREQ=DAILY;COUNT=5")
+	meeting := NewEntry("12345", "Meeting", time.Date(2025, 11, 6, 9, 15, 0, 0, time.UTC), 60)
+	later := NewEntry("12345", "Later", time.Date(2025, 11, 20, 9, 0, 0, 0, time.UTC), 60)
+
+	got := FindConflicts(series, []*Entry{meeting, later})
+	if len(got) != 1 || got[0] != meeting {
+		t.Errorf("Expected conflict with the 4th occurrence only, got %v", got)
+	}
+}
+
+func TestFindAllConflicts(t *testing.T) {
+	day := time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return day.Add(time.Duration(h) * time.Hour) }
+
+	a := NewEntry("12345", "A", at(9), 120)
+	b := NewEntry("12345", "B", at(10), 60)
+	c := NewEntry("12345", "C", at(10), 30)
+	d := NewEntry("12345", "D", at(11), 60)
+	e := NewEntry("12345", "E", at(15), 30)
+
+	conflicts := FindAllConflicts([]*Entry{e, d, c, b, a})
+
+	want := [][2]string{{"A", "C"}, {"A", "B"}, {"C", "B"}}
+	if len(conflicts) != len(want) {
+		t.Fatalf("Expected %d conflicts, got %d", len(want), len(conflicts))
+	}
+	for i, pair := range want {
+		got := conflicts[i]
+		if got.A.Title != pair[0] || got.B.Title != pair[1] {
+			t.Errorf("Conflict %d: expected %s/%s, got %s/%s", i, pair[0], pair[1], got.A.Title, got.B.Title)
+		}
+	}
+
+	if minutes := conflicts[1].Minutes(); minutes != 60 {
+		t.Errorf("Expected 60 minutes of overlap, got %d", minutes)
+	}
+}
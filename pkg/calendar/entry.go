@@ -21,6 +21,30 @@ type Entry struct {
 	Metadata  map[string]string `json:"metadata,omitempty"`
 	CreatedAt time.Time         `json:"created_at"`
 	UpdatedAt time.Time         `json:"updated_at"`
+
+	// Recurrencia (RFC 5545 RRULE). Las ocurrencias se expanden al listar.
+	RRule *RecurrenceRule `json:"rrule,omitempty"`
+	// ExDates son los inicios de ocurrencias canceladas (RFC 5545 EXDATE)
+	ExDates []time.Time `json:"exdates,omitempty"`
+	// RecurrenceID es el inicio original de una ocurrencia virtual; nil en el evento maestro
+	RecurrenceID *time.Time `json:"recurrence_id,omitempty"`
+
+	// TZID es la zona horaria IANA del evento (ej: "Europe/Madrid"). Se usa para
+	// guardar la hora en su zona de origen y para expandir recurrencias respetando
+	// los cambios de horario. Vacío = la zona de DateTime.
+	TZID string `json:"tzid,omitempty"`
+
+	// AllDay indica un evento de día completo: ocupa desde el inicio del día de
+	// DateTime hasta el fin del día de EndDate (o del mismo día) y no usa Duration.
+	AllDay bool `json:"all_day,omitempty"`
+	// EndDate es el fin de un evento de varios días. En eventos de día completo es
+	// el último día (inclusive); en el resto es el instante de fin y reemplaza a Duration.
+	EndDate *time.Time `json:"end_date,omitempty"`
+
+	// Reminders son las anticipaciones (minutos antes del inicio) de los
+	// recordatorios del evento. Sus alarmas se calculan al chequear las
+	// alarmas, así que siguen al evento si se mueve o se elimina.
+	Reminders []int `json:"reminders,omitempty"`
 }
 
 // NewEntry crea una nueva entrada con valores por defecto
@@ -57,17 +81,93 @@ func (e *Entry) Validate() error {
 	if e.DateTime.IsZero() {
 		return fmt.Errorf("datetime es requerido")
 	}
-	if e.Duration <= 0 {
+	if e.Duration < 0 || (e.Duration == 0 && !e.AllDay && e.EndDate == nil) {
 		return fmt.Errorf("duration debe ser mayor a 0")
 	}
+	if e.EndDate != nil {
+		if e.AllDay && e.EndDate.Before(StartOfDay(e.LocalDateTime())) {
+			return fmt.Errorf("end_date no puede ser anterior al inicio")
+		}
+		if !e.AllDay && !e.EndDate.After(e.DateTime) {
+			return fmt.Errorf("end_date debe ser posterior al inicio")
+		}
+	}
+	if e.RRule != nil {
+		if err := e.RRule.Validate(); err != nil {
+			return fmt.Errorf("rrule inválida: %w", err)
+		}
+	}
+	if e.TZID != "" {
+		if _, err := time.LoadLocation(e.TZID); err != nil {
+			return fmt.Errorf("tzid inválido: %v", err)
+		}
+	}
+	for _, r := range e.Reminders {
+		if r < 0 || r > MaxReminder {
+			return fmt.Errorf("recordatorio inválido: %d minutos (máximo %d)", r, MaxReminder)
+		}
+	}
 	return nil
 }
 
-// EndTime calcula la hora de finalización del evento
+// TimeZone retorna la zona horaria del evento (TZID, o la de DateTime si no tiene)
+func (e *Entry) TimeZone() *time.Location {
+	if e.TZID != "" {
+		if loc, err := time.LoadLocation(e.TZID); err == nil {
+			return loc
+		}
+	}
+	return e.DateTime.Location()
+}
+
+// LocalDateTime retorna el inicio del evento en su propia zona horaria
+func (e *Entry) LocalDateTime() time.Time {
+	return e.DateTime.In(e.TimeZone())
+}
+
+// EndTime calcula la hora de finalización del evento. En eventos de día completo
+// es el inicio del día siguiente al último día.
 func (e *Entry) EndTime() time.Time {
+	if e.AllDay {
+		last := e.LocalDateTime()
+		if e.EndDate != nil {
+			last = e.EndDate.In(e.TimeZone())
+		}
+		return StartOfDay(last).AddDate(0, 0, 1).In(e.DateTime.Location())
+	}
+	if e.EndDate != nil {
+		return *e.EndDate
+	}
 	return e.DateTime.Add(time.Duration(e.Duration) * time.Minute)
 }
 
+// Minutes retorna la duración total del evento en minutos
+func (e *Entry) Minutes() int {
+	return int(e.EndTime().Sub(e.DateTime).Minutes())
+}
+
+// IsMultiDay verifica si el evento termina después del día en que comienza
+// (en su zona horaria)
+func (e *Entry) IsMultiDay() bool {
+	start := e.LocalDateTime()
+	return e.EndTime().After(StartOfDay(start).AddDate(0, 0, 1))
+}
+
+// Overlaps verifica si el evento se superpone con el intervalo [from, to)
+func (e *Entry) Overlaps(from, to time.Time) bool {
+	return e.DateTime.Before(to) && e.EndTime().After(from)
+}
+
+// CoversDay verifica si el evento ocupa todo el día [dayStart, dayEnd)
+func (e *Entry) CoversDay(dayStart, dayEnd time.Time) bool {
+	return !e.DateTime.After(dayStart) && !e.EndTime().Before(dayEnd)
+}
+
+// StartOfDay retorna la medianoche del día de t, en su zona horaria
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
 // IsPast verifica si el evento ya pasó
 func (e *Entry) IsPast() bool {
 	return time.Now().After(e.EndTime())
@@ -84,6 +184,77 @@ func (e *Entry) IsCurrent() bool {
 	return now.After(e.DateTime) && now.Before(e.EndTime())
 }
 
+// IsRecurring verifica si la entrada es un evento maestro con recurrencia
+func (e *Entry) IsRecurring() bool {
+	return e.RRule != nil && e.RecurrenceID == nil
+}
+
+// IsOccurrence verifica si la entrada es una ocurrencia virtual de un evento recurrente
+func (e *Entry) IsOccurrence() bool {
+	return e.RecurrenceID != nil
+}
+
+// Occurrence crea una copia de la entrada como ocurrencia virtual que comienza en start
+func (e *Entry) Occurrence(start time.Time) *Entry {
+	occ := *e
+	occ.DateTime = start
+	recurrenceID := start
+	occ.RecurrenceID = &recurrenceID
+	occ.RRule = nil
+	occ.ExDates = nil
+
+	// El fin de un evento de varios días se desplaza junto con el inicio
+	if e.EndDate != nil {
+		var end time.Time
+		if e.AllDay {
+			end = e.EndDate.In(e.TimeZone()).AddDate(0, 0, daysBetween(e.LocalDateTime(), start.In(e.TimeZone())))
+		} else {
+			end = e.EndDate.Add(start.Sub(e.DateTime))
+		}
+		occ.EndDate = &end
+	}
+
+	occ.Tags = append([]string(nil), e.Tags...)
+	occ.Reminders = append([]int(nil), e.Reminders...)
+	if e.Metadata != nil {
+		occ.Metadata = make(map[string]string, len(e.Metadata))
+		for k, v := range e.Metadata {
+			occ.Metadata[k] = v
+		}
+	}
+
+	return &occ
+}
+
+// ExpandOccurrences retorna las ocurrencias del evento que se superponen con [from, to].
+// Para entradas no recurrentes retorna la propia entrada.
+func (e *Entry) ExpandOccurrences(from, to time.Time) []*Entry {
+	if !e.IsRecurring() {
+		return []*Entry{e}
+	}
+
+	// Expandir en la zona del evento para conservar la hora local tras un cambio de horario
+	duration := e.EndTime().Sub(e.DateTime)
+	starts := e.RRule.Occurrences(e.LocalDateTime(), duration, from, to)
+
+	occurrences := make([]*Entry, 0, len(starts))
+	for _, start := range starts {
+		if e.IsExcluded(start) {
+			continue
+		}
+		occurrences = append(occurrences, e.Occurrence(start))
+	}
+
+	return occurrences
+}
+
+// daysBetween retorna la cantidad de días de calendario entre las fechas de a y b
+func daysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
 // GenerateFilename genera el nombre de archivo para esta entrada
 // Formato: HH-MM-titulo-slug.md
 func (e *Entry) GenerateFilename() string {
@@ -90,6 +90,37 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid tzid",
+			entry: &Entry{
+				UserID:   "12345",
+				Title:    "Test",
+				DateTime: time.Now(),
+				Duration: 60,
+				TZID:     "Mars/Olympus",
+			},
+			wantErr: true,
+		},
+		{
+			name: "all-day without duration",
+			entry: &Entry{
+				UserID:   "12345",
+				Title:    "Feriado",
+				DateTime: time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC),
+				AllDay:   true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "end date before start",
+			entry: &Entry{
+				UserID:   "12345",
+				Title:    "Conferencia",
+				DateTime: time.Date(2025, 12, 25, 9, 0, 0, 0, time.UTC),
+				EndDate:  timePtr(time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)),
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -117,6 +148,53 @@ func TestEndTime(t *testing.T) {
 	}
 }
 
+func TestEndTimeMultiDay(t *testing.T) {
+	start := time.Date(2025, 12, 22, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		entry    *Entry
+		want     time.Time
+		multiDay bool
+	}{
+		{
+			name:     "all-day single day",
+			entry:    &Entry{DateTime: start, AllDay: true},
+			want:     time.Date(2025, 12, 23, 0, 0, 0, 0, time.UTC),
+			multiDay: false,
+		},
+		{
+			name:     "all-day with end date",
+			entry:    &Entry{DateTime: start, AllDay: true, EndDate: timePtr(time.Date(2025, 12, 26, 0, 0, 0, 0, time.UTC))},
+			want:     time.Date(2025, 12, 27, 0, 0, 0, 0, time.UTC),
+			multiDay: true,
+		},
+		{
+			name:     "timed with end date",
+			entry:    &Entry{DateTime: start.Add(9 * time.Hour), Duration: 60, EndDate: timePtr(time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC))},
+			want:     time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC),
+			multiDay: true,
+		},
+		{
+			name:     "timed past midnight",
+			entry:    &Entry{DateTime: start.Add(23 * time.Hour), Duration: 120},
+			want:     time.Date(2025, 12, 23, 1, 0, 0, 0, time.UTC),
+			multiDay: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.EndTime(); !got.Equal(tt.want) {
+				t.Errorf("EndTime() = %v, want %v", got, tt.want)
+			}
+			if got := tt.entry.IsMultiDay(); got != tt.multiDay {
+				t.Errorf("IsMultiDay() = %v, want %v", got, tt.multiDay)
+			}
+		})
+	}
+}
+
 func TestIsPastFutureCurrent(t *testing.T) {
 	now := time.Now()
 
@@ -218,3 +296,7 @@ func TestTags(t *testing.T) {
 		t.Error("Expected entry to not have tag 'trabajo' after removal")
 	}
 }
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -1,14 +1,47 @@
 package calendar
 
 import (
+	"fmt"
 	"strings"
 	"time"
 )
 
+// RangeMode define cómo se compara una entrada con el rango From/To de un filtro
+type RangeMode int
+
+const (
+	// RangeOverlap incluye las entradas que se superponen con el rango
+	// (inicio < To y fin > From). Es el modo por defecto.
+	RangeOverlap RangeMode = iota
+	// RangeStartsWithin incluye solo las entradas que comienzan dentro del rango
+	RangeStartsWithin
+)
+
+// ParseRangeMode parsea un modo de rango: "overlap" o "starts-within"
+func ParseRangeMode(s string) (RangeMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "overlap":
+		return RangeOverlap, nil
+	case "starts-within", "start":
+		return RangeStartsWithin, nil
+	default:
+		return RangeOverlap, fmt.Errorf("modo de rango inválido: %s (usar: overlap, starts-within)", s)
+	}
+}
+
+// String retorna el nombre del modo
+func (m RangeMode) String() string {
+	if m == RangeStartsWithin {
+		return "starts-within"
+	}
+	return "overlap"
+}
+
 // Filter representa criterios de búsqueda/filtrado de entradas
 type Filter struct {
 	From         *time.Time
 	To           *time.Time
+	RangeMode    RangeMode // cómo se aplica From/To (por defecto, superposición)
 	Query        string // búsqueda de texto libre
 	Title        string // filtro por título
 	Location     string // filtro por ubicación
@@ -31,10 +64,7 @@ func NewFilter() *Filter {
 // Matches verifica si una entrada cumple con el filtro
 func (f *Filter) Matches(entry *Entry) bool {
 	// Filtro por rango de fechas
-	if f.From != nil && entry.DateTime.Before(*f.From) {
-		return false
-	}
-	if f.To != nil && entry.DateTime.After(*f.To) {
+	if !f.matchesRange(entry) {
 		return false
 	}
 
@@ -103,6 +133,29 @@ func (f *Filter) Matches(entry *Entry) bool {
 	return true
 }
 
+// matchesRange verifica la entrada contra From/To según RangeMode. En modo
+// superposición los eventos que cruzan la medianoche o duran varios días se
+// incluyen en todos los días que abarcan.
+func (f *Filter) matchesRange(entry *Entry) bool {
+	if f.RangeMode == RangeStartsWithin {
+		if f.From != nil && entry.DateTime.Before(*f.From) {
+			return false
+		}
+		if f.To != nil && entry.DateTime.After(*f.To) {
+			return false
+		}
+		return true
+	}
+
+	if f.From != nil && !entry.EndTime().After(*f.From) {
+		return false
+	}
+	if f.To != nil && !entry.DateTime.Before(*f.To) {
+		return false
+	}
+	return true
+}
+
 // WithDateRange establece el rango de fechas
 func (f *Filter) WithDateRange(from, to time.Time) *Filter {
 	f.From = &from
@@ -110,6 +163,12 @@ func (f *Filter) WithDateRange(from, to time.Time) *Filter {
 	return f
 }
 
+// WithRangeMode establece cómo se aplica el rango de fechas
+func (f *Filter) WithRangeMode(mode RangeMode) *Filter {
+	f.RangeMode = mode
+	return f
+}
+
 // WithQuery establece la búsqueda de texto
 func (f *Filter) WithQuery(query string) *Filter {
 	f.Query = query
@@ -132,6 +132,100 @@ func TestFilterMatches(t *testing.T) {
 	}
 }
 
+func TestFilterMatchesMultiDay(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2025, 12, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	vacation := &Entry{DateTime: *day(22), AllDay: true, EndDate: day(26)}
+	holiday := &Entry{DateTime: *day(25), AllDay: true}
+
+	tests := []struct {
+		name    string
+		entry   *Entry
+		from    *time.Time
+		to      *time.Time
+		matches bool
+	}{
+		{"first day", vacation, day(22), day(23), true},
+		{"middle day", vacation, day(24), day(25), true},
+		{"last day", vacation, day(26), day(27), true},
+		{"day after", vacation, day(27), day(28), false},
+		{"day before", vacation, day(21), day(22), false},
+		{"single all-day event", holiday, day(25), day(26), true},
+		{"single all-day event previous day", holiday, day(24), day(25), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := &Filter{From: tt.from, To: tt.to}
+			if got := filter.Matches(tt.entry); got != tt.matches {
+				t.Errorf("Matches() = %v, want %v", got, tt.matches)
+			}
+		})
+	}
+}
+
+func TestFilterRangeMode(t *testing.T) {
+	// Evento de 23:00 a 01:00 del día siguiente
+	entry := &Entry{DateTime: time.Date(2025, 11, 20, 23, 0, 0, 0, time.UTC), Duration: 120}
+
+	yesterday := time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 11, 21, 0, 0, 0, 0, time.UTC)
+	tomorrow := time.Date(2025, 11, 22, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return today.Add(time.Duration(h) * time.Hour) }
+
+	tests := []struct {
+		name    string
+		mode    RangeMode
+		from    time.Time
+		to      time.Time
+		matches bool
+	}{
+		{"overlap previous day", RangeOverlap, yesterday, today, true},
+		{"overlap next day", RangeOverlap, today, tomorrow, true},
+		{"overlap after end", RangeOverlap, at(1), at(2), false},
+		{"overlap range ending at start", RangeOverlap, yesterday.Add(22 * time.Hour), yesterday.Add(23 * time.Hour), false},
+		{"starts within previous day", RangeStartsWithin, yesterday, today, true},
+		{"starts within next day", RangeStartsWithin, today, tomorrow, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewFilter().WithDateRange(tt.from, tt.to).WithRangeMode(tt.mode)
+			if got := filter.Matches(entry); got != tt.matches {
+				t.Errorf("Matches() = %v, want %v", got, tt.matches)
+			}
+		})
+	}
+}
+
+func TestParseRangeMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    RangeMode
+		wantErr bool
+	}{
+		{"", RangeOverlap, false},
+		{"overlap", RangeOverlap, false},
+		{"starts-within", RangeStartsWithin, false},
+		{"START", RangeStartsWithin, false},
+		{"ends-within", RangeOverlap, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRangeMode(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRangeMode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRangeMode(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
 func TestFilterWithDateRange(t *testing.T) {
 	from := time.Now()
 	to := from.Add(7 * 24 * time.Hour)
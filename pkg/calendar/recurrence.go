@@ -0,0 +1,494 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency define la frecuencia de una regla de recurrencia (RFC 5545 FREQ)
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+	FrequencyYearly  Frequency = "YEARLY"
+)
+
+// maxOccurrenceScan limita la cantidad de períodos recorridos al expandir una
+// regla, para que una regla sin COUNT/UNTIL no genere un loop infinito
+const maxOccurrenceScan = 100000
+
+// maxEmptyYears limita cuántos años seguidos se recorren sin encontrar ninguna
+// ocurrencia. Una regla válida nunca tiene un hueco tan largo (el peor caso es
+// un 29 de febrero, que puede saltear 8 años), así que pasado ese límite la
+// regla se considera imposible (ej: BYMONTHDAY=1;BYDAY=2MO).
+const maxEmptyYears = 10
+
+// WeekdayNum representa un valor de BYDAY: un día de la semana con un
+// ordinal opcional (ej: "MO", "2TU", "-1FR"). N=0 significa "todos".
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// RecurrenceRule representa un subconjunto de RRULE de RFC 5545:
+// FREQ, INTERVAL, BYDAY, BYMONTHDAY, COUNT y UNTIL
+type RecurrenceRule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRecurrenceRule parsea una RRULE en formato RFC 5545, con un UNTIL sin
+// zona horaria en la zona local (ver ParseRecurrenceRuleIn)
+// Ejemplo: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20251231T235959Z"
+func ParseRecurrenceRule(s string) (*RecurrenceRule, error) {
+	return ParseRecurrenceRuleIn(s, time.Local)
+}
+
+// ParseRecurrenceRuleIn parsea una RRULE en formato RFC 5545. Un UNTIL sin
+// zona horaria (fecha o fecha y hora flotante) se interpreta en loc, que
+// debe ser la zona del DTSTART del evento.
+func ParseRecurrenceRuleIn(s string, loc *time.Location) (*RecurrenceRule, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("regla de recurrencia vacía")
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("parte inválida en RRULE: %s", part)
+		}
+		key := strings.ToUpper(strings.TrimSpace(kv[0]))
+		value := strings.TrimSpace(kv[1])
+
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(value))
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("INTERVAL inválido: %s", value)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("COUNT inválido: %s", value)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseRRuleTime(value, loc)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				wd, err := parseWeekdayNum(d)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(strings.TrimSpace(d))
+				if err != nil {
+					return nil, fmt.Errorf("BYMONTHDAY inválido: %s", d)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "WKST":
+			// Solo se soporta WKST=MO (valor por defecto de RFC 5545)
+			if strings.ToUpper(value) != "MO" {
+				return nil, fmt.Errorf("WKST no soportado: %s", value)
+			}
+		default:
+			return nil, fmt.Errorf("parte de RRULE no soportada: %s", key)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// parseWeekdayNum parsea un valor de BYDAY como "MO", "2TU" o "-1FR"
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("BYDAY inválido: %s", s)
+	}
+
+	code := s[len(s)-2:]
+	wd, ok := weekdayCodes[code]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("BYDAY inválido: %s", s)
+	}
+
+	n := 0
+	if prefix := s[:len(s)-2]; prefix != "" {
+		var err error
+		n, err = strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return WeekdayNum{}, fmt.Errorf("BYDAY inválido: %s", s)
+		}
+	}
+
+	return WeekdayNum{N: n, Weekday: wd}, nil
+}
+
+// parseRRuleTime parsea UNTIL en formato DATE o DATE-TIME de RFC 5545; los
+// valores sin zona horaria se interpretan en loc
+func parseRRuleTime(s string, loc *time.Location) (time.Time, error) {
+	formats := []struct {
+		layout string
+		loc    *time.Location
+	}{
+		{"20060102T150405Z", time.UTC},
+		{"20060102T150405", loc},
+		{"20060102", loc},
+	}
+
+	for _, f := range formats {
+		if t, err := time.ParseInLocation(f.layout, s, f.loc); err == nil {
+			if f.layout == "20060102" {
+				// UNTIL con fecha incluye todo ese día
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("UNTIL inválido: %s", s)
+}
+
+// Validate valida que la regla sea consistente
+func (r *RecurrenceRule) Validate() error {
+	switch r.Freq {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	case "":
+		return fmt.Errorf("FREQ es requerido")
+	default:
+		return fmt.Errorf("FREQ no soportado: %s", r.Freq)
+	}
+
+	if r.Interval < 1 {
+		return fmt.Errorf("INTERVAL debe ser mayor a 0")
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("COUNT no puede ser negativo")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return fmt.Errorf("COUNT y UNTIL no pueden usarse juntos")
+	}
+
+	for _, d := range r.ByMonthDay {
+		if d == 0 || d < -31 || d > 31 {
+			return fmt.Errorf("BYMONTHDAY fuera de rango: %d", d)
+		}
+	}
+
+	for _, d := range r.ByDay {
+		if d.N != 0 && r.Freq != FrequencyMonthly {
+			return fmt.Errorf("BYDAY con ordinal solo se soporta con FREQ=MONTHLY")
+		}
+	}
+	if len(r.ByDay) > 0 && r.Freq == FrequencyYearly {
+		return fmt.Errorf("BYDAY no se soporta con FREQ=YEARLY")
+	}
+
+	return nil
+}
+
+// String serializa la regla en formato RRULE de RFC 5545 (sin el prefijo "RRULE:")
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = d.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// String retorna el valor BYDAY (ej: "MO", "-1FR")
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return weekdayNames[w.Weekday]
+	}
+	return fmt.Sprintf("%d%s", w.N, weekdayNames[w.Weekday])
+}
+
+// MarshalJSON serializa la regla como string RRULE para que el JSON sea legible
+func (r *RecurrenceRule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON parsea una regla serializada como string RRULE
+func (r *RecurrenceRule) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := ParseRecurrenceRule(s)
+	if err != nil {
+		return err
+	}
+
+	*r = *parsed
+	return nil
+}
+
+// Occurrences retorna los inicios de las ocurrencias de la regla a partir de
+// dtstart cuyo intervalo [inicio, inicio+duration) se superpone con [from, to].
+// COUNT se cuenta desde dtstart, incluyendo ocurrencias anteriores a from.
+func (r *RecurrenceRule) Occurrences(dtstart time.Time, duration time.Duration, from, to time.Time) []time.Time {
+	var result []time.Time
+
+	emitted := 0
+	empty, maxEmpty := 0, r.maxEmptyPeriods()
+	for i := 0; i < maxOccurrenceScan; i++ {
+		candidates := r.periodCandidates(dtstart, i*r.Interval)
+		if len(candidates) == 0 {
+			empty++
+			if empty > maxEmpty {
+				break
+			}
+			continue
+		}
+		empty = 0
+
+		for _, c := range candidates {
+			if c.Before(dtstart) {
+				continue
+			}
+			if r.Until != nil && c.After(*r.Until) {
+				return result
+			}
+			if r.Count > 0 && emitted >= r.Count {
+				return result
+			}
+			if c.After(to) {
+				return result
+			}
+
+			emitted++
+			if c.Add(duration).After(from) || c.Equal(from) {
+				result = append(result, c)
+			}
+		}
+	}
+
+	return result
+}
+
+// maxEmptyPeriods retorna la cantidad de períodos seguidos sin ocurrencias
+// que equivalen a maxEmptyYears según FREQ e INTERVAL
+func (r *RecurrenceRule) maxEmptyPeriods() int {
+	perYear := 1
+	switch r.Freq {
+	case FrequencyDaily:
+		perYear = 366
+	case FrequencyWeekly:
+		perYear = 53
+	case FrequencyMonthly:
+		perYear = 12
+	}
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	return (maxEmptyYears*perYear + interval - 1) / interval
+}
+
+// periodCandidates retorna las ocurrencias candidatas (ordenadas) del período
+// que está offset unidades de FREQ después del período de dtstart
+func (r *RecurrenceRule) periodCandidates(dtstart time.Time, offset int) []time.Time {
+	loc := dtstart.Location()
+	hour, minute, sec := dtstart.Clock()
+
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, sec, 0, loc)
+	}
+
+	var candidates []time.Time
+
+	switch r.Freq {
+	case FrequencyDaily:
+		day := at(dtstart.Year(), dtstart.Month(), dtstart.Day()+offset)
+		if r.matchesByDay(day) && r.matchesByMonthDay(day) {
+			candidates = append(candidates, day)
+		}
+
+	case FrequencyWeekly:
+		// Semanas comienzan el lunes (WKST=MO)
+		shift := (int(dtstart.Weekday()) + 6) % 7
+		monday := at(dtstart.Year(), dtstart.Month(), dtstart.Day()-shift+offset*7)
+
+		if len(r.ByDay) == 0 {
+			candidates = append(candidates, at(monday.Year(), monday.Month(), monday.Day()+shift))
+			break
+		}
+		for i := 0; i < 7; i++ {
+			day := at(monday.Year(), monday.Month(), monday.Day()+i)
+			if r.matchesByDay(day) && r.matchesByMonthDay(day) {
+				candidates = append(candidates, day)
+			}
+		}
+
+	case FrequencyMonthly:
+		first := at(dtstart.Year(), dtstart.Month()+time.Month(offset), 1)
+		year, month := first.Year(), first.Month()
+		lastDay := daysIn(year, month)
+
+		for day := 1; day <= lastDay; day++ {
+			t := at(year, month, day)
+			if r.matchesMonthly(t, dtstart, lastDay) {
+				candidates = append(candidates, t)
+			}
+		}
+
+	case FrequencyYearly:
+		year := dtstart.Year() + offset
+
+		if len(r.ByMonthDay) == 0 {
+			// Un 29 de febrero solo ocurre en años bisiestos
+			if month := dtstart.Month(); dtstart.Day() <= daysIn(year, month) {
+				candidates = append(candidates, at(year, month, dtstart.Day()))
+			}
+			break
+		}
+		// Sin BYMONTH, BYMONTHDAY se expande a todos los meses del año (RFC 5545)
+		for month := time.January; month <= time.December; month++ {
+			for day := 1; day <= daysIn(year, month); day++ {
+				t := at(year, month, day)
+				if r.matchesByMonthDay(t) {
+					candidates = append(candidates, t)
+				}
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Before(candidates[j])
+	})
+
+	return candidates
+}
+
+// matchesMonthly verifica si un día del mes es ocurrencia de una regla MONTHLY
+func (r *RecurrenceRule) matchesMonthly(t, dtstart time.Time, lastDay int) bool {
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		return t.Day() == dtstart.Day()
+	}
+
+	if len(r.ByMonthDay) > 0 && !r.matchesByMonthDay(t) {
+		return false
+	}
+
+	if len(r.ByDay) > 0 {
+		nth := (t.Day()-1)/7 + 1
+		nthFromEnd := -((lastDay-t.Day())/7 + 1)
+
+		for _, d := range r.ByDay {
+			if d.Weekday != t.Weekday() {
+				continue
+			}
+			if d.N == 0 || d.N == nth || d.N == nthFromEnd {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// matchesByDay verifica BYDAY sin ordinal (vacío = cualquier día)
+func (r *RecurrenceRule) matchesByDay(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Weekday == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesByMonthDay verifica BYMONTHDAY, con valores negativos contando desde fin de mes
+func (r *RecurrenceRule) matchesByMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	lastDay := daysIn(t.Year(), t.Month())
+	for _, d := range r.ByMonthDay {
+		if d == t.Day() || (d < 0 && lastDay+d+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// daysIn retorna la cantidad de días de un mes
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,312 @@
+package calendar
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"weekly by day", "FREQ=WEEKLY;BYDAY=MO,WE", "FREQ=WEEKLY;BYDAY=MO,WE", false},
+		{"with prefix", "RRULE:FREQ=DAILY;INTERVAL=2;COUNT=5", "FREQ=DAILY;INTERVAL=2;COUNT=5", false},
+		{"monthly nth weekday", "FREQ=MONTHLY;BYDAY=-1FR", "FREQ=MONTHLY;BYDAY=-1FR", false},
+		{"until utc", "FREQ=DAILY;UNTIL=20251231T120000Z", "FREQ=DAILY;UNTIL=20251231T120000Z", false},
+		{"lowercase", "freq=weekly;byday=tu", "FREQ=WEEKLY;BYDAY=TU", false},
+		{"missing freq", "INTERVAL=2", "", true},
+		{"invalid freq", "FREQ=HOURLY", "", true},
+		{"invalid interval", "FREQ=DAILY;INTERVAL=0", "", true},
+		{"invalid byday", "FREQ=WEEKLY;BYDAY=XX", "", true},
+		{"count and until", "FREQ=DAILY;COUNT=2;UNTIL=20251231", "", true},
+		{"ordinal outside monthly", "FREQ=WEEKLY;BYDAY=2MO", "", true},
+		{"unsupported part", "FREQ=DAILY;BYHOUR=9", "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRecurrenceRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && rule.String() != tt.want {
+				t.Errorf("String() = %v, want %v", rule.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceRuleOccurrences(t *testing.T) {
+	// Lunes 3 de noviembre de 2025, 09:00
+	dtstart := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		rule string
+		from time.Time
+		to   time.Time
+		want []string
+	}{
+		{
+			name: "daily with count",
+			rule: "FREQ=DAILY;COUNT=3",
+			from: dtstart,
+			to:   dtstart.AddDate(0, 1, 0),
+			want: []string{"2025-11-03", "2025-11-04", "2025-11-05"},
+		},
+		{
+			name: "weekly by day inside window",
+			rule: "FREQ=WEEKLY;BYDAY=MO,WE",
+			from: time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2025, 11, 17, 0, 0, 0, 0, time.UTC),
+			want: []string{"2025-11-10", "2025-11-12"},
+		},
+		{
+			name: "biweekly",
+			rule: "FREQ=WEEKLY;INTERVAL=2",
+			from: dtstart,
+			to:   time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+			want: []string{"2025-11-03", "2025-11-17"},
+		},
+		{
+			name: "count counts occurrences before window",
+			rule: "FREQ=DAILY;COUNT=3",
+			from: time.Date(2025, 11, 5, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC),
+			want: []string{"2025-11-05"},
+		},
+		{
+			name: "until inclusive",
+			rule: "FREQ=DAILY;UNTIL=20251105",
+			from: dtstart,
+			to:   dtstart.AddDate(0, 1, 0),
+			want: []string{"2025-11-03", "2025-11-04", "2025-11-05"},
+		},
+		{
+			name: "monthly first monday",
+			rule: "FREQ=MONTHLY;BYDAY=1MO;COUNT=3",
+			from: dtstart,
+			to:   dtstart.AddDate(1, 0, 0),
+			want: []string{"2025-11-03", "2025-12-01", "2026-01-05"},
+		},
+		{
+			name: "monthly last day",
+			rule: "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			from: dtstart,
+			to:   dtstart.AddDate(1, 0, 0),
+			want: []string{"2025-11-30", "2025-12-31", "2026-01-31"},
+		},
+		{
+			name: "yearly",
+			rule: "FREQ=YEARLY;COUNT=2",
+			from: dtstart,
+			to:   dtstart.AddDate(5, 0, 0),
+			want: []string{"2025-11-03", "2026-11-03"},
+		},
+		{
+			name: "yearly by month day expands to every month",
+			rule: "FREQ=YEARLY;BYMONTHDAY=15;COUNT=3",
+			from: dtstart,
+			to:   dtstart.AddDate(1, 0, 0),
+			want: []string{"2025-11-15", "2025-12-15", "2026-01-15"},
+		},
+		{
+			name: "impossible rule",
+			rule: "FREQ=MONTHLY;BYMONTHDAY=1;BYDAY=2MO",
+			from: dtstart,
+			to:   dtstart.AddDate(1000, 0, 0),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule() error = %v", err)
+			}
+
+			got := rule.Occurrences(dtstart, time.Hour, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() returned %d, want %d (%v)", len(got), len(tt.want), got)
+			}
+			for i, occ := range got {
+				if occ.Format("2006-01-02") != tt.want[i] {
+					t.Errorf("occurrence %d = %s, want %s", i, occ.Format("2006-01-02"), tt.want[i])
+				}
+				if occ.Hour() != 9 {
+					t.Errorf("occurrence %d hour = %d, want 9", i, occ.Hour())
+				}
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceRuleFloatingUntil(t *testing.T) {
+	// Un UNTIL sin zona horaria está en la zona del DTSTART, no en la del proceso
+	loc, err := time.LoadLocation("Pacific/Kiritimati")
+	if err != nil {
+		t.Skip(err)
+	}
+	dtstart := time.Date(2025, 11, 3, 9, 0, 0, 0, loc)
+
+	tests := []struct {
+		rule  string
+		until time.Time
+	}{
+		{"FREQ=DAILY;UNTIL=20251105T090000", time.Date(2025, 11, 5, 9, 0, 0, 0, loc)},
+		{"FREQ=DAILY;UNTIL=20251105", time.Date(2025, 11, 5, 23, 59, 59, 0, loc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := ParseRecurrenceRuleIn(tt.rule, loc)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRuleIn() error = %v", err)
+			}
+			if !rule.Until.Equal(tt.until) {
+				t.Errorf("Until = %v, want %v", rule.Until, tt.until)
+			}
+			if got := rule.Occurrences(dtstart, time.Hour, dtstart, dtstart.AddDate(0, 1, 0)); len(got) != 3 {
+				t.Errorf("Occurrences() = %v, want 3", got)
+			}
+		})
+	}
+}
+
+func TestRecurrenceRuleOccurrencesLeapDay(t *testing.T) {
+	// 2100 no es bisiesto: el 29 de febrero saltea 8 años
+	dtstart := time.Date(2096, 2, 29, 9, 0, 0, 0, time.UTC)
+	rule, err := ParseRecurrenceRule("FREQ=YEARLY;INTERVAL=4;COUNT=3")
+	if err != nil {
+		t.Fatalf("ParseRecurrenceRule() error = %v", err)
+	}
+
+	got := rule.Occurrences(dtstart, time.Hour, dtstart, dtstart.AddDate(20, 0, 0))
+	want := []string{"2096-02-29", "2104-02-29", "2108-02-29"}
+	if len(got) != len(want) {
+		t.Fatalf("Occurrences() returned %d, want %d (%v)", len(got), len(want), got)
+	}
+	for i, occ := range got {
+		if occ.Format("2006-01-02") != want[i] {
+			t.Errorf("occurrence %d = %s, want %s", i, occ.Format("2006-01-02"), want[i])
+		}
+	}
+}
+
+func TestExpandOccurrences(t *testing.T) {
+	dtstart := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	entry := NewEntry("12345", "Stand-up", dtstart, 15)
+	entry.Tags = []string{"team"}
+	entry.RRule, _ = ParseRecurrenceRule("FREQ=DAILY")
+
+	from := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+	occurrences := entry.ExpandOccurrences(from, to)
+
+	if len(occurrences) != 2 {
+		t.Fatalf("Expected 2 occurrences, got %d", len(occurrences))
+	}
+
+	occ := occurrences[0]
+	if occ.ID != entry.ID {
+		t.Errorf("Expected occurrence ID %s, got %s", entry.ID, occ.ID)
+	}
+	if !occ.IsOccurrence() || occ.IsRecurring() {
+		t.Error("Expected occurrence to be marked as occurrence")
+	}
+	if !entry.IsRecurring() {
+		t.Error("Expected master to be recurring")
+	}
+
+	// Modificar la ocurrencia no debe afectar al maestro
+	occ.AddTag("extra")
+	if entry.HasTag("extra") {
+		t.Error("Expected occurrence tags to be independent from master")
+	}
+}
+
+func TestExpandOccurrencesMultiDay(t *testing.T) {
+	// Fin de semana largo cada mes: viernes a domingo
+	entry := NewEntry("12345", "Escapada", time.Date(2025, 11, 7, 0, 0, 0, 0, time.UTC), 0)
+	entry.AllDay = true
+	end := time.Date(2025, 11, 9, 0, 0, 0, 0, time.UTC)
+	entry.EndDate = &end
+	entry.RRule, _ = ParseRecurrenceRule("FREQ=WEEKLY;INTERVAL=4")
+
+	// Ventana que comienza a mitad de la segunda ocurrencia (5 al 7 de diciembre)
+	from := time.Date(2025, 12, 6, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	occurrences := entry.ExpandOccurrences(from, to)
+
+	if len(occurrences) != 1 {
+		t.Fatalf("Expected 1 occurrence, got %d", len(occurrences))
+	}
+
+	occ := occurrences[0]
+	if !occ.DateTime.Equal(time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected occurrence start %v", occ.DateTime)
+	}
+	if occ.EndDate == nil || !occ.EndDate.Equal(time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected end date to move with the occurrence, got %v", occ.EndDate)
+	}
+}
+
+func TestRecurrenceRuleJSON(t *testing.T) {
+	entry := NewEntry("12345", "Weekly", time.Now(), 30)
+	entry.RRule, _ = ParseRecurrenceRule("FREQ=WEEKLY;BYDAY=MO;COUNT=4")
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var decoded Entry
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.RRule == nil || decoded.RRule.String() != entry.RRule.String() {
+		t.Errorf("Expected rrule %s, got %v", entry.RRule, decoded.RRule)
+	}
+}
+
+func TestExpandOccurrencesTZID(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	entry := NewEntry("12345", "Weekly", time.Date(2025, 10, 20, 10, 0, 0, 0, loc), 30)
+	entry.TZID = "Europe/Madrid"
+	entry.RRule, _ = ParseRecurrenceRule("FREQ=WEEKLY;COUNT=3")
+
+	// Tras serializar, DateTime queda con un offset fijo (+02:00)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded Entry
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	occurrences := decoded.ExpandOccurrences(decoded.DateTime, decoded.DateTime.AddDate(0, 1, 0))
+	if len(occurrences) != 3 {
+		t.Fatalf("Expected 3 occurrences, got %d", len(occurrences))
+	}
+
+	// El cambio de horario (26/10) no debe mover la hora local
+	for _, occ := range occurrences {
+		if got := occ.DateTime.In(loc).Format("15:04"); got != "10:00" {
+			t.Errorf("Expected occurrence at 10:00 Madrid, got %s (%v)", got, occ.DateTime)
+		}
+	}
+	if !decoded.HasOccurrence(occurrences[2].DateTime) {
+		t.Error("Expected HasOccurrence to match an occurrence after the DST change")
+	}
+}
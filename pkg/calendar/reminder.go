@@ -0,0 +1,96 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxReminder es la máxima anticipación de un recordatorio (minutos)
+const MaxReminder = 7 * 24 * 60
+
+// ParseReminders parsea una lista de anticipaciones separadas por coma
+// ("15m,1h,1d,1w", "0" = al inicio) y retorna los minutos ordenados y sin
+// repetidos. Un string vacío retorna una lista vacía.
+func ParseReminders(s string) ([]int, error) {
+	reminders := []int{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		minutes, err := parseReminder(part)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, minutes)
+	}
+	return NormalizeReminders(reminders), nil
+}
+
+// parseReminder parsea una anticipación: un número con unidad m, h, d o w
+// (o una duración de Go como 1h30m)
+func parseReminder(s string) (int, error) {
+	if s == "0" {
+		return 0, nil
+	}
+
+	units := map[byte]int{'m': 1, 'h': 60, 'd': 24 * 60, 'w': 7 * 24 * 60}
+	var minutes int
+	if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && units[s[len(s)-1]] > 0 {
+		minutes = n * units[s[len(s)-1]]
+	} else if d, err := time.ParseDuration(s); err == nil && d%time.Minute == 0 {
+		minutes = int(d / time.Minute)
+	} else {
+		return 0, fmt.Errorf("recordatorio inválido: %s (usar 15m, 2h, 1d, 1w)", s)
+	}
+
+	if minutes < 0 || minutes > MaxReminder {
+		return 0, fmt.Errorf("recordatorio inválido: %s (máximo 1w)", s)
+	}
+	return minutes, nil
+}
+
+// NormalizeReminders ordena los recordatorios y elimina los repetidos
+func NormalizeReminders(reminders []int) []int {
+	sort.Ints(reminders)
+	result := reminders[:0]
+	for _, r := range reminders {
+		if len(result) == 0 || r != result[len(result)-1] {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// FormatReminder formatea una anticipación en su unidad más grande exacta (15m, 2h, 1d, 1w)
+func FormatReminder(minutes int) string {
+	switch {
+	case minutes == 0:
+		return "0m"
+	case minutes%(7*24*60) == 0:
+		return fmt.Sprintf("%dw", minutes/(7*24*60))
+	case minutes%(24*60) == 0:
+		return fmt.Sprintf("%dd", minutes/(24*60))
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// FormatReminders formatea una lista de recordatorios como la acepta ParseReminders
+func FormatReminders(reminders []int) string {
+	parts := make([]string, len(reminders))
+	for i, r := range reminders {
+		parts[i] = FormatReminder(r)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ReminderTime retorna cuándo se dispara el recordatorio de minutes minutos antes del inicio
+func (e *Entry) ReminderTime(minutes int) time.Time {
+	return e.DateTime.Add(-time.Duration(minutes) * time.Minute)
+}
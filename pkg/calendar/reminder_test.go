@@ -0,0 +1,49 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseReminders(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"15m", "15m", false},
+		{"1d, 15m", "15m,1d", false},
+		{"2h,120m", "2h", false},
+		{"1h30m", "90m", false},
+		{"0", "0m", false},
+		{"1w", "1w", false},
+		{"", "", false},
+		{"8d", "", true},
+		{"-5m", "", true},
+		{"soon", "", true},
+		{"30s", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseReminders(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReminders(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && FormatReminders(got) != tt.want {
+				t.Errorf("ParseReminders(%q) = %v, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReminderTime(t *testing.T) {
+	entry := NewEntry("12345", "Reunión", time.Date(2025, 12, 22, 9, 0, 0, 0, time.UTC), 30)
+
+	if got := entry.ReminderTime(15); !got.Equal(time.Date(2025, 12, 22, 8, 45, 0, 0, time.UTC)) {
+		t.Errorf("Expected 08:45, got %v", got)
+	}
+	if got := entry.ReminderTime(24 * 60); !got.Equal(time.Date(2025, 12, 21, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected previous day, got %v", got)
+	}
+}
@@ -0,0 +1,168 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OccurrenceScope indica a qué ocurrencias de una serie aplica un cambio
+type OccurrenceScope string
+
+const (
+	ScopeThis      OccurrenceScope = "this"
+	ScopeFollowing OccurrenceScope = "following"
+	ScopeAll       OccurrenceScope = "all"
+)
+
+// ParseOccurrenceScope parsea un scope de ocurrencia
+func ParseOccurrenceScope(s string) (OccurrenceScope, error) {
+	switch OccurrenceScope(s) {
+	case ScopeThis, ScopeFollowing, ScopeAll:
+		return OccurrenceScope(s), nil
+	default:
+		return "", fmt.Errorf("scope inválido: %s (usar: this, following, all)", s)
+	}
+}
+
+// IsExcluded verifica si la ocurrencia que comienza en t fue cancelada (EXDATE)
+func (e *Entry) IsExcluded(t time.Time) bool {
+	for _, ex := range e.ExDates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasOccurrence verifica si t es el inicio de una ocurrencia vigente de la serie
+func (e *Entry) HasOccurrence(t time.Time) bool {
+	if e.RRule == nil || e.IsExcluded(t) {
+		return false
+	}
+	for _, occ := range e.RRule.Occurrences(e.LocalDateTime(), 0, t, t) {
+		if occ.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindOccurrence retorna el inicio de la ocurrencia vigente que cae en el día
+// de date (en la zona horaria de date)
+func (e *Entry) FindOccurrence(date time.Time) (time.Time, error) {
+	if !e.IsRecurring() {
+		return time.Time{}, fmt.Errorf("el evento %s no es recurrente", e.ID)
+	}
+
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	dayEnd := dayStart.Add(24 * time.Hour)
+
+	for _, occ := range e.ExpandOccurrences(dayStart, dayEnd) {
+		if !occ.DateTime.Before(dayStart) && occ.DateTime.Before(dayEnd) {
+			return occ.DateTime, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("el evento %s no tiene ocurrencia el %s", e.ID, dayStart.Format("2006-01-02"))
+}
+
+// ExcludeOccurrence cancela una ocurrencia agregándola a EXDATE
+func (e *Entry) ExcludeOccurrence(t time.Time) {
+	if !e.IsExcluded(t) {
+		e.ExDates = append(e.ExDates, t)
+		sort.Slice(e.ExDates, func(i, j int) bool {
+			return e.ExDates[i].Before(e.ExDates[j])
+		})
+	}
+}
+
+// TruncateBefore termina la serie justo antes de la ocurrencia que comienza en t.
+// COUNT se reemplaza por un UNTIL equivalente.
+func (e *Entry) TruncateBefore(t time.Time) {
+	if e.RRule == nil {
+		return
+	}
+
+	rule := *e.RRule
+	until := t.Add(-time.Second)
+	rule.Until = &until
+	rule.Count = 0
+	e.RRule = &rule
+
+	var exdates []time.Time
+	for _, ex := range e.ExDates {
+		if ex.Before(t) {
+			exdates = append(exdates, ex)
+		}
+	}
+	e.ExDates = exdates
+}
+
+// SplitAt divide la serie en la ocurrencia que comienza en t: retorna un nuevo
+// evento maestro (con nuevo ID) desde t en adelante. El receptor no se modifica;
+// usar TruncateBefore para terminar la serie original.
+func (e *Entry) SplitAt(t time.Time) *Entry {
+	next := *e
+	next.ID = GenerateID()
+	next.DateTime = t.In(e.TimeZone())
+	next.CreatedAt = time.Now()
+	next.UpdatedAt = next.CreatedAt
+	next.RecurrenceID = nil
+
+	next.Tags = append([]string(nil), e.Tags...)
+	next.Metadata = make(map[string]string, len(e.Metadata))
+	for k, v := range e.Metadata {
+		next.Metadata[k] = v
+	}
+
+	if e.RRule != nil {
+		rule := *e.RRule
+		if rule.Count > 0 {
+			before := e.RRule.Occurrences(e.LocalDateTime(), 0, e.DateTime, t.Add(-time.Nanosecond))
+			rule.Count -= len(before)
+		}
+		next.RRule = &rule
+	}
+
+	next.ExDates = nil
+	for _, ex := range e.ExDates {
+		if !ex.Before(t) {
+			next.ExDates = append(next.ExDates, ex)
+		}
+	}
+
+	return &next
+}
+
+// ExpandSeries expande un evento maestro en [from, to] aplicando EXDATE y los
+// overrides por ocurrencia. Un override reemplaza a su ocurrencia original y se
+// incluye si se superpone con la ventana, aunque haya sido movido fuera de ella.
+func ExpandSeries(master *Entry, overrides []*Entry, from, to time.Time) []*Entry {
+	byRecurrenceID := make(map[int64]*Entry, len(overrides))
+	for _, o := range overrides {
+		if o.RecurrenceID != nil && master.HasOccurrence(*o.RecurrenceID) {
+			byRecurrenceID[o.RecurrenceID.Unix()] = o
+		}
+	}
+
+	var result []*Entry
+	for _, occ := range master.ExpandOccurrences(from, to) {
+		if _, ok := byRecurrenceID[occ.RecurrenceID.Unix()]; ok {
+			continue
+		}
+		result = append(result, occ)
+	}
+
+	for _, o := range byRecurrenceID {
+		if o.EndTime().After(from) && !o.DateTime.After(to) {
+			result = append(result, o)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DateTime.Before(result[j].DateTime)
+	})
+
+	return result
+}
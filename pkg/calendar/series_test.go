@@ -0,0 +1,130 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func newDailySeries(t *testing.T, rule string) *Entry {
+	t.Helper()
+	dtstart := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	entry := NewEntry("12345", "Daily", dtstart, 30)
+	r, err := ParseRecurrenceRule(rule)
+	if err != nil {
+		t.Fatalf("ParseRecurrenceRule() error = %v", err)
+	}
+	entry.RRule = r
+	return entry
+}
+
+func TestExcludeOccurrence(t *testing.T) {
+	entry := newDailySeries(t, "FREQ=DAILY")
+	excluded := time.Date(2025, 11, 4, 9, 0, 0, 0, time.UTC)
+
+	entry.ExcludeOccurrence(excluded)
+	entry.ExcludeOccurrence(excluded)
+
+	if len(entry.ExDates) != 1 {
+		t.Errorf("Expected 1 exdate, got %d", len(entry.ExDates))
+	}
+	if entry.HasOccurrence(excluded) {
+		t.Error("Expected excluded occurrence to not be valid")
+	}
+
+	occurrences := entry.ExpandOccurrences(entry.DateTime, entry.DateTime.Add(72*time.Hour))
+	if len(occurrences) != 3 {
+		t.Errorf("Expected 3 occurrences, got %d", len(occurrences))
+	}
+	for _, occ := range occurrences {
+		if occ.DateTime.Equal(excluded) {
+			t.Error("Expected excluded occurrence to be skipped")
+		}
+	}
+}
+
+func TestFindOccurrence(t *testing.T) {
+	entry := newDailySeries(t, "FREQ=WEEKLY")
+
+	got, err := entry.FindOccurrence(time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("FindOccurrence() error = %v", err)
+	}
+	if !got.Equal(time.Date(2025, 11, 10, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("FindOccurrence() = %v", got)
+	}
+
+	if _, err := entry.FindOccurrence(time.Date(2025, 11, 11, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("Expected error for day without occurrence")
+	}
+}
+
+func TestSplitAndTruncate(t *testing.T) {
+	entry := newDailySeries(t, "FREQ=DAILY;COUNT=10")
+	split := time.Date(2025, 11, 7, 9, 0, 0, 0, time.UTC)
+	entry.ExcludeOccurrence(time.Date(2025, 11, 5, 9, 0, 0, 0, time.UTC))
+	entry.ExcludeOccurrence(time.Date(2025, 11, 8, 9, 0, 0, 0, time.UTC))
+
+	next := entry.SplitAt(split)
+	entry.TruncateBefore(split)
+
+	if next.ID == entry.ID {
+		t.Error("Expected new series to have a new ID")
+	}
+	if next.RRule.Count != 6 {
+		t.Errorf("Expected remaining COUNT 6, got %d", next.RRule.Count)
+	}
+	if len(next.ExDates) != 1 || len(entry.ExDates) != 1 {
+		t.Errorf("Expected exdates to be split, got %d and %d", len(entry.ExDates), len(next.ExDates))
+	}
+
+	window := entry.DateTime.AddDate(0, 1, 0)
+	before := entry.ExpandOccurrences(entry.DateTime, window)
+	after := next.ExpandOccurrences(entry.DateTime, window)
+
+	// 4 ocurrencias antes del corte (menos 1 excluida) y 6 desde el corte (menos 1 excluida)
+	if len(before) != 3 {
+		t.Errorf("Expected 3 occurrences before split, got %d", len(before))
+	}
+	if len(after) != 5 {
+		t.Errorf("Expected 5 occurrences after split, got %d", len(after))
+	}
+}
+
+func TestExpandSeriesWithOverrides(t *testing.T) {
+	entry := newDailySeries(t, "FREQ=DAILY")
+
+	// Mover la ocurrencia del 5/11 al 7/11 a las 16:00
+	moved := entry.Occurrence(time.Date(2025, 11, 5, 9, 0, 0, 0, time.UTC))
+	moved.DateTime = time.Date(2025, 11, 7, 16, 0, 0, 0, time.UTC)
+
+	// Override huérfano: no corresponde a ninguna ocurrencia
+	orphan := entry.Occurrence(time.Date(2025, 11, 6, 10, 0, 0, 0, time.UTC))
+
+	overrides := []*Entry{moved, orphan}
+
+	day5 := time.Date(2025, 11, 5, 0, 0, 0, 0, time.UTC)
+	got := ExpandSeries(entry, overrides, day5, day5.Add(24*time.Hour))
+	if len(got) != 0 {
+		t.Errorf("Expected moved occurrence to leave day 5 empty, got %d", len(got))
+	}
+
+	day7 := time.Date(2025, 11, 7, 0, 0, 0, 0, time.UTC)
+	got = ExpandSeries(entry, overrides, day7, day7.Add(24*time.Hour))
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 occurrences on day 7, got %d", len(got))
+	}
+	if got[1] != moved {
+		t.Error("Expected moved override to be included on day 7")
+	}
+}
+
+func TestParseOccurrenceScope(t *testing.T) {
+	for _, s := range []string{"this", "following", "all"} {
+		if _, err := ParseOccurrenceScope(s); err != nil {
+			t.Errorf("ParseOccurrenceScope(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseOccurrenceScope("some"); err == nil {
+		t.Error("Expected error for invalid scope")
+	}
+}
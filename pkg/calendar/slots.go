@@ -0,0 +1,118 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DayWindow es una franja del día en minutos desde la medianoche, [Start, End)
+type DayWindow struct {
+	Start int
+	End   int
+}
+
+// SlotQuery describe la búsqueda de huecos libres en el calendario
+type SlotQuery struct {
+	Duration time.Duration                // duración del hueco buscado
+	From     time.Time                    // inicio de la búsqueda (su zona define los días)
+	To       time.Time                    // fin de la búsqueda
+	Hours    map[time.Weekday][]DayWindow // franjas disponibles por día; sin franjas no se busca ese día
+	Buffer   time.Duration                // tiempo libre a dejar antes y después de cada evento
+	Count    int                          // cantidad máxima de huecos (0 = todos)
+}
+
+// Slot es un hueco libre: Start/End tienen la duración buscada y FreeUntil
+// indica hasta cuándo sigue libre
+type Slot struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	FreeUntil time.Time `json:"free_until"`
+}
+
+// Validate valida los parámetros de la búsqueda
+func (q *SlotQuery) Validate() error {
+	if q.Duration <= 0 {
+		return fmt.Errorf("la duración debe ser mayor a 0")
+	}
+	if !q.To.After(q.From) {
+		return fmt.Errorf("el fin de la búsqueda debe ser posterior al inicio")
+	}
+	if len(q.Hours) == 0 {
+		return fmt.Errorf("no hay franjas horarias para buscar")
+	}
+	for _, windows := range q.Hours {
+		for _, w := range windows {
+			if w.Start < 0 || w.End > 24*60 || w.End <= w.Start {
+				return fmt.Errorf("franja horaria inválida")
+			}
+		}
+	}
+	if q.Buffer < 0 {
+		return fmt.Errorf("el buffer no puede ser negativo")
+	}
+	return nil
+}
+
+// FindFreeSlots busca los huecos libres más tempranos de la duración pedida,
+// dentro de las franjas de cada día y dejando Buffer antes y después de cada evento.
+// Retorna un hueco (el inicio más temprano) por cada bloque libre. Los eventos
+// de día completo no ocupan tiempo; las ocurrencias deben estar expandidas.
+func FindFreeSlots(entries []*Entry, q SlotQuery) []Slot {
+	type interval struct{ start, end time.Time }
+
+	var busy []interval
+	for _, e := range entries {
+		if !blocksTime(e) {
+			continue
+		}
+		busy = append(busy, interval{e.DateTime.Add(-q.Buffer), e.EndTime().Add(q.Buffer)})
+	}
+	sort.Slice(busy, func(i, j int) bool {
+		return busy[i].start.Before(busy[j].start)
+	})
+
+	loc := q.From.Location()
+	var slots []Slot
+
+	for day := StartOfDay(q.From); day.Before(q.To); day = day.AddDate(0, 0, 1) {
+		y, m, d := day.Date()
+		for _, w := range q.Hours[day.Weekday()] {
+			windowStart := time.Date(y, m, d, 0, w.Start, 0, 0, loc)
+			windowEnd := time.Date(y, m, d, 0, w.End, 0, 0, loc)
+			if windowStart.Before(q.From) {
+				windowStart = q.From
+			}
+			if windowEnd.After(q.To) {
+				windowEnd = q.To
+			}
+
+			// Recorrer los eventos de la franja empujando el inicio libre
+			free := windowStart
+			for _, b := range busy {
+				if !b.end.After(free) {
+					continue
+				}
+				if !b.start.Before(windowEnd) {
+					break
+				}
+				if b.start.Sub(free) >= q.Duration {
+					slots = append(slots, Slot{Start: free, End: free.Add(q.Duration), FreeUntil: b.start})
+					if q.Count > 0 && len(slots) >= q.Count {
+						return slots
+					}
+				}
+				free = b.end
+			}
+
+			if windowEnd.Sub(free) >= q.Duration {
+				slots = append(slots, Slot{Start: free, End: free.Add(q.Duration), FreeUntil: windowEnd})
+				if q.Count > 0 && len(slots) >= q.Count {
+					return slots
+				}
+			}
+		}
+	}
+
+	return slots
+}
@@ -0,0 +1,134 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindFreeSlots(t *testing.T) {
+	// Jueves 20 de noviembre de 2025
+	day := time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)
+	at := func(d, h, m int) time.Time {
+		return day.AddDate(0, 0, d).Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+
+	entries := []*Entry{
+		NewEntry("12345", "Stand-up", at(0, 9, 0), 30),
+		NewEntry("12345", "Review", at(0, 10, 0), 60),
+		NewEntry("12345", "Workshop", at(0, 11, 30), 330),
+		{Title: "Feriado", DateTime: at(1, 0, 0), AllDay: true},
+	}
+
+	everyDay := func(windows ...DayWindow) map[time.Weekday][]DayWindow {
+		hours := make(map[time.Weekday][]DayWindow)
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			hours[d] = windows
+		}
+		return hours
+	}
+
+	base := SlotQuery{
+		Duration: 45 * time.Minute,
+		From:     at(0, 0, 0),
+		To:       at(4, 0, 0),
+		Hours:    everyDay(DayWindow{9 * 60, 18 * 60}),
+	}
+
+	tests := []struct {
+		name   string
+		modify func(q *SlotQuery)
+		want   []time.Time
+	}{
+		{
+			name:   "earliest slots",
+			modify: func(q *SlotQuery) { q.Count = 3 },
+			want:   []time.Time{at(0, 17, 0), at(1, 9, 0), at(2, 9, 0)},
+		},
+		{
+			name:   "short duration fits between meetings",
+			modify: func(q *SlotQuery) { q.Duration = 30 * time.Minute; q.Count = 2 },
+			want:   []time.Time{at(0, 9, 30), at(0, 11, 0)},
+		},
+		{
+			name:   "buffer between meetings",
+			modify: func(q *SlotQuery) { q.Duration = 30 * time.Minute; q.Buffer = 15 * time.Minute; q.Count = 1 },
+			want:   []time.Time{at(0, 17, 15)},
+		},
+		{
+			name: "only weekdays",
+			modify: func(q *SlotQuery) {
+				q.Hours = everyDay(DayWindow{9 * 60, 18 * 60})
+				delete(q.Hours, time.Saturday)
+				delete(q.Hours, time.Sunday)
+				q.To = at(5, 0, 0)
+			},
+			want: []time.Time{at(0, 17, 0), at(1, 9, 0), at(4, 9, 0)},
+		},
+		{
+			name: "split shift",
+			modify: func(q *SlotQuery) {
+				q.Hours = everyDay(DayWindow{8 * 60, 9 * 60}, DayWindow{16 * 60, 20 * 60})
+				q.Duration = time.Hour
+				q.Count = 3
+			},
+			want: []time.Time{at(0, 8, 0), at(0, 17, 0), at(1, 8, 0)},
+		},
+		{
+			name:   "search starts mid-day",
+			modify: func(q *SlotQuery) { q.From = at(1, 16, 40); q.To = at(2, 0, 0) },
+			want:   []time.Time{at(1, 16, 40)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := base
+			tt.modify(&q)
+			if err := q.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			slots := FindFreeSlots(entries, q)
+			if len(slots) != len(tt.want) {
+				t.Fatalf("Expected %d slots, got %d: %v", len(tt.want), len(slots), slots)
+			}
+			for i, want := range tt.want {
+				if !slots[i].Start.Equal(want) {
+					t.Errorf("Slot %d: expected %v, got %v", i, want, slots[i].Start)
+				}
+				if !slots[i].End.Equal(want.Add(q.Duration)) {
+					t.Errorf("Slot %d: unexpected end %v", i, slots[i].End)
+				}
+			}
+		})
+	}
+}
+
+func TestSlotQueryValidate(t *testing.T) {
+	from := time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)
+	valid := SlotQuery{
+		Duration: time.Hour,
+		From:     from,
+		To:       from.AddDate(0, 0, 1),
+		Hours:    map[time.Weekday][]DayWindow{time.Thursday: {{540, 1080}}},
+	}
+
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected valid query, got %v", err)
+	}
+
+	invalid := []func(q *SlotQuery){
+		func(q *SlotQuery) { q.Duration = 0 },
+		func(q *SlotQuery) { q.To = q.From },
+		func(q *SlotQuery) { q.Hours = nil },
+		func(q *SlotQuery) { q.Hours = map[time.Weekday][]DayWindow{time.Monday: {{600, 540}}} },
+		func(q *SlotQuery) { q.Buffer = -time.Minute },
+	}
+	for i, modify := range invalid {
+		q := valid
+		modify(&q)
+		if err := q.Validate(); err == nil {
+			t.Errorf("Case %d: expected error", i)
+		}
+	}
+}
@@ -0,0 +1,442 @@
+// Package csvio implementa la importación y exportación de entradas del
+// calendario en formato CSV con un mapeo de columnas configurable
+package csvio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+// Campos de Entry que se pueden mapear a columnas
+const (
+	FieldDateTime = "datetime"
+	FieldTitle    = "title"
+	FieldDuration = "duration"
+	FieldLocation = "location"
+	FieldNotes    = "notes"
+	FieldTags     = "tags"
+	FieldAllDay   = "all_day"
+	FieldEnd      = "end"
+
+	// metaPrefix identifica columnas que se guardan en Entry.Metadata (meta:clave)
+	metaPrefix = "meta:"
+)
+
+// DefaultDateFormat es el formato de fecha por defecto de la columna datetime
+const DefaultDateFormat = "2006-01-02 15:04"
+
+// dayFormat es el formato de las fechas de eventos de día completo
+const dayFormat = "2006-01-02"
+
+// Column asocia un campo de Entry con el encabezado de una columna CSV
+type Column struct {
+	Field  string
+	Header string
+}
+
+// Mapping describe cómo se corresponden las columnas CSV con los campos de Entry
+type Mapping struct {
+	Columns      []Column
+	DateFormat   string
+	TagSeparator string
+	Comma        rune
+}
+
+// DefaultMapping retorna el mapeo por defecto: una columna por campo, con el
+// nombre del campo como encabezado
+func DefaultMapping() *Mapping {
+	fields := []string{FieldDateTime, FieldTitle, FieldDuration, FieldLocation, FieldNotes, FieldTags, FieldAllDay, FieldEnd}
+	columns := make([]Column, len(fields))
+	for i, f := range fields {
+		columns[i] = Column{Field: f, Header: f}
+	}
+	return &Mapping{
+		Columns:      columns,
+		DateFormat:   DefaultDateFormat,
+		TagSeparator: ";",
+		Comma:        ',',
+	}
+}
+
+// ParseMapping parsea una especificación de columnas del tipo
+// "datetime=Start,title=Subject,meta:project=Project". Si se omite "=Encabezado"
+// se usa el nombre del campo.
+func ParseMapping(spec string) (*Mapping, error) {
+	m := DefaultMapping()
+	if strings.TrimSpace(spec) == "" {
+		return m, nil
+	}
+
+	m.Columns = nil
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		field, header := part, part
+		if i := strings.Index(part, "="); i >= 0 {
+			field, header = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+		field = strings.ToLower(field)
+
+		if !isValidField(field) {
+			return nil, fmt.Errorf("campo desconocido: %s", field)
+		}
+		if header == "" {
+			return nil, fmt.Errorf("encabezado vacío para el campo %s", field)
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("campo duplicado: %s", field)
+		}
+		seen[field] = true
+
+		m.Columns = append(m.Columns, Column{Field: field, Header: header})
+	}
+
+	if len(m.Columns) == 0 {
+		return nil, fmt.Errorf("el mapeo no tiene columnas")
+	}
+
+	return m, nil
+}
+
+// isValidField verifica si el nombre corresponde a un campo mapeable
+func isValidField(field string) bool {
+	switch field {
+	case FieldDateTime, FieldTitle, FieldDuration, FieldLocation, FieldNotes, FieldTags, FieldAllDay, FieldEnd:
+		return true
+	}
+	return strings.HasPrefix(field, metaPrefix) && len(field) > len(metaPrefix)
+}
+
+// RowError es un error de validación de una fila del CSV
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("línea %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Result contiene las entradas válidas y los errores por fila
+type Result struct {
+	Entries []*calendar.Entry
+	Lines   []int // número de línea de cada entrada de Entries
+	Errors  []*RowError
+}
+
+// Reader lee entradas desde un CSV con encabezado
+type Reader struct {
+	r               io.Reader
+	mapping         *Mapping
+	userID          string
+	loc             *time.Location
+	defaultDuration int
+}
+
+// NewReader crea un lector para userID. Las fechas se interpretan en loc.
+func NewReader(r io.Reader, mapping *Mapping, userID string, loc *time.Location) *Reader {
+	if mapping == nil {
+		mapping = DefaultMapping()
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	return &Reader{
+		r:               r,
+		mapping:         mapping,
+		userID:          userID,
+		loc:             loc,
+		defaultDuration: 60,
+	}
+}
+
+// WithDefaultDuration establece la duración (minutos) de filas sin duración
+func (r *Reader) WithDefaultDuration(minutes int) *Reader {
+	if minutes > 0 {
+		r.defaultDuration = minutes
+	}
+	return r
+}
+
+// ReadAll lee todas las filas. Las filas inválidas se reportan en
+// Result.Errors con su número de línea y no detienen la lectura.
+func (r *Reader) ReadAll() (*Result, error) {
+	cr := csv.NewReader(r.r)
+	cr.Comma = r.mapping.Comma
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("el archivo CSV está vacío")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo encabezado: %w", err)
+	}
+
+	index, err := r.columnIndex(header)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				result.Errors = append(result.Errors, &RowError{Line: parseErr.Line, Err: parseErr.Err})
+				continue
+			}
+			return nil, err
+		}
+
+		if isBlank(record) {
+			continue
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		entry, err := r.parseRecord(record, index)
+		if err != nil {
+			result.Errors = append(result.Errors, &RowError{Line: line, Err: err})
+			continue
+		}
+		result.Entries = append(result.Entries, entry)
+		result.Lines = append(result.Lines, line)
+	}
+
+	return result, nil
+}
+
+// columnIndex ubica cada campo del mapeo en el encabezado (sin distinguir mayúsculas)
+func (r *Reader) columnIndex(header []string) (map[string]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, h := range header {
+		positions[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+
+	index := make(map[string]int)
+	for _, col := range r.mapping.Columns {
+		if i, ok := positions[strings.ToLower(col.Header)]; ok {
+			index[col.Field] = i
+		}
+	}
+
+	for _, required := range []string{FieldDateTime, FieldTitle} {
+		if _, ok := index[required]; !ok {
+			return nil, fmt.Errorf("falta la columna requerida para %s", required)
+		}
+	}
+
+	return index, nil
+}
+
+// parseRecord convierte una fila en una Entry validada
+func (r *Reader) parseRecord(record []string, index map[string]int) (*calendar.Entry, error) {
+	value := func(field string) string {
+		if i, ok := index[field]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	allDay := false
+	if s := value(FieldAllDay); s != "" {
+		b, err := strconv.ParseBool(strings.ToLower(s))
+		if err != nil {
+			return nil, fmt.Errorf("all_day inválido %q", s)
+		}
+		allDay = b
+	}
+
+	var datetime time.Time
+	if s := value(FieldDateTime); s != "" {
+		t, err := r.parseDate(s, allDay)
+		if err != nil {
+			return nil, fmt.Errorf("datetime inválido %q (formato esperado: %s)", s, r.mapping.DateFormat)
+		}
+		datetime = t
+	}
+
+	var end *time.Time
+	if s := value(FieldEnd); s != "" {
+		t, err := r.parseDate(s, allDay)
+		if err != nil {
+			return nil, fmt.Errorf("end inválido %q (formato esperado: %s)", s, r.mapping.DateFormat)
+		}
+		end = &t
+	}
+
+	duration := r.defaultDuration
+	if s := value(FieldDuration); s != "" {
+		d, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("duration inválido %q", s)
+		}
+		duration = d
+	}
+
+	entry := calendar.NewEntry(r.userID, value(FieldTitle), datetime, duration)
+	if name := r.loc.String(); name != "Local" {
+		entry.TZID = name
+	}
+	entry.AllDay = allDay
+	entry.EndDate = end
+	if allDay {
+		entry.Duration = 0
+	} else if end != nil {
+		entry.Duration = entry.Minutes()
+	}
+	entry.Location = value(FieldLocation)
+	entry.Notes = value(FieldNotes)
+
+	if s := value(FieldTags); s != "" {
+		for _, tag := range strings.Split(s, r.mapping.TagSeparator) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				entry.AddTag(tag)
+			}
+		}
+	}
+
+	for _, col := range r.mapping.Columns {
+		if key := strings.TrimPrefix(col.Field, metaPrefix); key != col.Field {
+			if v := value(col.Field); v != "" {
+				entry.Metadata[key] = v
+			}
+		}
+	}
+
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// parseDate parsea una fecha con el formato del mapeo; en eventos de día
+// completo también acepta YYYY-MM-DD
+func (r *Reader) parseDate(s string, allDay bool) (time.Time, error) {
+	t, err := time.ParseInLocation(r.mapping.DateFormat, s, r.loc)
+	if err != nil && allDay {
+		return time.ParseInLocation(dayFormat, s, r.loc)
+	}
+	return t, err
+}
+
+// isBlank indica si todos los campos de la fila están vacíos
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Writer escribe entradas como CSV con encabezado
+type Writer struct {
+	w       io.Writer
+	mapping *Mapping
+	loc     *time.Location
+}
+
+// NewWriter crea un escritor que formatea las fechas en loc (nil = zona de la entrada)
+func NewWriter(w io.Writer, mapping *Mapping, loc *time.Location) *Writer {
+	if mapping == nil {
+		mapping = DefaultMapping()
+	}
+	return &Writer{
+		w:       w,
+		mapping: mapping,
+		loc:     loc,
+	}
+}
+
+// WriteAll escribe el encabezado y una fila por entrada
+func (w *Writer) WriteAll(entries []*calendar.Entry) error {
+	cw := csv.NewWriter(w.w)
+	cw.Comma = w.mapping.Comma
+
+	header := make([]string, len(w.mapping.Columns))
+	for i, col := range w.mapping.Columns {
+		header[i] = col.Header
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := cw.Write(w.record(entry)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// record arma la fila de una entrada según el mapeo
+func (w *Writer) record(entry *calendar.Entry) []string {
+	record := make([]string, len(w.mapping.Columns))
+	for i, col := range w.mapping.Columns {
+		switch col.Field {
+		case FieldDateTime:
+			record[i] = w.formatDate(entry, entry.DateTime)
+		case FieldTitle:
+			record[i] = entry.Title
+		case FieldDuration:
+			if !entry.AllDay {
+				record[i] = strconv.Itoa(entry.Minutes())
+			}
+		case FieldAllDay:
+			if entry.AllDay {
+				record[i] = "true"
+			}
+		case FieldEnd:
+			if entry.EndDate != nil {
+				record[i] = w.formatDate(entry, *entry.EndDate)
+			}
+		case FieldLocation:
+			record[i] = entry.Location
+		case FieldNotes:
+			record[i] = entry.Notes
+		case FieldTags:
+			record[i] = strings.Join(entry.Tags, w.mapping.TagSeparator)
+		default:
+			record[i] = entry.Metadata[strings.TrimPrefix(col.Field, metaPrefix)]
+		}
+	}
+	return record
+}
+
+// formatDate formatea una fecha de la entrada: en la zona del escritor, o como
+// día (YYYY-MM-DD, en la zona del evento) si es de día completo
+func (w *Writer) formatDate(entry *calendar.Entry, t time.Time) string {
+	if entry.AllDay {
+		return t.In(entry.TimeZone()).Format(dayFormat)
+	}
+	if w.loc != nil {
+		t = t.In(w.loc)
+	}
+	return t.Format(w.mapping.DateFormat)
+}
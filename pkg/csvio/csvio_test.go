@@ -0,0 +1,171 @@
+package csvio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+func TestParseMapping(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []Column
+		wantErr bool
+	}{
+		{"", DefaultMapping().Columns, false},
+		{"datetime=Start,title=Subject", []Column{{FieldDateTime, "Start"}, {FieldTitle, "Subject"}}, false},
+		{"Title,meta:project=Project", []Column{{FieldTitle, "Title"}, {"meta:project", "Project"}}, false},
+		{"datetime=Start,unknown=X", nil, true},
+		{"title=A,title=B", nil, true},
+		{"meta:=X", nil, true},
+		{"title=", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMapping(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMapping(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if len(got.Columns) != len(tt.want) {
+			t.Errorf("ParseMapping(%q) = %v, want %v", tt.spec, got.Columns, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if got.Columns[i] != tt.want[i] {
+				t.Errorf("ParseMapping(%q) column %d = %v, want %v", tt.spec, i, got.Columns[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	input := "Start,Subject,Minutes,Labels,Project\n" +
+		"2025-11-20 09:00,Standup,15,work;team,alpha\n" +
+		"2025-11-21 25:00,Bad time,30,,\n" +
+		",,,,\n" +
+		"2025-11-22 10:00,,30,,\n" +
+		"2025-11-23 11:00,\"Review, Q4\",abc,,\n" +
+		"2025-11-24 12:00,Lunch,,,\n" +
+		"2025-11-25 12:00,Negative,-5,,\n"
+
+	mapping, err := ParseMapping("datetime=Start,title=Subject,duration=Minutes,tags=Labels,meta:project=Project")
+	if err != nil {
+		t.Fatalf("ParseMapping() error = %v", err)
+	}
+
+	result, err := NewReader(strings.NewReader(input), mapping, "12345", time.UTC).WithDefaultDuration(45).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	if len(result.Entries) != 2 {
+		t.Fatalf("Expected 2 valid entries, got %d", len(result.Entries))
+	}
+
+	standup := result.Entries[0]
+	if standup.Title != "Standup" || standup.Duration != 15 || standup.UserID != "12345" {
+		t.Errorf("Unexpected entry: %q %d %q", standup.Title, standup.Duration, standup.UserID)
+	}
+	if !standup.DateTime.Equal(time.Date(2025, 11, 20, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected datetime %v", standup.DateTime)
+	}
+	if len(standup.Tags) != 2 || standup.Tags[1] != "team" {
+		t.Errorf("Unexpected tags %v", standup.Tags)
+	}
+	if standup.Metadata["project"] != "alpha" {
+		t.Errorf("Expected project metadata, got %v", standup.Metadata)
+	}
+
+	if len(result.Lines) != 2 || result.Lines[0] != 2 || result.Lines[1] != 7 {
+		t.Errorf("Expected entry lines [2 7], got %v", result.Lines)
+	}
+
+	if result.Entries[1].Duration != 45 {
+		t.Errorf("Expected default duration 45, got %d", result.Entries[1].Duration)
+	}
+
+	wantLines := []int{3, 5, 6, 8}
+	if len(result.Errors) != len(wantLines) {
+		t.Fatalf("Expected %d errors, got %v", len(wantLines), result.Errors)
+	}
+	for i, line := range wantLines {
+		if result.Errors[i].Line != line {
+			t.Errorf("Error %d: expected line %d, got %d (%v)", i, line, result.Errors[i].Line, result.Errors[i])
+		}
+	}
+}
+
+func TestReadAllMissingColumn(t *testing.T) {
+	_, err := NewReader(strings.NewReader("title,duration\nA,30\n"), nil, "12345", time.UTC).ReadAll()
+	if err == nil {
+		t.Error("Expected error when datetime column is missing")
+	}
+}
+
+func TestWriteAllRoundTrip(t *testing.T) {
+	entry := calendar.NewEntry("12345", "Review, Q4", time.Date(2025, 11, 20, 9, 0, 0, 0, time.UTC), 30)
+	entry.Notes = "line1\nline2"
+	entry.Tags = []string{"work", "team"}
+	entry.Metadata["project"] = "alpha"
+
+	mapping, _ := ParseMapping("datetime,title,duration,notes,tags,meta:project=Project")
+	mapping.Comma = ';'
+
+	var buf bytes.Buffer
+	if err := NewWriter(&buf, mapping, time.UTC).WriteAll([]*calendar.Entry{entry}); err != nil {
+		t.Fatalf("WriteAll() error = %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "datetime;title;duration;notes;tags;Project\n") {
+		t.Errorf("Unexpected header: %q", buf.String())
+	}
+
+	result, err := NewReader(&buf, mapping, "12345", time.UTC).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(result.Entries) != 1 || len(result.Errors) != 0 {
+		t.Fatalf("Expected 1 entry and no errors, got %d / %v", len(result.Entries), result.Errors)
+	}
+
+	got := result.Entries[0]
+	if got.Title != entry.Title || got.Notes != entry.Notes || got.Duration != entry.Duration ||
+		!got.DateTime.Equal(entry.DateTime) || got.Metadata["project"] != "alpha" || len(got.Tags) != 2 {
+		t.Errorf("Round trip mismatch: %+v", got)
+	}
+}
+
+func TestWriteAllRoundTripAllDay(t *testing.T) {
+	entry := calendar.NewEntry("12345", "Vacaciones", time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), 0)
+	entry.AllDay = true
+	end := time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)
+	entry.EndDate = &end
+
+	var buf bytes.Buffer
+	if err := NewWriter(&buf, nil, time.UTC).WriteAll([]*calendar.Entry{entry}); err != nil {
+		t.Fatalf("WriteAll() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "2026-01-05,Vacaciones,,,,,true,2026-01-16") {
+		t.Errorf("Unexpected output: %q", buf.String())
+	}
+
+	result, err := NewReader(&buf, nil, "12345", time.UTC).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(result.Entries) != 1 || len(result.Errors) != 0 {
+		t.Fatalf("Expected 1 entry and no errors, got %d / %v", len(result.Entries), result.Errors)
+	}
+
+	got := result.Entries[0]
+	if !got.AllDay || got.EndDate == nil || !got.EndDate.Equal(end) || !got.DateTime.Equal(entry.DateTime) {
+		t.Errorf("Round trip mismatch: %+v", got)
+	}
+}
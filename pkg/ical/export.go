@@ -0,0 +1,332 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+// Encoder serializa entradas del calendario como un VCALENDAR
+type Encoder struct {
+	w    io.Writer
+	loc  *time.Location
+	name string
+}
+
+// NewEncoder crea un encoder que escribe en w usando la zona horaria loc.
+// Si loc es nil o UTC, las fechas de eventos sin TZID se escriben en UTC.
+func NewEncoder(w io.Writer, loc *time.Location) *Encoder {
+	return &Encoder{
+		w:   w,
+		loc: loc,
+	}
+}
+
+// WithName establece el nombre del calendario (X-WR-CALNAME)
+func (e *Encoder) WithName(name string) *Encoder {
+	e.name = name
+	return e
+}
+
+// Encode escribe un VCALENDAR con un VEVENT por entrada. Los eventos
+// recurrentes se exportan con RRULE/EXDATE y sus overrides con RECURRENCE-ID;
+// no se deben pasar ocurrencias virtuales sin override.
+func (e *Encoder) Encode(entries []*calendar.Entry) error {
+	var b strings.Builder
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+ProdID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if e.name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(e.name))
+	}
+
+	if !isUTC(e.loc) {
+		writeLine(&b, "X-WR-TIMEZONE:"+e.loc.String())
+	}
+
+	// Un VTIMEZONE por cada zona usada: la del calendario y las TZID de los eventos
+	from, to := entriesSpan(entries)
+	for _, loc := range e.zones(entries) {
+		writeVTimezone(&b, loc, from, to)
+	}
+
+	for _, entry := range entries {
+		e.writeVEvent(&b, entry)
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+// zones retorna las zonas horarias (no UTC) que necesitan un VTIMEZONE
+func (e *Encoder) zones(entries []*calendar.Entry) []*time.Location {
+	var zones []*time.Location
+	seen := make(map[string]bool)
+
+	add := func(loc *time.Location) {
+		if isUTC(loc) || seen[loc.String()] {
+			return
+		}
+		seen[loc.String()] = true
+		zones = append(zones, loc)
+	}
+
+	add(e.loc)
+	for _, entry := range entries {
+		add(e.eventLocation(entry))
+	}
+
+	return zones
+}
+
+// eventLocation retorna la zona en la que se escriben las fechas de una entrada:
+// su TZID si tiene, o la del calendario
+func (e *Encoder) eventLocation(entry *calendar.Entry) *time.Location {
+	if entry.TZID != "" {
+		return entry.TimeZone()
+	}
+	return e.loc
+}
+
+// writeVEvent escribe el VEVENT de una entrada
+func (e *Encoder) writeVEvent(b *strings.Builder, entry *calendar.Entry) {
+	loc := e.eventLocation(entry)
+
+	writeLine(b, "BEGIN:VEVENT")
+	writeLine(b, "UID:"+UID(entry))
+	writeLine(b, "DTSTAMP:"+entry.UpdatedAt.UTC().Format(utcDateTimeLayout))
+	if !entry.CreatedAt.IsZero() {
+		writeLine(b, "CREATED:"+entry.CreatedAt.UTC().Format(utcDateTimeLayout))
+	}
+	if !entry.UpdatedAt.IsZero() {
+		writeLine(b, "LAST-MODIFIED:"+entry.UpdatedAt.UTC().Format(utcDateTimeLayout))
+	}
+
+	// Los eventos de día completo usan fechas (VALUE=DATE) y DTEND exclusivo
+	property := timeProperty
+	if entry.AllDay {
+		property = dateProperty
+		loc = entry.TimeZone()
+	}
+
+	writeLine(b, property("DTSTART", entry.DateTime, loc))
+	if entry.AllDay {
+		writeLine(b, property("DTEND", entry.EndTime(), loc))
+	} else {
+		writeLine(b, fmt.Sprintf("DURATION:PT%dM", entry.Minutes()))
+	}
+
+	if entry.RecurrenceID != nil {
+		writeLine(b, property("RECURRENCE-ID", *entry.RecurrenceID, loc))
+	}
+
+	if entry.RRule != nil {
+		writeLine(b, "RRULE:"+entry.RRule.String())
+		for _, ex := range entry.ExDates {
+			writeLine(b, property("EXDATE", ex, loc))
+		}
+	}
+
+	writeLine(b, "SUMMARY:"+escapeText(entry.Title))
+
+	if entry.Location != "" {
+		writeLine(b, "LOCATION:"+escapeText(entry.Location))
+	}
+
+	if entry.Notes != "" {
+		writeLine(b, "DESCRIPTION:"+escapeText(entry.Notes))
+	}
+
+	if len(entry.Tags) > 0 {
+		tags := make([]string, len(entry.Tags))
+		for i, tag := range entry.Tags {
+			tags[i] = escapeText(tag)
+		}
+		writeLine(b, "CATEGORIES:"+strings.Join(tags, ","))
+	}
+
+	for _, minutes := range entry.Reminders {
+		writeLine(b, "BEGIN:VALARM")
+		writeLine(b, "ACTION:DISPLAY")
+		writeLine(b, "DESCRIPTION:"+escapeText(entry.Title))
+		writeLine(b, fmt.Sprintf("TRIGGER:-PT%dM", minutes))
+		writeLine(b, "END:VALARM")
+	}
+
+	writeLine(b, "END:VEVENT")
+}
+
+// timeProperty formatea una propiedad DATE-TIME en la zona loc
+func timeProperty(name string, t time.Time, loc *time.Location) string {
+	if isUTC(loc) {
+		return name + ":" + t.UTC().Format(utcDateTimeLayout)
+	}
+	return fmt.Sprintf("%s;TZID=%s:%s", name, loc.String(), t.In(loc).Format(dateTimeLayout))
+}
+
+// dateProperty formatea una propiedad DATE con el día de t en la zona loc
+func dateProperty(name string, t time.Time, loc *time.Location) string {
+	return fmt.Sprintf("%s;VALUE=DATE:%s", name, t.In(loc).Format(dateLayout))
+}
+
+// UID retorna el UID iCalendar de una entrada. Las entradas importadas
+// conservan el UID de origen.
+func UID(entry *calendar.Entry) string {
+	if uid := entry.Metadata[MetadataUID]; uid != "" {
+		return uid
+	}
+	return entry.ID + "@clical"
+}
+
+// writeLine escribe una línea de contenido plegada
+func writeLine(b *strings.Builder, line string) {
+	b.WriteString(foldLine(line))
+}
+
+// entriesSpan retorna el rango de años cubierto por las entradas
+func entriesSpan(entries []*calendar.Entry) (time.Time, time.Time) {
+	if len(entries) == 0 {
+		now := time.Now()
+		return now, now
+	}
+
+	from, to := entries[0].DateTime, entries[0].DateTime
+	for _, entry := range entries {
+		if entry.DateTime.Before(from) {
+			from = entry.DateTime
+		}
+		if entry.DateTime.After(to) {
+			to = entry.DateTime
+		}
+		if entry.RRule != nil {
+			// Cubrir al menos un año de ocurrencias
+			if end := entry.DateTime.AddDate(1, 0, 0); end.After(to) {
+				to = end
+			}
+			if entry.RRule.Until != nil && entry.RRule.Until.After(to) {
+				to = *entry.RRule.Until
+			}
+		}
+	}
+
+	return from, to
+}
+
+// transition representa un cambio de offset de una zona horaria
+type transition struct {
+	at         time.Time
+	offsetFrom int
+	offsetTo   int
+	name       string
+	isDST      bool
+}
+
+// writeVTimezone escribe un VTIMEZONE con las transiciones de loc entre los
+// años de from y to (inclusive)
+func writeVTimezone(b *strings.Builder, loc *time.Location, from, to time.Time) {
+	start := time.Date(from.Year(), 1, 1, 0, 0, 0, 0, loc)
+	end := time.Date(to.Year()+1, 1, 1, 0, 0, 0, 0, loc)
+
+	transitions := findTransitions(loc, start, end)
+
+	writeLine(b, "BEGIN:VTIMEZONE")
+	writeLine(b, "TZID:"+loc.String())
+
+	// Observancia inicial, vigente desde el comienzo del rango
+	name, offset := start.Zone()
+	writeObservance(b, transition{
+		at:         start,
+		offsetFrom: offset,
+		offsetTo:   offset,
+		name:       name,
+		isDST:      start.IsDST(),
+	})
+
+	for _, t := range transitions {
+		writeObservance(b, t)
+	}
+
+	writeLine(b, "END:VTIMEZONE")
+}
+
+// writeObservance escribe un componente STANDARD o DAYLIGHT
+func writeObservance(b *strings.Builder, t transition) {
+	kind := "STANDARD"
+	if t.isDST {
+		kind = "DAYLIGHT"
+	}
+
+	// DTSTART de la observancia se expresa en la hora local previa al cambio
+	onset := t.at.UTC().Add(time.Duration(t.offsetFrom) * time.Second)
+
+	writeLine(b, "BEGIN:"+kind)
+	writeLine(b, "DTSTART:"+onset.Format(dateTimeLayout))
+	writeLine(b, "TZOFFSETFROM:"+formatOffset(t.offsetFrom))
+	writeLine(b, "TZOFFSETTO:"+formatOffset(t.offsetTo))
+	writeLine(b, "TZNAME:"+t.name)
+	writeLine(b, "END:"+kind)
+}
+
+// findTransitions busca los cambios de offset de loc en [start, end)
+func findTransitions(loc *time.Location, start, end time.Time) []transition {
+	var result []transition
+
+	_, prevOffset := start.Zone()
+	for day := start; day.Before(end); day = day.Add(24 * time.Hour) {
+		next := day.Add(24 * time.Hour)
+		_, offset := next.In(loc).Zone()
+		if offset == prevOffset {
+			continue
+		}
+
+		// Búsqueda binaria del minuto exacto del cambio
+		lo, hi := day, next
+		for hi.Sub(lo) > time.Minute {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if _, o := mid.In(loc).Zone(); o == prevOffset {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+
+		at := hi.In(loc).Truncate(time.Minute)
+		if _, o := at.Zone(); o != offset {
+			at = at.Add(time.Minute)
+		}
+		name, _ := at.Zone()
+		result = append(result, transition{
+			at:         at,
+			offsetFrom: prevOffset,
+			offsetTo:   offset,
+			name:       name,
+			isDST:      at.IsDST(),
+		})
+		prevOffset = offset
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].at.Before(result[j].at)
+	})
+
+	return result
+}
+
+// formatOffset formatea un offset en segundos como +HHMM / -HHMM
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, (seconds%3600)/60)
+}
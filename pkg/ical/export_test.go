@@ -0,0 +1,151 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"simple", "simple"},
+		{"a, b; c", `a\, b\; c`},
+		{"line1\nline2", `line1\nline2`},
+		{`back\slash`, `back\\slash`},
+	}
+
+	for _, tt := range tests {
+		got := escapeText(tt.input)
+		if got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+		if back := unescapeText(got); back != tt.input {
+			t.Errorf("unescapeText(%q) = %q, want %q", got, back, tt.input)
+		}
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("á", 100)
+	folded := foldLine(line)
+
+	for _, l := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(l) > maxLineOctets {
+			t.Errorf("Expected folded line of at most %d octets, got %d", maxLineOctets, len(l))
+		}
+	}
+
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", "")
+	if unfolded != line {
+		t.Error("Expected unfolded line to match the original")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	entry := calendar.NewEntry("12345", "Reunión, semanal", time.Date(2025, 11, 3, 9, 0, 0, 0, loc), 45)
+	entry.Location = "Sala 2"
+	entry.Tags = []string{"trabajo", "equipo"}
+	entry.RRule, _ = calendar.ParseRecurrenceRule("FREQ=WEEKLY;BYDAY=MO")
+	entry.ExcludeOccurrence(time.Date(2025, 11, 10, 9, 0, 0, 0, loc))
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, loc).WithName("Test").Encode([]*calendar.Entry{entry}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"BEGIN:VCALENDAR\r\n",
+		"TZID:Europe/Madrid\r\n",
+		"BEGIN:DAYLIGHT\r\n",
+		"DTSTART:20251026T030000\r\n",
+		"UID:" + entry.ID + "@clical\r\n",
+		"DTSTART;TZID=Europe/Madrid:20251103T090000\r\n",
+		"DURATION:PT45M\r\n",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO\r\n",
+		"EXDATE;TZID=Europe/Madrid:20251110T090000\r\n",
+		"SUMMARY:Reunión\\, semanal\r\n",
+		"LOCATION:Sala 2\r\n",
+		"CATEGORIES:trabajo,equipo\r\n",
+		"END:VCALENDAR\r\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("Expected output to contain %q", e)
+		}
+	}
+}
+
+func TestEncodeUTC(t *testing.T) {
+	entry := calendar.NewEntry("12345", "Call", time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC), 30)
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, nil).Encode([]*calendar.Entry{entry}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	if strings.Contains(buf.String(), "VTIMEZONE") {
+		t.Error("Expected no VTIMEZONE for UTC export")
+	}
+	if !strings.Contains(buf.String(), "DTSTART:20251103T090000Z\r\n") {
+		t.Error("Expected DTSTART in UTC")
+	}
+}
+
+func TestFindTransitions(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, loc)
+	transitions := findTransitions(loc, start, start.AddDate(1, 0, 0))
+
+	if len(transitions) != 2 {
+		t.Fatalf("Expected 2 transitions, got %d", len(transitions))
+	}
+	if got := transitions[0].at.Format("2006-01-02 15:04"); got != "2025-03-09 03:00" {
+		t.Errorf("Expected DST start at 2025-03-09 03:00, got %s", got)
+	}
+	if formatOffset(transitions[0].offsetFrom) != "-0500" || formatOffset(transitions[0].offsetTo) != "-0400" {
+		t.Errorf("Unexpected offsets %d -> %d", transitions[0].offsetFrom, transitions[0].offsetTo)
+	}
+}
+
+func TestEncodeEventTZID(t *testing.T) {
+	madrid, err1 := time.LoadLocation("Europe/Madrid")
+	ba, err2 := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err1 != nil || err2 != nil {
+		t.Skip("timezone data not available")
+	}
+
+	flight := calendar.NewEntry("12345", "Flight", time.Date(2025, 12, 5, 23, 55, 0, 0, madrid), 780)
+	flight.TZID = "Europe/Madrid"
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, ba).Encode([]*calendar.Entry{flight}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, e := range []string{
+		"TZID:America/Argentina/Buenos_Aires\r\n",
+		"TZID:Europe/Madrid\r\n",
+		"DTSTART;TZID=Europe/Madrid:20251205T235500\r\n",
+	} {
+		if !strings.Contains(out, e) {
+			t.Errorf("Expected output to contain %q", e)
+		}
+	}
+}
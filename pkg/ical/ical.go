@@ -0,0 +1,83 @@
+// Package ical implementa la conversión entre entradas del calendario y
+// archivos iCalendar (RFC 5545)
+package ical
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// ProdID identifica a clical como generador del archivo
+	ProdID = "-//clical//clical//ES"
+
+	// maxLineOctets es el largo máximo de una línea antes de plegarla (RFC 5545 3.1)
+	maxLineOctets = 75
+
+	dateTimeLayout    = "20060102T150405"
+	utcDateTimeLayout = "20060102T150405Z"
+	dateLayout        = "20060102"
+)
+
+// escapeText escapa un valor TEXT según RFC 5545 3.3.11
+func escapeText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// unescapeText revierte escapeText
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// foldLine pliega una línea de contenido en líneas de hasta 75 octetos,
+// sin cortar caracteres UTF-8 multibyte
+func foldLine(line string) string {
+	if len(line) <= maxLineOctets {
+		return line + "\r\n"
+	}
+
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Las líneas de continuación comienzan con un espacio
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+
+	return b.String()
+}
+
+// isUTC indica si una zona horaria debe serializarse como UTC (sufijo Z)
+func isUTC(loc *time.Location) bool {
+	return loc == nil || loc == time.UTC || loc.String() == "UTC" || loc.String() == "Local"
+}
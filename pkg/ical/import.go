@@ -0,0 +1,521 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+// MetadataUID es la clave de Entry.Metadata donde se guarda el UID de origen
+const MetadataUID = "ical_uid"
+
+// Decoder parsea un archivo iCalendar y lo convierte en entradas del calendario
+type Decoder struct {
+	r               io.Reader
+	userID          string
+	loc             *time.Location
+	defaultDuration int
+}
+
+// Skip describe un VEVENT que no se pudo importar
+type Skip struct {
+	UID    string
+	Line   int
+	Reason string
+}
+
+// Result contiene las entradas decodificadas y los VEVENTs omitidos.
+// Los overrides de ocurrencia (RECURRENCE-ID) tienen RecurrenceID y el ID vacío.
+type Result struct {
+	Entries  []*calendar.Entry
+	Skipped  []Skip
+	Warnings []string
+}
+
+// property representa una línea de contenido: NOMBRE;PARAM=valor:VALOR
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+	line   int
+}
+
+// component representa un componente BEGIN/END con sus propiedades
+type component struct {
+	name  string
+	line  int
+	props map[string][]property
+	subs  []*component
+}
+
+// NewDecoder crea un decoder para userID. Las fechas sin zona (flotantes o
+// de día completo) se interpretan en loc.
+func NewDecoder(r io.Reader, userID string, loc *time.Location) *Decoder {
+	if loc == nil {
+		loc = time.Local
+	}
+	return &Decoder{
+		r:               r,
+		userID:          userID,
+		loc:             loc,
+		defaultDuration: 60,
+	}
+}
+
+// WithDefaultDuration establece la duración (minutos) de eventos sin DTEND ni DURATION
+func (d *Decoder) WithDefaultDuration(minutes int) *Decoder {
+	if minutes > 0 {
+		d.defaultDuration = minutes
+	}
+	return d
+}
+
+// Decode lee el archivo completo y retorna las entradas de cada VEVENT
+func (d *Decoder) Decode() (*Result, error) {
+	lines, err := unfoldLines(d.r)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseComponents(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	var calendars []*component
+	for _, c := range root.subs {
+		if c.name == "VCALENDAR" {
+			calendars = append(calendars, c)
+		}
+	}
+	if len(calendars) == 0 {
+		return nil, fmt.Errorf("no se encontró VCALENDAR")
+	}
+
+	result := &Result{}
+	for _, cal := range calendars {
+		zones := parseTimezones(cal)
+		for _, sub := range cal.subs {
+			if sub.name != "VEVENT" {
+				continue
+			}
+			entry, warnings, err := d.decodeEvent(sub, zones)
+			uid := sub.first("UID").value
+			for _, w := range warnings {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", uid, w))
+			}
+			if err != nil {
+				result.Skipped = append(result.Skipped, Skip{UID: uid, Line: sub.line, Reason: err.Error()})
+				continue
+			}
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+
+	return result, nil
+}
+
+// decodeEvent convierte un VEVENT en una Entry
+func (d *Decoder) decodeEvent(c *component, zones map[string]*time.Location) (*calendar.Entry, []string, error) {
+	var warnings []string
+
+	uid := c.first("UID").value
+	if uid == "" {
+		return nil, nil, fmt.Errorf("VEVENT sin UID")
+	}
+
+	if strings.EqualFold(c.first("STATUS").value, "CANCELLED") {
+		return nil, nil, fmt.Errorf("evento cancelado")
+	}
+
+	dtstartProp := c.first("DTSTART")
+	if dtstartProp.value == "" {
+		return nil, nil, fmt.Errorf("VEVENT sin DTSTART")
+	}
+	start, allDay, err := d.parseTime(dtstartProp, zones)
+	if err != nil {
+		return nil, nil, fmt.Errorf("DTSTART inválido: %w", err)
+	}
+
+	// Duración: DURATION tiene prioridad sobre DTEND
+	duration := 0
+	if p := c.first("DURATION"); p.value != "" {
+		dur, err := parseDuration(p.value)
+		if err != nil {
+			return nil, nil, err
+		}
+		duration = int(dur.Minutes())
+	} else if p := c.first("DTEND"); p.value != "" {
+		end, _, err := d.parseTime(p, zones)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DTEND inválido: %w", err)
+		}
+		duration = int(end.Sub(start).Minutes())
+	} else if allDay {
+		duration = 24 * 60
+	}
+	if duration <= 0 {
+		duration = d.defaultDuration
+		if allDay {
+			duration = 24 * 60
+		}
+	}
+
+	title := unescapeText(c.first("SUMMARY").value)
+	if title == "" {
+		title = "(sin título)"
+	}
+
+	entry := calendar.NewEntry(d.userID, title, start, duration)
+	entry.TZID = d.eventTZID(dtstartProp)
+	entry.Location = unescapeText(c.first("LOCATION").value)
+	entry.Notes = unescapeText(c.first("DESCRIPTION").value)
+	entry.Metadata[MetadataUID] = uid
+
+	// Eventos de día completo: DTEND es exclusivo, EndDate es el último día
+	if allDay {
+		entry.AllDay = true
+		entry.Duration = 0
+		if days := (duration + 12*60) / (24 * 60); days > 1 {
+			end := start.AddDate(0, 0, days-1)
+			entry.EndDate = &end
+		}
+	}
+
+	for _, p := range c.props["CATEGORIES"] {
+		for _, tag := range splitEscaped(p.value) {
+			if tag = strings.TrimSpace(unescapeText(tag)); tag != "" {
+				entry.AddTag(tag)
+			}
+		}
+	}
+
+	reminders, reminderWarnings := decodeAlarms(c)
+	entry.Reminders = reminders
+	warnings = append(warnings, reminderWarnings...)
+
+	if p := c.first("CREATED"); p.value != "" {
+		if t, _, err := d.parseTime(p, zones); err == nil {
+			entry.CreatedAt = t
+		}
+	}
+
+	if p := c.first("RECURRENCE-ID"); p.value != "" {
+		rid, _, err := d.parseTime(p, zones)
+		if err != nil {
+			return nil, nil, fmt.Errorf("RECURRENCE-ID inválido: %w", err)
+		}
+		entry.ID = ""
+		entry.RecurrenceID = &rid
+		return entry, warnings, nil
+	}
+
+	if p := c.first("RRULE"); p.value != "" {
+		rule, err := calendar.ParseRecurrenceRuleIn(p.value, entry.DateTime.Location())
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("RRULE no soportada (%v), se importa solo la primera ocurrencia", err))
+		} else {
+			entry.RRule = rule
+			for _, ex := range c.props["EXDATE"] {
+				for _, v := range strings.Split(ex.value, ",") {
+					exProp := ex
+					exProp.value = v
+					t, _, err := d.parseTime(exProp, zones)
+					if err != nil {
+						warnings = append(warnings, fmt.Sprintf("EXDATE inválido: %s", v))
+						continue
+					}
+					entry.ExcludeOccurrence(t)
+				}
+			}
+		}
+	}
+
+	if err := entry.Validate(); err != nil {
+		return nil, warnings, err
+	}
+
+	return entry, warnings, nil
+}
+
+// parseTime parsea una propiedad DATE o DATE-TIME y la convierte a la zona del decoder
+func (d *Decoder) parseTime(p property, zones map[string]*time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(p.value)
+
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, d.loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcDateTimeLayout, value)
+		return t.In(d.loc), false, err
+	}
+
+	loc := d.loc
+	if tzid := strings.Trim(p.params["TZID"], `"`); tzid != "" {
+		if l, ok := zones[tzid]; ok {
+			loc = l
+		} else if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	t, err := time.ParseInLocation(dateTimeLayout, value, loc)
+	return t.In(d.loc), false, err
+}
+
+// eventTZID retorna la zona IANA del evento según DTSTART: su TZID si es un
+// nombre IANA, la zona del decoder para horas flotantes, o "" para UTC y
+// zonas definidas solo por VTIMEZONE
+func (d *Decoder) eventTZID(p property) string {
+	if tzid := strings.Trim(p.params["TZID"], `"`); tzid != "" {
+		if _, err := time.LoadLocation(tzid); err == nil {
+			return tzid
+		}
+		return ""
+	}
+
+	if strings.HasSuffix(strings.TrimSpace(p.value), "Z") || d.loc.String() == "Local" {
+		return ""
+	}
+	return d.loc.String()
+}
+
+// decodeAlarms convierte los VALARM de un VEVENT en recordatorios. Solo se
+// importan los TRIGGER relativos al inicio y anteriores a él (ej: -PT15M).
+func decodeAlarms(c *component) ([]int, []string) {
+	var reminders []int
+	var warnings []string
+	for _, sub := range c.subs {
+		if sub.name != "VALARM" {
+			continue
+		}
+		trigger := sub.first("TRIGGER")
+		if trigger.params["VALUE"] == "DATE-TIME" || trigger.params["RELATED"] == "END" {
+			warnings = append(warnings, fmt.Sprintf("VALARM con TRIGGER no relativo al inicio ignorado: %s", trigger.value))
+			continue
+		}
+		dur, err := parseDuration(trigger.value)
+		if err != nil || dur > 0 || -dur > calendar.MaxReminder*time.Minute || dur%time.Minute != 0 {
+			warnings = append(warnings, fmt.Sprintf("VALARM con TRIGGER no soportado ignorado: %s", trigger.value))
+			continue
+		}
+		reminders = append(reminders, int(-dur/time.Minute))
+	}
+	if len(reminders) == 0 {
+		return nil, warnings
+	}
+	return calendar.NormalizeReminders(reminders), warnings
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration parsea un valor DURATION de RFC 5545 (ej: PT1H30M, P1D, P2W)
+func parseDuration(s string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil || s == "P" || s == "PT" {
+		return 0, fmt.Errorf("DURATION inválido: %s", s)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(m[i+2])
+		total += time.Duration(n) * unit
+	}
+
+	if m[1] == "-" {
+		total = -total
+	}
+
+	return total, nil
+}
+
+// unfoldLines lee las líneas de contenido deshaciendo el plegado de RFC 5545
+func unfoldLines(r io.Reader) ([]property, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	var raw []string
+	var lineNumbers []int
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(raw) > 0 {
+			raw[len(raw)-1] += line[1:]
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		raw = append(raw, line)
+		lineNumbers = append(lineNumbers, n)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo iCalendar: %w", err)
+	}
+
+	props := make([]property, 0, len(raw))
+	for i, line := range raw {
+		p, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("línea %d: %w", lineNumbers[i], err)
+		}
+		p.line = lineNumbers[i]
+		props = append(props, p)
+	}
+
+	return props, nil
+}
+
+// parseProperty parsea una línea NOMBRE;PARAM=valor;PARAM="v:x":VALOR
+func parseProperty(line string) (property, error) {
+	inQuotes := false
+	sep := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 {
+		return property{}, fmt.Errorf("línea de contenido inválida: %s", line)
+	}
+
+	head, value := line[:sep], line[sep+1:]
+	parts := strings.Split(head, ";")
+
+	p := property{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string),
+		value:  value,
+	}
+	for _, param := range parts[1:] {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) == 2 {
+			p.params[strings.ToUpper(kv[0])] = kv[1]
+		}
+	}
+
+	return p, nil
+}
+
+// parseComponents arma el árbol de componentes BEGIN/END
+func parseComponents(props []property) (*component, error) {
+	root := &component{name: "ROOT", props: map[string][]property{}}
+	stack := []*component{root}
+
+	for _, p := range props {
+		current := stack[len(stack)-1]
+		switch p.name {
+		case "BEGIN":
+			c := &component{name: strings.ToUpper(p.value), line: p.line, props: map[string][]property{}}
+			current.subs = append(current.subs, c)
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 1 || current.name != strings.ToUpper(p.value) {
+				return nil, fmt.Errorf("línea %d: END:%s inesperado", p.line, p.value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			current.props[p.name] = append(current.props[p.name], p)
+		}
+	}
+
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("componente %s sin END", stack[len(stack)-1].name)
+	}
+
+	return root, nil
+}
+
+// first retorna la primera propiedad con ese nombre (vacía si no existe)
+func (c *component) first(name string) property {
+	if props := c.props[name]; len(props) > 0 {
+		return props[0]
+	}
+	return property{}
+}
+
+// parseTimezones arma un mapa TZID -> Location a partir de los VTIMEZONE.
+// Se usa la base de datos de zonas si el TZID es un nombre IANA; si no, se usa
+// el offset fijo de la observancia STANDARD.
+func parseTimezones(cal *component) map[string]*time.Location {
+	zones := make(map[string]*time.Location)
+
+	for _, c := range cal.subs {
+		if c.name != "VTIMEZONE" {
+			continue
+		}
+		tzid := c.first("TZID").value
+		if tzid == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			zones[tzid] = loc
+			continue
+		}
+
+		for _, obs := range c.subs {
+			if obs.name != "STANDARD" {
+				continue
+			}
+			if offset, err := parseOffset(obs.first("TZOFFSETTO").value); err == nil {
+				zones[tzid] = time.FixedZone(tzid, offset)
+				break
+			}
+		}
+	}
+
+	return zones
+}
+
+// parseOffset parsea un offset +HHMM / -HHMM a segundos
+func parseOffset(s string) (int, error) {
+	if len(s) < 5 {
+		return 0, fmt.Errorf("offset inválido: %s", s)
+	}
+	hours, err1 := strconv.Atoi(s[1:3])
+	minutes, err2 := strconv.Atoi(s[3:5])
+	if err1 != nil || err2 != nil {
+		return 0, fmt.Errorf("offset inválido: %s", s)
+	}
+	seconds := hours*3600 + minutes*60
+	if s[0] == '-' {
+		seconds = -seconds
+	}
+	return seconds, nil
+}
+
+// splitEscaped divide una lista separada por comas ignorando las comas escapadas
+func splitEscaped(s string) []string {
+	var parts []string
+	var current strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			current.WriteByte(s[i])
+			current.WriteByte(s[i+1])
+			i++
+		case s[i] == ',':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(s[i])
+		}
+	}
+	return append(parts, current.String())
+}
@@ -0,0 +1,256 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"PT45M", 45 * time.Minute, false},
+		{"PT1H30M", 90 * time.Minute, false},
+		{"P1D", 24 * time.Hour, false},
+		{"P1DT2H", 26 * time.Hour, false},
+		{"P2W", 14 * 24 * time.Hour, false},
+		{"-PT15M", -15 * time.Minute, false},
+		{"P", 0, true},
+		{"1H", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDuration(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDuration(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseDuration(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseProperty(t *testing.T) {
+	p, err := parseProperty(`DTSTART;TZID="GMT+01:00 Custom";VALUE=DATE-TIME:20251103T100000`)
+	if err != nil {
+		t.Fatalf("parseProperty() error = %v", err)
+	}
+	if p.name != "DTSTART" {
+		t.Errorf("Expected name DTSTART, got %s", p.name)
+	}
+	if p.params["TZID"] != `"GMT+01:00 Custom"` {
+		t.Errorf("Unexpected TZID param %s", p.params["TZID"])
+	}
+	if p.value != "20251103T100000" {
+		t.Errorf("Unexpected value %s", p.value)
+	}
+
+	if _, err := parseProperty("NOCOLON"); err == nil {
+		t.Error("Expected error for line without value")
+	}
+}
+
+const sampleICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Custom Zone\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:19700101T000000\r\n" +
+	"TZOFFSETFROM:+0100\r\n" +
+	"TZOFFSETTO:+0100\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:call@example.com\r\n" +
+	"DTSTART:20251120T140000Z\r\n" +
+	"DTEND:20251120T153000Z\r\n" +
+	"SUMMARY:Call\\, team\r\n" +
+	"CATEGORIES:work,a\\,b\r\n" +
+	"DESCRIPTION:line1\\nline2 fol\r\n" +
+	" ded\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"TRIGGER:-PT1H\r\n" +
+	"END:VALARM\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"TRIGGER;RELATED=END:PT0S\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:conf@example.com\r\n" +
+	"DTSTART;VALUE=DATE:20251124\r\n" +
+	"DTEND;VALUE=DATE:20251126\r\n" +
+	"SUMMARY:Conference\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:weekly@example.com\r\n" +
+	"DTSTART;TZID=\"Custom Zone\":20251103T100000\r\n" +
+	"DURATION:PT45M\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=4\r\n" +
+	"EXDATE;TZID=\"Custom Zone\":20251110T100000\r\n" +
+	"SUMMARY:Weekly\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:weekly@example.com\r\n" +
+	"RECURRENCE-ID;TZID=\"Custom Zone\":20251117T100000\r\n" +
+	"DTSTART;TZID=\"Custom Zone\":20251117T150000\r\n" +
+	"SUMMARY:Weekly moved\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cancelled@example.com\r\n" +
+	"DTSTART:20251120T140000Z\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestDecode(t *testing.T) {
+	result, err := NewDecoder(strings.NewReader(sampleICS), "12345", time.UTC).WithDefaultDuration(30).Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if len(result.Entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(result.Entries))
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].UID != "cancelled@example.com" {
+		t.Errorf("Expected cancelled event to be skipped, got %+v", result.Skipped)
+	}
+
+	call := result.Entries[0]
+	if call.Title != "Call, team" || call.Duration != 90 || call.UserID != "12345" {
+		t.Errorf("Unexpected call entry: %q %d %q", call.Title, call.Duration, call.UserID)
+	}
+	if call.Notes != "line1\nline2 folded" {
+		t.Errorf("Unexpected notes %q", call.Notes)
+	}
+	if len(call.Tags) != 2 || call.Tags[1] != "a,b" {
+		t.Errorf("Unexpected tags %v", call.Tags)
+	}
+	if len(call.Reminders) != 1 || call.Reminders[0] != 60 {
+		t.Errorf("Expected 1h reminder, got %v", call.Reminders)
+	}
+	if len(result.Warnings) != 1 || !strings.HasPrefix(result.Warnings[0], "call@example.com: VALARM") {
+		t.Errorf("Expected warning for unsupported VALARM, got %v", result.Warnings)
+	}
+	if call.Metadata[MetadataUID] != "call@example.com" {
+		t.Errorf("Expected source UID in metadata, got %q", call.Metadata[MetadataUID])
+	}
+
+	conf := result.Entries[1]
+	if !conf.AllDay || !conf.DateTime.Equal(time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected all-day entry: %v all-day=%v", conf.DateTime, conf.AllDay)
+	}
+	if conf.EndDate == nil || !conf.EndDate.Equal(time.Date(2025, 11, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected last day 2025-11-25, got %v", conf.EndDate)
+	}
+
+	weekly := result.Entries[2]
+	if weekly.RRule == nil || weekly.RRule.Count != 4 {
+		t.Fatalf("Expected weekly RRULE with COUNT=4")
+	}
+	if !weekly.DateTime.Equal(time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected TZID from VTIMEZONE to be applied, got %v", weekly.DateTime)
+	}
+	if len(weekly.ExDates) != 1 {
+		t.Errorf("Expected 1 EXDATE, got %d", len(weekly.ExDates))
+	}
+
+	moved := result.Entries[3]
+	if !moved.IsOccurrence() || moved.ID != "" {
+		t.Errorf("Expected override without ID")
+	}
+	if moved.Duration != 30 {
+		t.Errorf("Expected default duration, got %d", moved.Duration)
+	}
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	entry := calendar.NewEntry("12345", "Reunión; semanal", time.Date(2025, 11, 3, 9, 0, 0, 0, loc), 45)
+	entry.Location = "Sala 2"
+	entry.Tags = []string{"trabajo"}
+	entry.RRule, _ = calendar.ParseRecurrenceRule("FREQ=WEEKLY;BYDAY=MO")
+	entry.Reminders = []int{15, 1440}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, loc).Encode([]*calendar.Entry{entry}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	result, err := NewDecoder(&buf, "12345", loc).Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(result.Entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(result.Entries))
+	}
+
+	got := result.Entries[0]
+	if got.Title != entry.Title || got.Location != entry.Location || got.Duration != entry.Duration {
+		t.Errorf("Round trip mismatch: %+v", got)
+	}
+	if !got.DateTime.Equal(entry.DateTime) {
+		t.Errorf("Expected %v, got %v", entry.DateTime, got.DateTime)
+	}
+	if got.RRule.String() != entry.RRule.String() {
+		t.Errorf("Expected RRULE %s, got %s", entry.RRule, got.RRule)
+	}
+	if calendar.FormatReminders(got.Reminders) != "15m,1d" {
+		t.Errorf("Expected reminders 15m,1d, got %v", got.Reminders)
+	}
+	if got.TZID != "Europe/Madrid" {
+		t.Errorf("Expected TZID Europe/Madrid, got %q", got.TZID)
+	}
+	if UID(got) != UID(entry) {
+		t.Errorf("Expected UID %s, got %s", UID(entry), UID(got))
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	if _, err := NewDecoder(strings.NewReader("BEGIN:VEVENT\r\nEND:VEVENT\r\n"), "1", nil).Decode(); err == nil {
+		t.Error("Expected error without VCALENDAR")
+	}
+	if _, err := NewDecoder(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n"), "1", nil).Decode(); err == nil {
+		t.Error("Expected error for unterminated component")
+	}
+}
+
+func TestDecodeAllDayRoundTrip(t *testing.T) {
+	entry := calendar.NewEntry("12345", "Vacaciones", time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), 0)
+	entry.AllDay = true
+	end := time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)
+	entry.EndDate = &end
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, time.UTC).Encode([]*calendar.Entry{entry}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "DTEND;VALUE=DATE:20260117") {
+		t.Errorf("Expected exclusive DTEND date, got:\n%s", buf.String())
+	}
+
+	result, err := NewDecoder(&buf, "12345", time.UTC).Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	got := result.Entries[0]
+	if !got.AllDay || got.EndDate == nil || !got.EndDate.Equal(end) {
+		t.Errorf("Expected all-day entry until %v, got all-day=%v end=%v", end, got.AllDay, got.EndDate)
+	}
+	if err := got.Validate(); err != nil {
+		t.Errorf("Decoded entry is invalid: %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/sebasvalencia/clical/pkg/calendar"
 	"github.com/sebasvalencia/clical/pkg/storage"
+	"github.com/sebasvalencia/clical/pkg/user"
 )
 
 // DailyReport contiene el reporte diario completo
@@ -15,6 +16,8 @@ type DailyReport struct {
 	Date           time.Time
 	UserID         string
 	Events         []*calendar.Entry
+	AllDay         []*calendar.Entry // eventos de día completo o que ocupan todo el día
+	WorkHours      []user.Window     // franjas laborables del día (vacío si no es laborable)
 	Summary        Summary
 	FreetimeBlocks []FreetimeBlock
 	Tomorrow       []*calendar.Entry
@@ -27,7 +30,8 @@ type Summary struct {
 	TotalHours    float64
 	FirstEvent    *time.Time
 	LastEvent     *time.Time
-	FreeHours     float64
+	FreeHours     float64 // horas libres dentro de la jornada laboral
+	WorkHours     float64 // horas laborables del día
 	NextEvent     *calendar.Entry
 	MinutesToNext int
 }
@@ -39,15 +43,20 @@ type FreetimeBlock struct {
 	Duration int // minutos
 }
 
-// GenerateDailyReport genera el reporte diario para un usuario
+// GenerateDailyReport genera el reporte diario para un usuario.
+// El día de date se determina en la zona horaria del usuario.
 func GenerateDailyReport(store storage.Storage, userID string, date time.Time) (*DailyReport, error) {
+	loc := UserLocation(store, userID)
+	config := UserConfig(store, userID)
+	date = date.In(loc)
+
 	// Normalizar fecha a inicio del día
 	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	dayEnd := dayStart.Add(24 * time.Hour)
+	dayEnd := dayStart.AddDate(0, 0, 1)
 
-	// Obtener eventos del día
+	// Obtener eventos del día, incluyendo los que comenzaron antes y siguen en curso
 	filter := calendar.NewFilter()
-	filter.WithDateRange(dayStart, dayEnd)
+	filter.WithDateRange(dayStart, dayEnd).WithRangeMode(calendar.RangeOverlap)
 	events, err := store.ListEntries(userID, filter)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo eventos: %w", err)
@@ -55,27 +64,42 @@ func GenerateDailyReport(store storage.Storage, userID string, date time.Time) (
 
 	// Obtener eventos de mañana
 	tomorrowStart := dayEnd
-	tomorrowEnd := tomorrowStart.Add(24 * time.Hour)
+	tomorrowEnd := tomorrowStart.AddDate(0, 0, 1)
 	filterTomorrow := calendar.NewFilter()
-	filterTomorrow.WithDateRange(tomorrowStart, tomorrowEnd)
+	filterTomorrow.WithDateRange(tomorrowStart, tomorrowEnd).WithRangeMode(calendar.RangeOverlap)
 	tomorrow, err := store.ListEntries(userID, filterTomorrow)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo eventos de mañana: %w", err)
 	}
 
+	localize(events, loc)
+	localize(tomorrow, loc)
+
+	// Los eventos de día completo se muestran aparte y no ocupan tiempo libre
+	events, allDay := splitAllDay(events, dayStart, dayEnd)
+
 	// Calcular resumen
-	summary := calculateSummary(events)
+	summary := calculateSummary(events, dayStart, dayEnd)
+	summary.TotalEvents += len(allDay)
 
-	// Calcular bloques libres
-	freetime := calculateFreetime(events, dayStart, dayEnd)
+	// Calcular tiempo libre dentro de la jornada del usuario (los eventos con
+	// horario que ocupan todo el día también la ocupan)
+	busy := append(append([]*calendar.Entry{}, allDay...), events...)
+	workHours := config.WorkWindows(dayStart.Weekday())
+	summary.WorkHours = float64(config.WorkMinutes(dayStart.Weekday())) / 60.0
+	summary.FreeHours = float64(FreeMinutes(busy, dayStart, workHours)) / 60.0
+
+	freetime := calculateFreetime(busy, dayStart, workHours)
 
 	// Generar sugerencias
-	suggestions := generateSuggestions(events, tomorrow)
+	suggestions := generateSuggestions(append(allDay, events...), tomorrow)
 
 	report := &DailyReport{
 		Date:           dayStart,
 		UserID:         userID,
 		Events:         events,
+		AllDay:         allDay,
+		WorkHours:      workHours,
 		Summary:        summary,
 		FreetimeBlocks: freetime,
 		Tomorrow:       tomorrow,
@@ -85,8 +109,36 @@ func GenerateDailyReport(store storage.Storage, userID string, date time.Time) (
 	return report, nil
 }
 
+// splitAllDay separa los eventos de día completo (o que ocupan todo el día) de
+// los eventos con horario
+func splitAllDay(events []*calendar.Entry, dayStart, dayEnd time.Time) (timed, allDay []*calendar.Entry) {
+	for _, e := range events {
+		if e.AllDay || e.CoversDay(dayStart, dayEnd) {
+			allDay = append(allDay, e)
+		} else {
+			timed = append(timed, e)
+		}
+	}
+	return timed, allDay
+}
+
+// minutesWithin retorna los minutos del evento que caen dentro de [from, to)
+func minutesWithin(e *calendar.Entry, from, to time.Time) int {
+	start, end := e.DateTime, e.EndTime()
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return int(end.Sub(start).Minutes())
+}
+
 // calculateSummary calcula estadísticas del día
-func calculateSummary(events []*calendar.Entry) Summary {
+func calculateSummary(events []*calendar.Entry, dayStart, dayEnd time.Time) Summary {
 	summary := Summary{
 		TotalEvents: len(events),
 	}
@@ -98,21 +150,28 @@ func calculateSummary(events []*calendar.Entry) Summary {
 	// Calcular horas totales
 	totalMinutes := 0
 	for _, e := range events {
-		totalMinutes += e.Duration
+		totalMinutes += minutesWithin(e, dayStart, dayEnd)
 	}
 	summary.TotalHours = float64(totalMinutes) / 60.0
 
-	// Primer y último evento
+	// Primer y último evento, limitados al día (los eventos pueden cruzar la medianoche)
 	first := events[0].DateTime
+	if first.Before(dayStart) {
+		first = dayStart
+	}
 	summary.FirstEvent = &first
 
 	last := events[len(events)-1].EndTime()
+	for _, e := range events {
+		if e.EndTime().After(last) {
+			last = e.EndTime()
+		}
+	}
+	if last.After(dayEnd) {
+		last = dayEnd
+	}
 	summary.LastEvent = &last
 
-	// Calcular tiempo libre (asumiendo día laboral de 8am a 6pm)
-	workDayMinutes := 10 * 60 // 10 horas
-	summary.FreeHours = float64(workDayMinutes-totalMinutes) / 60.0
-
 	// Próximo evento
 	now := time.Now()
 	for _, e := range events {
@@ -126,53 +185,75 @@ func calculateSummary(events []*calendar.Entry) Summary {
 	return summary
 }
 
-// calculateFreetime calcula bloques de tiempo libre
-func calculateFreetime(events []*calendar.Entry, dayStart, dayEnd time.Time) []FreetimeBlock {
+// calculateFreetime calcula los bloques libres de al menos 15 minutos dentro
+// de la jornada laboral
+func calculateFreetime(events []*calendar.Entry, dayStart time.Time, workHours []user.Window) []FreetimeBlock {
 	var blocks []FreetimeBlock
-
-	if len(events) == 0 {
-		// Todo el día libre
-		return []FreetimeBlock{
-			{
-				Start:    dayStart.Add(8 * time.Hour), // 8am
-				End:      dayStart.Add(18 * time.Hour), // 6pm
-				Duration: 10 * 60, // 10 horas
-			},
+	for _, block := range freeIntervals(events, dayStart, workHours) {
+		if block.Duration >= 15 {
+			blocks = append(blocks, block)
 		}
 	}
+	return blocks
+}
 
-	// Bloques entre eventos
-	workStart := dayStart.Add(8 * time.Hour) // 8am
-	workEnd := dayStart.Add(18 * time.Hour) // 6pm
+// FreeMinutes retorna los minutos libres del día dentro de las franjas
+// laborables. Nunca es negativo: solo cuenta los huecos entre eventos.
+func FreeMinutes(events []*calendar.Entry, dayStart time.Time, workHours []user.Window) int {
+	total := 0
+	for _, block := range freeIntervals(events, dayStart, workHours) {
+		total += block.Duration
+	}
+	return total
+}
+
+// freeIntervals retorna los intervalos libres dentro de cada franja laborable.
+// Los eventos de día completo no ocupan tiempo.
+func freeIntervals(events []*calendar.Entry, dayStart time.Time, workHours []user.Window) []FreetimeBlock {
+	busy := make([]*calendar.Entry, 0, len(events))
+	for _, e := range events {
+		if !e.AllDay {
+			busy = append(busy, e)
+		}
+	}
+	sort.SliceStable(busy, func(i, j int) bool {
+		return busy[i].DateTime.Before(busy[j].DateTime)
+	})
 
-	lastEnd := workStart
+	var blocks []FreetimeBlock
+	y, m, d := dayStart.Date()
+
+	for _, w := range workHours {
+		workStart := time.Date(y, m, d, 0, w.Start, 0, 0, dayStart.Location())
+		workEnd := time.Date(y, m, d, 0, w.End, 0, 0, dayStart.Location())
+
+		lastEnd := workStart
+		for _, event := range busy {
+			// Solo eventos que se superponen con la franja (pueden haber
+			// comenzado el día anterior)
+			if !event.Overlaps(workStart, workEnd) {
+				continue
+			}
 
-	for _, event := range events {
-		// Si el evento está dentro del horario laboral
-		if event.DateTime.After(workStart) && event.DateTime.Before(workEnd) {
 			// Si hay gap desde último evento
 			if event.DateTime.After(lastEnd) {
-				gap := int(event.DateTime.Sub(lastEnd).Minutes())
-				if gap >= 15 { // Solo bloques de al menos 15 min
-					blocks = append(blocks, FreetimeBlock{
-						Start:    lastEnd,
-						End:      event.DateTime,
-						Duration: gap,
-					})
-				}
+				blocks = append(blocks, FreetimeBlock{
+					Start:    lastEnd,
+					End:      event.DateTime,
+					Duration: int(event.DateTime.Sub(lastEnd).Minutes()),
+				})
+			}
+			if event.EndTime().After(lastEnd) {
+				lastEnd = event.EndTime()
 			}
-			lastEnd = event.EndTime()
 		}
-	}
 
-	// Bloque final hasta fin de jornada
-	if lastEnd.Before(workEnd) {
-		gap := int(workEnd.Sub(lastEnd).Minutes())
-		if gap >= 15 {
+		// Bloque final hasta fin de la franja
+		if lastEnd.Before(workEnd) {
 			blocks = append(blocks, FreetimeBlock{
 				Start:    lastEnd,
 				End:      workEnd,
-				Duration: gap,
+				Duration: int(workEnd.Sub(lastEnd).Minutes()),
 			})
 		}
 	}
@@ -224,7 +305,12 @@ func FormatDailyReport(report *DailyReport) string {
 	if report.Summary.LastEvent != nil {
 		md.WriteString(fmt.Sprintf("- **Last event:** %s\n", report.Summary.LastEvent.Format("15:04")))
 	}
-	md.WriteString(fmt.Sprintf("- **Free time:** %.1f horas\n", report.Summary.FreeHours))
+	if len(report.WorkHours) > 0 {
+		md.WriteString(fmt.Sprintf("- **Working hours:** %s\n", formatWorkHours(report.WorkHours)))
+		md.WriteString(fmt.Sprintf("- **Free time:** %.1f de %.1f horas\n", report.Summary.FreeHours, report.Summary.WorkHours))
+	} else {
+		md.WriteString("- **Free time:** día no laborable\n")
+	}
 	md.WriteString("\n")
 
 	// Próximo evento
@@ -232,10 +318,10 @@ func FormatDailyReport(report *DailyReport) string {
 		md.WriteString(fmt.Sprintf("### 🔴 NEXT (in %d minutes)\n\n", report.Summary.MinutesToNext))
 		md.WriteString(fmt.Sprintf("**[%s - %s] %s**\n",
 			report.Summary.NextEvent.DateTime.Format("15:04"),
-			report.Summary.NextEvent.EndTime().Format("15:04"),
+			formatEnd(report.Summary.NextEvent, report.Date),
 			report.Summary.NextEvent.Title))
 		md.WriteString(fmt.Sprintf("- ID: %s\n", report.Summary.NextEvent.ID))
-		md.WriteString(fmt.Sprintf("- Duration: %d min\n", report.Summary.NextEvent.Duration))
+		md.WriteString(fmt.Sprintf("- Duration: %d min\n", report.Summary.NextEvent.Minutes()))
 		if report.Summary.NextEvent.Location != "" {
 			md.WriteString(fmt.Sprintf("- Location: %s\n", report.Summary.NextEvent.Location))
 		}
@@ -248,18 +334,31 @@ func FormatDailyReport(report *DailyReport) string {
 		md.WriteString("\n")
 	}
 
+	// Eventos de día completo
+	if len(report.AllDay) > 0 {
+		md.WriteString("## All Day\n\n")
+		for _, event := range report.AllDay {
+			md.WriteString(fmt.Sprintf("- %s%s [ID: %s]\n", event.Title, formatSpan(event), event.ID))
+		}
+		md.WriteString("\n")
+	}
+
 	// Agenda del día
 	if len(report.Events) > 0 {
 		md.WriteString("## Today's Agenda\n\n")
 
 		for _, event := range report.Events {
 			md.WriteString(fmt.Sprintf("**[%s - %s] %s**\n",
-				event.DateTime.Format("15:04"),
-				event.EndTime().Format("15:04"),
+				formatStart(event, report.Date),
+				formatEnd(event, report.Date),
 				event.Title))
 
 			md.WriteString(fmt.Sprintf("- ID: %s\n", event.ID))
-			md.WriteString(fmt.Sprintf("- Duration: %d min\n", event.Duration))
+			md.WriteString(fmt.Sprintf("- Duration: %d min\n", event.Minutes()))
+
+			if event.TZID != "" && event.TZID != report.Date.Location().String() {
+				md.WriteString(fmt.Sprintf("- Time zone: %s (%s local)\n", event.TZID, event.LocalDateTime().Format("15:04")))
+			}
 
 			if event.Location != "" {
 				md.WriteString(fmt.Sprintf("- Location: %s\n", event.Location))
@@ -275,7 +374,7 @@ func FormatDailyReport(report *DailyReport) string {
 
 			md.WriteString("\n")
 		}
-	} else {
+	} else if len(report.AllDay) == 0 {
 		md.WriteString("## Today's Agenda\n\n")
 		md.WriteString("No hay eventos programados para hoy.\n\n")
 	}
@@ -306,17 +405,24 @@ func FormatDailyReport(report *DailyReport) string {
 		md.WriteString(fmt.Sprintf("## Vista de Mañana (%s %d)\n\n",
 			weekdays[(report.Date.Weekday()+1)%7], report.Date.Day()+1))
 
-		for _, event := range report.Tomorrow {
+		tomorrowStart := report.Date.AddDate(0, 0, 1)
+		tomorrowEnd := tomorrowStart.AddDate(0, 0, 1)
+
+		timed, allDay := splitAllDay(report.Tomorrow, tomorrowStart, tomorrowEnd)
+		for _, event := range allDay {
+			md.WriteString(fmt.Sprintf("- [all day] %s%s\n", event.Title, formatSpan(event)))
+		}
+		for _, event := range timed {
 			md.WriteString(fmt.Sprintf("- [%s] %s (%d min)\n",
-				event.DateTime.Format("15:04"),
+				formatStart(event, tomorrowStart),
 				event.Title,
-				event.Duration))
+				event.Minutes()))
 		}
 
-		// Advertencia si mañana está pesado
+		// Advertencia si mañana está pesado (sin contar eventos de día completo)
 		totalMinutes := 0
-		for _, e := range report.Tomorrow {
-			totalMinutes += e.Duration
+		for _, e := range timed {
+			totalMinutes += minutesWithin(e, tomorrowStart, tomorrowEnd)
 		}
 		hours := float64(totalMinutes) / 60.0
 		if hours > 4 {
@@ -337,11 +443,56 @@ func FormatDailyReport(report *DailyReport) string {
 
 	// Footer
 	md.WriteString("---\n\n")
-	md.WriteString(fmt.Sprintf("*Generado: %s*\n", time.Now().Format("2006-01-02 15:04")))
+	md.WriteString(fmt.Sprintf("*Generado: %s*\n", time.Now().In(report.Date.Location()).Format("2006-01-02 15:04")))
 
 	return md.String()
 }
 
+// formatWorkHours formatea las franjas laborables, ej: "09:00-13:00, 14:00-18:00"
+func formatWorkHours(windows []user.Window) string {
+	parts := make([]string, len(windows))
+	for i, w := range windows {
+		parts[i] = w.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatStart formatea el inicio de un evento; si comenzó otro día incluye la fecha
+func formatStart(e *calendar.Entry, day time.Time) string {
+	if sameDay(e.DateTime, day) {
+		return e.DateTime.Format("15:04")
+	}
+	return e.DateTime.Format("02/01 15:04")
+}
+
+// formatEnd formatea el fin de un evento; si termina otro día incluye la fecha
+func formatEnd(e *calendar.Entry, day time.Time) string {
+	end := e.EndTime().In(day.Location())
+	if sameDay(end, day) {
+		return end.Format("15:04")
+	}
+	return end.Format("02/01 15:04")
+}
+
+// formatSpan describe el rango de días de un evento de varios días (vacío si dura un día)
+func formatSpan(e *calendar.Entry) string {
+	if !e.IsMultiDay() {
+		return ""
+	}
+	last := e.EndTime().In(e.DateTime.Location())
+	if e.AllDay {
+		last = last.AddDate(0, 0, -1)
+	}
+	return fmt.Sprintf(" (%s - %s)", e.DateTime.Format("02/01"), last.Format("02/01"))
+}
+
+// sameDay verifica si a y b caen en el mismo día de calendario
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
 // FindNextEvents encuentra los próximos N eventos a partir de ahora
 func FindNextEvents(store storage.Storage, userID string, count int) ([]*calendar.Entry, error) {
 	now := time.Now()
@@ -366,5 +517,34 @@ func FindNextEvents(store storage.Storage, userID string, count int) ([]*calenda
 		events = events[:count]
 	}
 
+	localize(events, UserLocation(store, userID))
+
 	return events, nil
 }
+
+// UserLocation retorna la zona horaria del usuario, o la zona local si el
+// usuario no está registrado o su zona es inválida
+func UserLocation(store storage.Storage, userID string) *time.Location {
+	if u, err := store.GetUser(userID); err == nil {
+		if loc, err := u.Location(); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// UserConfig retorna la configuración del usuario, o la configuración por
+// defecto si el usuario no está registrado
+func UserConfig(store storage.Storage, userID string) user.UserConfig {
+	if u, err := store.GetUser(userID); err == nil {
+		return u.Config
+	}
+	return user.DefaultConfig()
+}
+
+// localize convierte las fechas de los eventos a loc para mostrarlas
+func localize(events []*calendar.Entry, loc *time.Location) {
+	for _, e := range events {
+		e.DateTime = e.DateTime.In(loc)
+	}
+}
@@ -46,6 +46,11 @@ func (ap *AlarmPaths) PastDir(recurrence alarm.Recurrence) string {
 	return filepath.Join(ap.UserAlarmsDir(), "past", "recurring", string(recurrence))
 }
 
+// UnackedFile retorna el archivo de las alarmas disparadas sin confirmar
+func (ap *AlarmPaths) UnackedFile() string {
+	return filepath.Join(ap.UserAlarmsDir(), "unacked.json")
+}
+
 // PendingFile retorna la ruta completa para una alarma one-time
 func (ap *AlarmPaths) PendingFile(filename string) string {
 	return filepath.Join(ap.PendingDir(), filename)
@@ -69,11 +74,16 @@ func (ap *AlarmPaths) EnsureAlarmDirs() error {
 		ap.RecurringDir(alarm.RecurrenceWeekly),
 		ap.RecurringDir(alarm.RecurrenceMonthly),
 		ap.RecurringDir(alarm.RecurrenceYearly),
+		ap.RecurringDir(alarm.RecurrenceCron),
+		ap.RecurringDir(alarm.RecurrenceInterval),
 		ap.PastDir(alarm.RecurrenceOnce),
 		ap.PastDir(alarm.RecurrenceDaily),
 		ap.PastDir(alarm.RecurrenceWeekly),
 		ap.PastDir(alarm.RecurrenceMonthly),
 		ap.PastDir(alarm.RecurrenceYearly),
+		ap.PastDir(alarm.RecurrenceCron),
+		ap.PastDir(alarm.RecurrenceInterval),
+		ap.PastDir(alarm.RecurrenceReminder),
 	}
 
 	for _, dir := range dirs {
@@ -0,0 +1,837 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/user"
+)
+
+// recoveryMinutes es cuántos minutos hacia atrás se recuperan alarmas no
+// disparadas (por ejemplo si el chequeo periódico no corrió)
+const recoveryMinutes = 60
+
+// unackedRetention es cuánto tiempo una alarma disparada sigue esperando
+// confirmación (alarm ack); pasado ese tiempo se descarta sin confirmar
+const unackedRetention = 24 * time.Hour
+
+// activeRecurrences son las recurrencias de alarmas recurrentes con un
+// archivo por horario, que se busca minuto a minuto
+var activeRecurrences = []alarm.Recurrence{
+	alarm.RecurrenceDaily,
+	alarm.RecurrenceWeekly,
+	alarm.RecurrenceMonthly,
+	alarm.RecurrenceYearly,
+}
+
+// scheduledRecurrences son las recurrencias cuyo archivo describe un horario
+// del que se calculan las ejecuciones (ver alarmSchedule)
+var scheduledRecurrences = []alarm.Recurrence{
+	alarm.RecurrenceCron,
+	alarm.RecurrenceInterval,
+}
+
+// allRecurrences incluye las alarmas one-time y los registros de
+// recordatorios de eventos (que solo tienen archivos en past/)
+var allRecurrences = append(append(append([]alarm.Recurrence{alarm.RecurrenceOnce}, activeRecurrences...), scheduledRecurrences...), alarm.RecurrenceReminder)
+
+// schedule calcula las ejecuciones de un archivo de scheduledRecurrences
+type schedule interface {
+	// Next retorna la primera ejecución posterior a after (cero si no hay)
+	Next(after time.Time) time.Time
+}
+
+// alarmSchedule retorna el horario de un archivo de scheduledRecurrences,
+// con las fechas del nombre de archivo en loc
+func alarmSchedule(recurrence alarm.Recurrence, filename string, loc *time.Location) (schedule, error) {
+	switch recurrence {
+	case alarm.RecurrenceCron:
+		return alarm.ParseCronFilename(filename)
+	case alarm.RecurrenceInterval:
+		return alarm.ParseIntervalFilename(filename, loc)
+	}
+	return nil, fmt.Errorf("unsupported recurrence type: %s", recurrence)
+}
+
+// alarmFiles abstrae dónde guarda cada backend los archivos de alarmas.
+// Un archivo es una lista de alarmas identificada por su recurrencia y nombre
+// (el schedule), y está activo (pending/ o recurring/) o en past/.
+// La lógica de disparo, recovery y cancelación es común a todos los backends.
+type alarmFiles interface {
+	// readAlarmFile lee un archivo de alarmas; ok es false si no existe
+	readAlarmFile(userID string, past bool, recurrence alarm.Recurrence, filename string) (alarms []*alarm.Alarm, ok bool, err error)
+	alarmFileExists(userID string, past bool, recurrence alarm.Recurrence, filename string) (bool, error)
+	writeAlarmFile(userID string, past bool, recurrence alarm.Recurrence, filename string, alarms []*alarm.Alarm) error
+	// removeAlarmFile elimina un archivo activo (sin error si no existe)
+	removeAlarmFile(userID string, recurrence alarm.Recurrence, filename string) error
+	// listAlarmFiles retorna los nombres de archivo ordenados
+	listAlarmFiles(userID string, past bool, recurrence alarm.Recurrence) ([]string, error)
+	// moveAlarmFileToPast mueve un archivo activo a past/
+	moveAlarmFileToPast(userID string, recurrence alarm.Recurrence, filename string) error
+	// readUnackedAlarms lee las alarmas disparadas sin confirmar (vacío si no hay)
+	readUnackedAlarms(userID string) ([]*alarm.Alarm, error)
+	writeUnackedAlarms(userID string, alarms []*alarm.Alarm) error
+}
+
+// saveAlarm agrega una alarma al archivo de su schedule
+func saveAlarm(files alarmFiles, userID string, recurrence alarm.Recurrence, filename string, alm *alarm.Alarm) error {
+	if err := alm.Validate(); err != nil {
+		return fmt.Errorf("alarma inválida: %w", err)
+	}
+
+	// Leer alarmas existentes en el archivo (si existe)
+	existingAlarms, _, err := files.readAlarmFile(userID, false, recurrence, filename)
+	if err != nil {
+		return fmt.Errorf("error leyendo alarmas existentes: %w", err)
+	}
+
+	// Agregar la nueva alarma
+	existingAlarms = append(existingAlarms, alm)
+
+	return files.writeAlarmFile(userID, false, recurrence, filename, existingAlarms)
+}
+
+// getAlarms lee las alarmas activas de un archivo (vacío si no existe)
+func getAlarms(files alarmFiles, userID string, recurrence alarm.Recurrence, filename string) ([]*alarm.Alarm, error) {
+	alarms, ok, err := files.readAlarmFile(userID, false, recurrence, filename)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []*alarm.Alarm{}, nil
+	}
+	return alarms, nil
+}
+
+// entryLister lista los eventos de los que se calculan los recordatorios
+type entryLister interface {
+	ListEntries(userID string, filter *calendar.Filter) ([]*calendar.Entry, error)
+}
+
+// userGetter lee la configuración de un usuario
+type userGetter interface {
+	GetUser(userID string) (*user.User, error)
+}
+
+// alarmLocation retorna la zona horaria en la que se interpretan los horarios
+// de las alarmas de un usuario: la de su configuración o, si no está
+// registrado o su zona es inválida, fallback
+func alarmLocation(users userGetter, userID string, fallback *time.Location) *time.Location {
+	u, err := users.GetUser(userID)
+	if err != nil {
+		return fallback
+	}
+	loc, err := u.Location()
+	if err != nil {
+		return fallback
+	}
+	return loc
+}
+
+// checkAlarms retorna las alarmas que deben ejecutarse en el momento dado,
+// recuperando las de los últimos recoveryMinutes minutos. Los nombres de
+// archivo se calculan en la zona horaria de at.
+func checkAlarms(files alarmFiles, entries entryLister, userID string, at time.Time) ([]*alarm.Alarm, error) {
+	roundedTime := alarm.RoundToMinute(at)
+	result := []*alarm.Alarm{}
+
+	// 1. Chequear alarmas one-time (pending/)
+	for i := 0; i <= recoveryMinutes; i++ {
+		checkTime := roundedTime.Add(-time.Duration(i) * time.Minute)
+		filename := alarm.OneTimeFilename(checkTime)
+
+		alarms, ok, err := files.readAlarmFile(userID, false, alarm.RecurrenceOnce, filename)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		// Agregar ScheduledFor a cada alarma
+		for _, alm := range alarms {
+			alm.WithScheduledFor(checkTime)
+			result = append(result, alm)
+		}
+
+		// Mover archivo a past/
+		if err := files.moveAlarmFileToPast(userID, alarm.RecurrenceOnce, filename); err != nil {
+			return nil, fmt.Errorf("error moviendo alarma a past: %w", err)
+		}
+	}
+
+	// 2. Chequear alarmas recurrentes
+	for _, recurrence := range activeRecurrences {
+		for i := 0; i <= recoveryMinutes; i++ {
+			checkTime := roundedTime.Add(-time.Duration(i) * time.Minute)
+
+			// Verificar si ya fue ejecutada en este momento
+			wasExecuted, err := wasRecurringAlarmExecuted(files, userID, recurrence, checkTime)
+			if err != nil {
+				return nil, fmt.Errorf("error checking execution record: %w", err)
+			}
+			if !wasExecuted {
+				filename := recurringFilename(recurrence, checkTime)
+				if err := checkRecurringAlarm(files, userID, recurrence, filename, checkTime, alarm.ExecutionFilename(checkTime), &result); err != nil {
+					return nil, err
+				}
+			}
+
+			// Los demás archivos que se ejecutan en este momento (último día
+			// del mes, 2do martes...) registran su ejecución con el nombre
+			// del archivo, como las de scheduledRecurrences
+			for _, filename := range extraRecurringFilenames(recurrence, checkTime) {
+				exists, err := files.alarmFileExists(userID, false, recurrence, filename)
+				if err != nil {
+					return nil, err
+				}
+				if !exists {
+					continue
+				}
+				execFilename := scheduledExecutionFilename(checkTime, filename)
+				executed, err := files.alarmFileExists(userID, true, recurrence, execFilename)
+				if err != nil {
+					return nil, fmt.Errorf("error checking execution record: %w", err)
+				}
+				if executed {
+					continue
+				}
+				if err := checkRecurringAlarm(files, userID, recurrence, filename, checkTime, execFilename, &result); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// 3. Chequear alarmas con horario calculado (cron, interval): en lugar de
+	// buscar un archivo por minuto se recorren sus archivos y se calculan las
+	// ejecuciones de cada uno dentro de la ventana de recovery
+	for _, recurrence := range scheduledRecurrences {
+		filenames, err := files.listAlarmFiles(userID, false, recurrence)
+		if err != nil {
+			return nil, fmt.Errorf("error listando alarmas %s: %w", recurrence, err)
+		}
+		for _, filename := range filenames {
+			if err := checkScheduledAlarm(files, userID, recurrence, filename, roundedTime, &result); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 4. Recordatorios de eventos
+	if err := checkReminders(files, entries, userID, roundedTime, &result); err != nil {
+		return nil, err
+	}
+
+	// Las alarmas disparadas quedan esperando confirmación
+	if err := recordDeliveries(files, userID, roundedTime, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkReminders ejecuta los recordatorios de los eventos cuya hora cae en
+// los últimos recoveryMinutes minutos. Se calculan a partir del inicio
+// actual de cada evento (no se guardan como alarmas), así que siguen al
+// evento si se mueve y desaparecen si se elimina. Cada ejecución se
+// registra en past/reminder/ para no repetirla; no se ejecutan recordatorios
+// anteriores a la creación del evento.
+func checkReminders(files alarmFiles, entries entryLister, userID string, at time.Time, result *[]*alarm.Alarm) error {
+	from := at.Add(-time.Duration(recoveryMinutes) * time.Minute)
+	filter := calendar.NewFilter().WithDateRange(from, at.Add(calendar.MaxReminder*time.Minute))
+	filter.RangeMode = calendar.RangeStartsWithin
+
+	list, err := entries.ListEntries(userID, filter)
+	if err != nil {
+		return fmt.Errorf("error listando eventos: %w", err)
+	}
+
+	for _, entry := range list {
+		for _, minutes := range entry.Reminders {
+			fireAt := alarm.RoundToMinute(entry.ReminderTime(minutes).In(at.Location()))
+			if fireAt.Before(from) || fireAt.After(at) || fireAt.Before(alarm.RoundToMinute(entry.CreatedAt)) {
+				continue
+			}
+
+			execFilename := reminderExecutionFilename(fireAt, entry.ID, minutes)
+			executed, err := files.alarmFileExists(userID, true, alarm.RecurrenceReminder, execFilename)
+			if err != nil {
+				return fmt.Errorf("error checking execution record: %w", err)
+			}
+			if executed {
+				continue
+			}
+
+			alm := reminderAlarm(entry, minutes, fireAt)
+			if err := files.writeAlarmFile(userID, true, alarm.RecurrenceReminder, execFilename, []*alarm.Alarm{alm}); err != nil {
+				return fmt.Errorf("error copying execution record: %w", err)
+			}
+			*result = append(*result, alm)
+		}
+	}
+	return nil
+}
+
+// reminderAlarm crea la alarma del recordatorio de minutes minutos antes de entry
+func reminderAlarm(entry *calendar.Entry, minutes int, fireAt time.Time) *alarm.Alarm {
+	start := entry.LocalDateTime()
+	context := fmt.Sprintf("Reminder: %s at %s", entry.Title, start.Format("2006-01-02 15:04"))
+	if entry.Location != "" {
+		context += " (" + entry.Location + ")"
+	}
+
+	createdAt := entry.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = fireAt
+	}
+
+	return &alarm.Alarm{
+		ID:           fmt.Sprintf("alarm_reminder_%s_%s_%s", entry.ID, entry.DateTime.UTC().Format("20060102T1504Z"), calendar.FormatReminder(minutes)),
+		Context:      context,
+		CreatedAt:    createdAt,
+		Recurrence:   alarm.RecurrenceReminder,
+		ScheduledFor: fireAt,
+		Event: &alarm.EventRef{
+			ID:       entry.ID,
+			Title:    entry.Title,
+			Location: entry.Location,
+			Start:    entry.DateTime,
+			Reminder: minutes,
+		},
+	}
+}
+
+// reminderExecutionFilename retorna el registro de ejecución de un recordatorio
+// Formato: 2025-12-22_08-45-00_abc123def456_15m.json
+func reminderExecutionFilename(t time.Time, entryID string, minutes int) string {
+	return fmt.Sprintf("%s_%s_%s.json", strings.TrimSuffix(alarm.ExecutionFilename(t), ".json"), entryID, calendar.FormatReminder(minutes))
+}
+
+// checkScheduledAlarm ejecuta las alarmas de un archivo de scheduledRecurrences
+// por cada ejecución de su horario en los últimos recoveryMinutes minutos que
+// no se haya registrado todavía. No se recuperan ejecuciones anteriores a la
+// creación de la alarma más vieja del archivo.
+func checkScheduledAlarm(files alarmFiles, userID string, recurrence alarm.Recurrence, filename string, at time.Time, result *[]*alarm.Alarm) error {
+	sched, err := alarmSchedule(recurrence, filename, at.Location())
+	if err != nil {
+		// Archivo con un nombre que no es un horario: se ignora
+		return nil
+	}
+
+	alarms, ok, err := files.readAlarmFile(userID, false, recurrence, filename)
+	if err != nil {
+		return err
+	}
+	if !ok || len(alarms) == 0 {
+		return nil
+	}
+
+	created := alarms[0].CreatedAt
+	for _, alm := range alarms[1:] {
+		if alm.CreatedAt.Before(created) {
+			created = alm.CreatedAt
+		}
+	}
+	from := at.Add(-time.Duration(recoveryMinutes+1) * time.Minute)
+	if created.After(from) {
+		from = created
+	}
+
+	for t := sched.Next(from); !t.IsZero() && !t.After(at); t = sched.Next(t) {
+		execFilename := scheduledExecutionFilename(t, filename)
+		executed, err := files.alarmFileExists(userID, true, recurrence, execFilename)
+		if err != nil {
+			return fmt.Errorf("error checking execution record: %w", err)
+		}
+		if executed {
+			continue
+		}
+		if err := checkRecurringAlarm(files, userID, recurrence, filename, t, execFilename, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scheduledExecutionFilename retorna el registro de ejecución de un archivo de
+// scheduledRecurrences. A diferencia de ExecutionFilename incluye el archivo,
+// porque varios horarios pueden ejecutarse en el mismo minuto.
+// Formato: 2025-12-22_09-00-00_0_9_x_x_1-5.json
+func scheduledExecutionFilename(t time.Time, filename string) string {
+	return strings.TrimSuffix(alarm.ExecutionFilename(t), ".json") + "_" + filename
+}
+
+// recurringFilename retorna el archivo de alarmas recurrentes que corresponde a t
+func recurringFilename(recurrence alarm.Recurrence, t time.Time) string {
+	switch recurrence {
+	case alarm.RecurrenceDaily:
+		return alarm.CurrentDailyFilename(t)
+	case alarm.RecurrenceWeekly:
+		return alarm.CurrentWeeklyFilename(t)
+	case alarm.RecurrenceMonthly:
+		return alarm.CurrentMonthlyFilename(t)
+	case alarm.RecurrenceYearly:
+		return alarm.CurrentYearlyFilename(t)
+	}
+	return ""
+}
+
+// extraRecurringFilenames retorna los archivos de alarmas recurrentes que
+// también corresponden a t además del de recurringFilename
+func extraRecurringFilenames(recurrence alarm.Recurrence, t time.Time) []string {
+	switch recurrence {
+	case alarm.RecurrenceMonthly:
+		return alarm.MonthlyFilenames(t)[1:]
+	case alarm.RecurrenceYearly:
+		return alarm.YearlyFilenames(t)[1:]
+	}
+	return nil
+}
+
+// checkRecurringAlarm chequea y ejecuta las alarmas de un archivo recurrente,
+// registrando la ejecución en past/ como execFilename
+func checkRecurringAlarm(files alarmFiles, userID string, recurrence alarm.Recurrence, filename string, at time.Time, execFilename string, result *[]*alarm.Alarm) error {
+	alarms, ok, err := files.readAlarmFile(userID, false, recurrence, filename)
+	if err != nil {
+		return err
+	}
+	if !ok || len(alarms) == 0 {
+		return nil
+	}
+
+	hasExpired := false
+	activeAlarms := []*alarm.Alarm{}
+
+	for _, alm := range alarms {
+		alm.WithScheduledFor(at)
+		*result = append(*result, alm)
+
+		// Si está expirada, marcar para mover a past
+		if alm.IsExpired() {
+			hasExpired = true
+		} else {
+			activeAlarms = append(activeAlarms, alm)
+		}
+	}
+
+	// Copiar registro de ejecución a past/ para evitar duplicados
+	if len(activeAlarms) > 0 {
+		if err := files.writeAlarmFile(userID, true, recurrence, execFilename, activeAlarms); err != nil {
+			return fmt.Errorf("error copying execution record: %w", err)
+		}
+	}
+
+	// Si alguna alarma expiró, mover archivo completo a past/
+	if hasExpired {
+		if err := files.moveAlarmFileToPast(userID, recurrence, filename); err != nil {
+			return fmt.Errorf("error moving expired alarm to past: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// listActiveAlarms lista todas las alarmas activas con su próxima ejecución
+// posterior a now, calculada en la zona horaria de now
+func listActiveAlarms(files alarmFiles, userID string, now time.Time) ([]*alarm.Alarm, error) {
+	result := []*alarm.Alarm{}
+
+	for _, rec := range allRecurrences {
+		filenames, err := files.listAlarmFiles(userID, false, rec)
+		if err != nil {
+			return nil, fmt.Errorf("error listando alarmas %s: %w", rec, err)
+		}
+
+		for _, filename := range filenames {
+			alarms, err := getAlarms(files, userID, rec, filename)
+			if err != nil {
+				return nil, err
+			}
+
+			var nextRun time.Time
+			if rec == alarm.RecurrenceOnce {
+				nextRun, err = parseOneTimeFilename(filename, now.Location())
+			} else {
+				nextRun, err = calculateNextRun(rec, filename, now)
+			}
+			// Sin próxima ejecución calculable NextRun queda en cero
+			if err != nil {
+				nextRun = time.Time{}
+			}
+			for _, alm := range alarms {
+				alm.Schedule = &alarm.ScheduleInfo{
+					Filename: filename,
+					NextRun:  nextRun,
+				}
+			}
+			result = append(result, alarms...)
+		}
+	}
+
+	return result, nil
+}
+
+// listPastAlarms lista todas las alarmas pasadas, ignorando archivos ilegibles
+func listPastAlarms(files alarmFiles, userID string) ([]*alarm.Alarm, error) {
+	result := []*alarm.Alarm{}
+
+	for _, rec := range allRecurrences {
+		filenames, err := files.listAlarmFiles(userID, true, rec)
+		if err != nil {
+			continue
+		}
+
+		for _, filename := range filenames {
+			alarms, _, err := files.readAlarmFile(userID, true, rec, filename)
+			if err != nil {
+				continue
+			}
+			result = append(result, alarms...)
+		}
+	}
+
+	return result, nil
+}
+
+// cancelAlarm elimina una alarma activa por ID. Si el archivo queda vacío se elimina.
+func cancelAlarm(files alarmFiles, userID string, alarmID string) error {
+	for _, rec := range allRecurrences {
+		filenames, err := files.listAlarmFiles(userID, false, rec)
+		if err != nil {
+			continue
+		}
+
+		for _, filename := range filenames {
+			alarms, _, err := files.readAlarmFile(userID, false, rec, filename)
+			if err != nil {
+				continue
+			}
+
+			// Buscar la alarma por ID
+			found := false
+			newAlarms := []*alarm.Alarm{}
+			for _, alm := range alarms {
+				if alm.ID == alarmID {
+					found = true
+				} else {
+					newAlarms = append(newAlarms, alm)
+				}
+			}
+			if !found {
+				continue
+			}
+
+			// Si quedan alarmas, reescribir el archivo; si no, eliminarlo
+			if len(newAlarms) > 0 {
+				return files.writeAlarmFile(userID, false, rec, filename, newAlarms)
+			}
+			return files.removeAlarmFile(userID, rec, filename)
+		}
+	}
+
+	return fmt.Errorf("alarm not found: %s", alarmID)
+}
+
+// recordDeliveries agrega las alarmas disparadas en at a las que esperan
+// confirmación. Una nueva entrega de una alarma reemplaza a la anterior sin
+// confirmar, así que queda como máximo una por alarma.
+func recordDeliveries(files alarmFiles, userID string, at time.Time, fired []*alarm.Alarm) error {
+	unacked, err := files.readUnackedAlarms(userID)
+	if err != nil {
+		return err
+	}
+	kept, changed := pruneUnacked(unacked, at)
+	if len(fired) == 0 && !changed {
+		return nil
+	}
+
+	ids := make(map[string]bool, len(fired))
+	for _, alm := range fired {
+		ids[alm.ID] = true
+	}
+	pending := []*alarm.Alarm{}
+	for _, alm := range kept {
+		if !ids[alm.ID] {
+			pending = append(pending, alm)
+		}
+	}
+	for _, alm := range fired {
+		delivery := alm.Clone()
+		delivery.DeliveredAt = &at
+		delivery.Deliveries = 1
+		pending = append(pending, delivery)
+	}
+
+	return files.writeUnackedAlarms(userID, pending)
+}
+
+// pruneUnacked descarta las alarmas que esperan confirmación hace más de
+// unackedRetention; changed indica si se descartó alguna
+func pruneUnacked(unacked []*alarm.Alarm, at time.Time) (kept []*alarm.Alarm, changed bool) {
+	kept = []*alarm.Alarm{}
+	for _, alm := range unacked {
+		if at.Sub(alm.ScheduledFor) > unackedRetention {
+			changed = true
+			continue
+		}
+		kept = append(kept, alm)
+	}
+	return kept, changed
+}
+
+// nagAlarms vuelve a entregar las alarmas sin confirmar cuya última entrega
+// fue hace every o más
+func nagAlarms(files alarmFiles, userID string, at time.Time, every time.Duration) ([]*alarm.Alarm, error) {
+	if every < time.Minute {
+		return nil, fmt.Errorf("invalid nag interval %s: must be at least 1m", every)
+	}
+
+	at = alarm.RoundToMinute(at)
+	unacked, err := files.readUnackedAlarms(userID)
+	if err != nil {
+		return nil, err
+	}
+	pending, changed := pruneUnacked(unacked, at)
+
+	result := []*alarm.Alarm{}
+	for _, alm := range pending {
+		if alm.DeliveredAt != nil && at.Before(alm.DeliveredAt.Add(every)) {
+			continue
+		}
+		alm.DeliveredAt = &at
+		alm.Deliveries++
+		result = append(result, alm.Clone())
+		changed = true
+	}
+
+	if changed {
+		if err := files.writeUnackedAlarms(userID, pending); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// ackAlarm confirma una alarma disparada: deja de esperar confirmación y no
+// se vuelve a entregar
+func ackAlarm(files alarmFiles, userID string, alarmID string) error {
+	unacked, err := files.readUnackedAlarms(userID)
+	if err != nil {
+		return err
+	}
+
+	pending := []*alarm.Alarm{}
+	for _, alm := range unacked {
+		if alm.ID != alarmID {
+			pending = append(pending, alm)
+		}
+	}
+	if len(pending) == len(unacked) {
+		return fmt.Errorf("alarm not awaiting acknowledgement: %s", alarmID)
+	}
+
+	return files.writeUnackedAlarms(userID, pending)
+}
+
+// snoozeAlarm repite una alarma disparada como una nueva alarma one-time
+// para at+d, vinculada a la original. Posponer una alarma también la confirma.
+func snoozeAlarm(files alarmFiles, userID string, alarmID string, at time.Time, d time.Duration) (*alarm.Alarm, error) {
+	if d < time.Minute {
+		return nil, fmt.Errorf("invalid snooze %s: must be at least 1m", d)
+	}
+
+	// Buscar la alarma entre las que esperan confirmación y, si no, entre
+	// las ya ejecutadas
+	unacked, err := files.readUnackedAlarms(userID)
+	if err != nil {
+		return nil, err
+	}
+	var fired *alarm.Alarm
+	for _, alm := range unacked {
+		if alm.ID == alarmID {
+			fired = alm
+		}
+	}
+	delivered := fired != nil
+	if fired == nil {
+		past, err := listPastAlarms(files, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, alm := range past {
+			if alm.ID == alarmID {
+				fired = alm
+			}
+		}
+	}
+	if fired == nil {
+		return nil, fmt.Errorf("fired alarm not found: %s", alarmID)
+	}
+
+	until := alarm.RoundToMinute(at.Add(d))
+	snoozed := fired.Snooze()
+	if err := saveAlarm(files, userID, alarm.RecurrenceOnce, alarm.OneTimeFilename(until), snoozed); err != nil {
+		return nil, err
+	}
+
+	if delivered {
+		if err := ackAlarm(files, userID, alarmID); err != nil {
+			return nil, err
+		}
+	}
+
+	return snoozed.WithScheduledFor(until), nil
+}
+
+// copyRecurringAlarmExecution guarda en past/ las alarmas recurrentes disparadas
+// con el timestamp de ejecución. Esto permite rastrear cuándo se disparó cada
+// alarma recurrente y evitar duplicados.
+func copyRecurringAlarmExecution(files alarmFiles, userID string, recurrence alarm.Recurrence, alarms []*alarm.Alarm, executedAt time.Time) error {
+	if len(alarms) == 0 {
+		return nil
+	}
+	return files.writeAlarmFile(userID, true, recurrence, alarm.ExecutionFilename(executedAt), alarms)
+}
+
+// wasRecurringAlarmExecuted verifica si una alarma recurrente ya fue ejecutada en un momento dado
+func wasRecurringAlarmExecuted(files alarmFiles, userID string, recurrence alarm.Recurrence, executedAt time.Time) (bool, error) {
+	ok, err := files.alarmFileExists(userID, true, recurrence, alarm.ExecutionFilename(executedAt))
+	if err != nil {
+		return false, fmt.Errorf("error checking execution record: %w", err)
+	}
+	return ok, nil
+}
+
+// parseOneTimeFilename parsea un filename de alarma one-time y retorna el tiempo en loc
+// Formato: 2025-12-21_01-10-00.json
+func parseOneTimeFilename(filename string, loc *time.Location) (time.Time, error) {
+	// Remover extensión .json
+	filename = strings.TrimSuffix(filename, ".json")
+
+	// Parsear formato YYYY-MM-DD_HH-MM-SS
+	return time.ParseInLocation("2006-01-02_15-04-05", filename, loc)
+}
+
+// calculateNextRun calcula la próxima ejecución posterior a now de una alarma
+// recurrente basándose en su filename, en la zona horaria de now
+func calculateNextRun(recurrence alarm.Recurrence, filename string, now time.Time) (time.Time, error) {
+	if containsRecurrence(scheduledRecurrences, recurrence) {
+		sched, err := alarmSchedule(recurrence, filename, now.Location())
+		if err != nil {
+			return time.Time{}, err
+		}
+		return sched.Next(now), nil
+	}
+
+	// Remover extensión .json
+	filename = strings.TrimSuffix(filename, ".json")
+	loc := now.Location()
+
+	switch recurrence {
+	case alarm.RecurrenceDaily:
+		// Formato: 14-30-00.json
+		parts := strings.Split(filename, "-")
+		if len(parts) < 2 {
+			return time.Time{}, fmt.Errorf("invalid daily filename format")
+		}
+		hour, _ := time.Parse("15", parts[0])
+		minute, _ := time.Parse("04", parts[1])
+
+		h := hour.Hour()
+		m := minute.Minute()
+
+		// Calcular próxima ejecución (hoy o mañana)
+		nextRun := time.Date(now.Year(), now.Month(), now.Day(), h, m, 0, 0, loc)
+		if nextRun.Before(now) {
+			nextRun = nextRun.AddDate(0, 0, 1)
+		}
+		return nextRun, nil
+
+	case alarm.RecurrenceWeekly:
+		// Formato: monday_14-30-00.json
+		parts := strings.Split(filename, "_")
+		if len(parts) < 2 {
+			return time.Time{}, fmt.Errorf("invalid weekly filename format")
+		}
+
+		weekdayStr := parts[0]
+		timeParts := strings.Split(parts[1], "-")
+		if len(timeParts) < 2 {
+			return time.Time{}, fmt.Errorf("invalid weekly time format")
+		}
+
+		// Parsear weekday
+		var targetWeekday time.Weekday
+		switch strings.ToLower(weekdayStr) {
+		case "sunday":
+			targetWeekday = time.Sunday
+		case "monday":
+			targetWeekday = time.Monday
+		case "tuesday":
+			targetWeekday = time.Tuesday
+		case "wednesday":
+			targetWeekday = time.Wednesday
+		case "thursday":
+			targetWeekday = time.Thursday
+		case "friday":
+			targetWeekday = time.Friday
+		case "saturday":
+			targetWeekday = time.Saturday
+		default:
+			return time.Time{}, fmt.Errorf("invalid weekday: %s", weekdayStr)
+		}
+
+		hour, _ := time.Parse("15", timeParts[0])
+		minute, _ := time.Parse("04", timeParts[1])
+		h := hour.Hour()
+		m := minute.Minute()
+
+		// Calcular días hasta el próximo weekday
+		daysUntil := int(targetWeekday - now.Weekday())
+		if daysUntil < 0 {
+			daysUntil += 7
+		}
+		if daysUntil == 0 {
+			// Es hoy, verificar si ya pasó la hora
+			todayTime := time.Date(now.Year(), now.Month(), now.Day(), h, m, 0, 0, loc)
+			if todayTime.Before(now) {
+				daysUntil = 7
+			}
+		}
+
+		nextRun := time.Date(now.Year(), now.Month(), now.Day()+daysUntil, h, m, 0, 0, loc)
+		return nextRun, nil
+
+	case alarm.RecurrenceMonthly:
+		// Formato: 15_14-30-00.json, 2nd-tuesday_14-30-00.json...
+		schedule, err := alarm.ParseMonthlyFilename(filename)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return schedule.Next(now), nil
+
+	case alarm.RecurrenceYearly:
+		// Formato: 11-21_14-30-00.json, 11-4th-thursday_10-00-00.json...
+		schedule, err := alarm.ParseYearlyFilename(filename)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return schedule.Next(now), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported recurrence type: %s", recurrence)
+}
+
+// containsRecurrence indica si rec está en list
+func containsRecurrence(list []alarm.Recurrence, rec alarm.Recurrence) bool {
+	for _, r := range list {
+		if r == rec {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,469 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/user"
+)
+
+func TestCheckAlarmsCron(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newTestStorage(t, backend)
+
+		// Cada 20 minutos de 9 a 10, de lunes a viernes (2025-12-22 es lunes)
+		sched, err := alarm.ParseCron("*/20 9 * * mon-fri")
+		if err != nil {
+			t.Fatal(err)
+		}
+		alm := alarm.NewAlarm("revisar la cola", alarm.RecurrenceCron)
+		alm.CreatedAt = time.Date(2025, 12, 22, 9, 10, 0, 0, time.UTC)
+		if err := s.SaveAlarm("u1", alm.CreatedAt, alarm.RecurrenceCron, sched.Filename(), alm); err != nil {
+			t.Fatal(err)
+		}
+
+		check := func(at time.Time) int {
+			t.Helper()
+			fired, err := s.CheckAlarms("u1", at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return len(fired)
+		}
+
+		// 9:20 y 9:40 se recuperan; 9:00 es anterior a la creación
+		if got := check(time.Date(2025, 12, 22, 9, 45, 0, 0, time.UTC)); got != 2 {
+			t.Errorf("CheckAlarms(9:45) = %d alarmas, want 2", got)
+		}
+		// Ya registradas: no se repiten
+		if got := check(time.Date(2025, 12, 22, 9, 50, 0, 0, time.UTC)); got != 0 {
+			t.Errorf("CheckAlarms(9:50) = %d alarmas, want 0", got)
+		}
+		// Fuera del horario
+		if got := check(time.Date(2025, 12, 22, 12, 0, 0, 0, time.UTC)); got != 0 {
+			t.Errorf("CheckAlarms(12:00) = %d alarmas, want 0", got)
+		}
+		// Al día siguiente solo se recupera la última hora: 9:40 pero no 9:00 ni 9:20
+		if got := check(time.Date(2025, 12, 23, 10, 30, 0, 0, time.UTC)); got != 1 {
+			t.Errorf("CheckAlarms(10:30) = %d alarmas, want 1", got)
+		}
+
+		active, err := s.ListActiveAlarms("u1")
+		if err != nil || len(active) != 1 || active[0].Schedule == nil || active[0].Schedule.NextRun.IsZero() {
+			t.Fatalf("ListActiveAlarms() = %v, %v", active, err)
+		}
+		if !sched.Matches(active[0].Schedule.NextRun) {
+			t.Errorf("NextRun = %v no cumple el horario", active[0].Schedule.NextRun)
+		}
+	})
+}
+
+func TestCheckAlarmsCronUserTimezone(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newTestStorage(t, backend)
+
+		loc, err := time.LoadLocation("Pacific/Kiritimati")
+		if err != nil {
+			t.Skip(err)
+		}
+		if err := s.SaveUser(user.NewUser("u1", "Ana", loc.String())); err != nil {
+			t.Fatal(err)
+		}
+
+		// Las 9 de lunes a viernes en la zona del usuario (UTC+14)
+		sched, err := alarm.ParseCron("0 9 * * mon-fri")
+		if err != nil {
+			t.Fatal(err)
+		}
+		at := time.Date(2025, 12, 22, 9, 0, 0, 0, loc)
+		alm := alarm.NewAlarm("standup", alarm.RecurrenceCron)
+		alm.CreatedAt = at.Add(-time.Hour)
+		if err := s.SaveAlarm("u1", alm.CreatedAt, alarm.RecurrenceCron, sched.Filename(), alm); err != nil {
+			t.Fatal(err)
+		}
+
+		// Las 9 UTC (23 del usuario) no cumplen el horario; las 19 UTC sí
+		for _, tt := range []struct {
+			at   time.Time
+			want int
+		}{
+			{time.Date(2025, 12, 22, 9, 0, 0, 0, time.UTC), 0},
+			{at.UTC(), 1},
+		} {
+			fired, err := s.CheckAlarms("u1", tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fired) != tt.want {
+				t.Errorf("CheckAlarms(%v) = %d alarmas, want %d", tt.at, len(fired), tt.want)
+			}
+		}
+
+		active, err := s.ListActiveAlarms("u1")
+		if err != nil || len(active) != 1 || active[0].Schedule == nil {
+			t.Fatalf("ListActiveAlarms() = %v, %v", active, err)
+		}
+		if next := active[0].Schedule.NextRun; next.In(loc).Hour() != 9 {
+			t.Errorf("NextRun = %v, want 09:00 %s", next, loc)
+		}
+	})
+}
+
+func TestCheckAlarmsInterval(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newTestStorage(t, backend)
+
+		// Cada 45 minutos de 8:00 a 10:00 (8:00, 8:45, 9:30)
+		window, _ := alarm.ParseTimeWindow("08:00-10:00")
+		sched, err := alarm.NewIntervalSchedule(time.Date(2025, 12, 22, 8, 0, 0, 0, time.UTC), 45*time.Minute, window)
+		if err != nil {
+			t.Fatal(err)
+		}
+		alm := alarm.NewAlarm("medicación", alarm.RecurrenceInterval)
+		alm.CreatedAt = time.Date(2025, 12, 22, 7, 0, 0, 0, time.UTC)
+		if err := s.SaveAlarm("u1", alm.CreatedAt, alarm.RecurrenceInterval, sched.Filename(), alm); err != nil {
+			t.Fatal(err)
+		}
+
+		check := func(at time.Time) []time.Time {
+			t.Helper()
+			fired, err := s.CheckAlarms("u1", at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var times []time.Time
+			for _, a := range fired {
+				times = append(times, a.ScheduledFor)
+			}
+			return times
+		}
+
+		if got := check(time.Date(2025, 12, 22, 8, 50, 0, 0, time.UTC)); len(got) != 2 {
+			t.Errorf("CheckAlarms(8:50) = %v, want 8:00 y 8:45", got)
+		}
+		if got := check(time.Date(2025, 12, 22, 9, 30, 0, 0, time.UTC)); len(got) != 1 || got[0].Hour() != 9 || got[0].Minute() != 30 {
+			t.Errorf("CheckAlarms(9:30) = %v, want 9:30", got)
+		}
+		// Fuera de la franja no se dispara (10:15 sería la siguiente sin franja)
+		if got := check(time.Date(2025, 12, 22, 10, 20, 0, 0, time.UTC)); len(got) != 0 {
+			t.Errorf("CheckAlarms(10:20) = %v, want ninguna", got)
+		}
+		if got := check(time.Date(2025, 12, 23, 8, 0, 0, 0, time.UTC)); len(got) != 1 {
+			t.Errorf("CheckAlarms(8:00 del día siguiente) = %v, want 8:00", got)
+		}
+	})
+}
+
+func TestCheckAlarmsMonthlyDays(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newTestStorage(t, backend)
+
+		// El 2026-02-27 (viernes) es el último día hábil y el último viernes de
+		// febrero; el 28 (sábado) es el último día y el de los días 29-31 ajustados
+		schedules := map[string]alarm.MonthlySchedule{
+			"day 27":       {Day: 27, Hour: 9},
+			"last weekday": {Day: alarm.LastBusinessDay, Hour: 9},
+			"last friday":  {Nth: alarm.LastWeek, Weekday: time.Friday, Hour: 9},
+			"4th friday":   {Nth: 4, Weekday: time.Friday, Hour: 9},
+			"last day":     {Day: alarm.LastDay, Hour: 9},
+			"31 clamp":     {Day: 31, Clamp: true, Hour: 9},
+			"31":           {Day: 31, Hour: 9},
+		}
+		for context, sched := range schedules {
+			if err := s.SaveAlarm("u1", time.Now(), alarm.RecurrenceMonthly, sched.Filename(), alarm.NewAlarm(context, alarm.RecurrenceMonthly)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		check := func(at time.Time) map[string]bool {
+			t.Helper()
+			fired, err := s.CheckAlarms("u1", at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			contexts := map[string]bool{}
+			for _, a := range fired {
+				contexts[a.Context] = true
+			}
+			return contexts
+		}
+
+		friday := time.Date(2026, 2, 27, 9, 0, 0, 0, time.UTC)
+		got := check(friday)
+		for _, want := range []string{"day 27", "last weekday", "last friday", "4th friday"} {
+			if !got[want] {
+				t.Errorf("CheckAlarms(27/02) no disparó %q: %v", want, got)
+			}
+		}
+		if len(got) != 4 {
+			t.Errorf("CheckAlarms(27/02) = %v, want 4 alarmas", got)
+		}
+		if got := check(friday.Add(10 * time.Minute)); len(got) != 0 {
+			t.Errorf("CheckAlarms(27/02) repetido = %v", got)
+		}
+
+		got = check(friday.AddDate(0, 0, 1))
+		if len(got) != 2 || !got["last day"] || !got["31 clamp"] {
+			t.Errorf("CheckAlarms(28/02) = %v, want last day y 31 clamp", got)
+		}
+
+		// Sin clamp el 31 solo se ejecuta en meses de 31 días; calculateNextRun coincide
+		next, err := calculateNextRun(alarm.RecurrenceMonthly, schedules["31"].Filename(), time.Now())
+		if err != nil || next.Day() != 31 {
+			t.Errorf("calculateNextRun(31) = %v, %v", next, err)
+		}
+	})
+}
+
+func TestCheckAlarmsUserTimezone(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newTestStorage(t, backend)
+
+		// Una zona distinta de la del proceso: los horarios se interpretan en
+		// la zona del usuario aunque el chequeo reciba la hora en otra zona
+		loc, err := time.LoadLocation("Pacific/Kiritimati")
+		if err != nil {
+			t.Skip(err)
+		}
+		if err := s.SaveUser(user.NewUser("u1", "Ana", loc.String())); err != nil {
+			t.Fatal(err)
+		}
+
+		// 09:00 del usuario (UTC+14) son las 19:00 UTC del día anterior
+		at := time.Date(2025, 12, 22, 9, 0, 0, 0, loc)
+		once := alarm.NewAlarm("una vez", alarm.RecurrenceOnce)
+		if err := s.SaveAlarm("u1", at, alarm.RecurrenceOnce, alarm.OneTimeFilename(at), once); err != nil {
+			t.Fatal(err)
+		}
+		daily := alarm.NewAlarm("todos los días", alarm.RecurrenceDaily)
+		if err := s.SaveAlarm("u1", at, alarm.RecurrenceDaily, alarm.DailySchedule{Hour: 9}.Filename(), daily); err != nil {
+			t.Fatal(err)
+		}
+
+		active, err := s.ListActiveAlarms("u1")
+		if err != nil || len(active) != 2 {
+			t.Fatalf("ListActiveAlarms() = %v, %v", active, err)
+		}
+		for _, alm := range active {
+			next := alm.Schedule.NextRun
+			if alm.ID == once.ID && !next.Equal(at) {
+				t.Errorf("NextRun(once) = %v, want %v", next, at)
+			}
+			if alm.ID == daily.ID && (next.Location().String() != loc.String() || next.Hour() != 9) {
+				t.Errorf("NextRun(daily) = %v, want 09:00 %s", next, loc)
+			}
+		}
+
+		fired, err := s.CheckAlarms("u1", at.UTC())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fired) != 2 {
+			t.Errorf("CheckAlarms(19:00 UTC) = %d alarmas, want 2", len(fired))
+		}
+
+		// Las 09:00 UTC no son las 09:00 del usuario
+		fired, err = s.CheckAlarms("u1", time.Date(2025, 12, 23, 9, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fired) != 0 {
+			t.Errorf("CheckAlarms(09:00 UTC) = %d alarmas, want 0", len(fired))
+		}
+	})
+}
+
+func TestCheckAlarmsReminders(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newTestStorage(t, backend)
+
+		entry := calendar.NewEntry("u1", "Dentista", time.Date(2025, 12, 22, 9, 0, 0, 0, time.Local), 30)
+		entry.Location = "Centro"
+		entry.Reminders = []int{15, 24 * 60}
+		entry.CreatedAt = time.Date(2025, 12, 1, 0, 0, 0, 0, time.Local)
+		if err := s.SaveEntry("u1", entry); err != nil {
+			t.Fatal(err)
+		}
+
+		check := func(at time.Time) []*alarm.Alarm {
+			t.Helper()
+			fired, err := s.CheckAlarms("u1", at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return fired
+		}
+
+		// El recordatorio de 1d se dispara el día anterior
+		if got := check(time.Date(2025, 12, 21, 9, 0, 0, 0, time.Local)); len(got) != 1 || got[0].Event == nil || got[0].Event.Reminder != 24*60 {
+			t.Fatalf("CheckAlarms(día anterior) = %v, want recordatorio de 1d", got)
+		}
+
+		fired := check(time.Date(2025, 12, 22, 8, 50, 0, 0, time.Local))
+		if len(fired) != 1 {
+			t.Fatalf("CheckAlarms(8:50) = %d alarmas, want 1", len(fired))
+		}
+		alm := fired[0]
+		if alm.Recurrence != alarm.RecurrenceReminder || alm.Event.ID != entry.ID || alm.Event.Location != "Centro" {
+			t.Errorf("Unexpected reminder alarm: %+v %+v", alm, alm.Event)
+		}
+		if alm.ScheduledFor.Hour() != 8 || alm.ScheduledFor.Minute() != 45 {
+			t.Errorf("Expected reminder at 8:45, got %v", alm.ScheduledFor)
+		}
+		if got := check(time.Date(2025, 12, 22, 8, 55, 0, 0, time.Local)); len(got) != 0 {
+			t.Errorf("CheckAlarms(8:55) = %v, want ninguna (ya ejecutado)", got)
+		}
+
+		// Mover el evento mueve el recordatorio
+		entry.DateTime = time.Date(2025, 12, 22, 11, 0, 0, 0, time.Local)
+		if err := s.UpdateEntry("u1", entry); err != nil {
+			t.Fatal(err)
+		}
+		if got := check(time.Date(2025, 12, 22, 10, 45, 0, 0, time.Local)); len(got) != 1 {
+			t.Errorf("CheckAlarms(10:45) = %v, want recordatorio del evento movido", got)
+		}
+
+		// Eliminar el evento cancela sus recordatorios
+		entry.DateTime = time.Date(2025, 12, 22, 13, 0, 0, 0, time.Local)
+		if err := s.UpdateEntry("u1", entry); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.DeleteEntry("u1", entry.ID); err != nil {
+			t.Fatal(err)
+		}
+		if got := check(time.Date(2025, 12, 22, 12, 45, 0, 0, time.Local)); len(got) != 0 {
+			t.Errorf("CheckAlarms(12:45) = %v, want ninguna (evento eliminado)", got)
+		}
+	})
+}
+
+func TestCheckAlarmsRemindersRecurring(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newTestStorage(t, backend)
+
+		entry := calendar.NewEntry("u1", "Daily", time.Date(2025, 12, 22, 9, 0, 0, 0, time.Local), 15)
+		entry.RRule, _ = calendar.ParseRecurrenceRule("FREQ=DAILY")
+		entry.Reminders = []int{10}
+		entry.CreatedAt = time.Date(2025, 12, 1, 0, 0, 0, 0, time.Local)
+		if err := s.SaveEntry("u1", entry); err != nil {
+			t.Fatal(err)
+		}
+
+		for day := 22; day <= 24; day++ {
+			fired, err := s.CheckAlarms("u1", time.Date(2025, 12, day, 8, 50, 0, 0, time.Local))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fired) != 1 || fired[0].ScheduledFor.Day() != day {
+				t.Errorf("CheckAlarms(%d 8:50) = %v, want recordatorio de la ocurrencia", day, fired)
+			}
+		}
+	})
+}
+
+func TestAckSnoozeNag(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newTestStorage(t, backend)
+
+		at := time.Date(2025, 12, 22, 9, 0, 0, 0, time.Local)
+		alm := alarm.NewAlarm("tomar medicación", alarm.RecurrenceOnce)
+		if err := s.SaveAlarm("u1", at, alarm.RecurrenceOnce, alarm.OneTimeFilename(at), alm); err != nil {
+			t.Fatal(err)
+		}
+		if fired, err := s.CheckAlarms("u1", at); err != nil || len(fired) != 1 {
+			t.Fatalf("CheckAlarms() = %v, %v", fired, err)
+		}
+
+		unacked, err := s.ListUnackedAlarms("u1")
+		if err != nil || len(unacked) != 1 || unacked[0].ID != alm.ID {
+			t.Fatalf("ListUnackedAlarms() = %v, %v", unacked, err)
+		}
+
+		// Se vuelve a entregar cada 5 minutos mientras no se confirme
+		nag := func(at time.Time) []*alarm.Alarm {
+			t.Helper()
+			alarms, err := s.NagAlarms("u1", at, 5*time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return alarms
+		}
+		if got := nag(at.Add(4 * time.Minute)); len(got) != 0 {
+			t.Errorf("NagAlarms(+4m) = %v, want ninguna", got)
+		}
+		if got := nag(at.Add(5 * time.Minute)); len(got) != 1 || got[0].Deliveries != 2 {
+			t.Errorf("NagAlarms(+5m) = %v, want segunda entrega", got)
+		}
+		if got := nag(at.Add(7 * time.Minute)); len(got) != 0 {
+			t.Errorf("NagAlarms(+7m) = %v, want ninguna", got)
+		}
+
+		// Posponer crea una alarma one-time vinculada y confirma la original
+		snoozed, err := s.SnoozeAlarm("u1", alm.ID, at.Add(8*time.Minute), 10*time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snoozed.SnoozedFrom != alm.ID || !snoozed.ScheduledFor.Equal(at.Add(18*time.Minute)) {
+			t.Errorf("Unexpected snoozed alarm: %+v", snoozed)
+		}
+		if got := nag(at.Add(15 * time.Minute)); len(got) != 0 {
+			t.Errorf("NagAlarms() after snooze = %v, want ninguna", got)
+		}
+		fired, err := s.CheckAlarms("u1", at.Add(18*time.Minute))
+		if err != nil || len(fired) != 1 || fired[0].ID != snoozed.ID || fired[0].Context != alm.Context {
+			t.Fatalf("CheckAlarms(+18m) = %v, %v, want alarma pospuesta", fired, err)
+		}
+
+		if err := s.AckAlarm("u1", snoozed.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AckAlarm("u1", snoozed.ID); err == nil {
+			t.Error("Expected error acknowledging twice")
+		}
+		if got := nag(at.Add(30 * time.Minute)); len(got) != 0 {
+			t.Errorf("NagAlarms() after ack = %v, want ninguna", got)
+		}
+
+		// Una alarma ya confirmada se puede seguir posponiendo desde past/
+		if _, err := s.SnoozeAlarm("u1", alm.ID, at.Add(40*time.Minute), 5*time.Minute); err != nil {
+			t.Errorf("SnoozeAlarm() of a past alarm error = %v", err)
+		}
+		if _, err := s.SnoozeAlarm("u1", "alarm_once_0_missing", at, 5*time.Minute); err == nil {
+			t.Error("Expected error snoozing an unknown alarm")
+		}
+	})
+}
+
+func TestUnackedRecurringAlarm(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newTestStorage(t, backend)
+
+		alm := alarm.NewAlarm("stand-up", alarm.RecurrenceDaily)
+		alm.CreatedAt = time.Date(2025, 12, 1, 0, 0, 0, 0, time.Local)
+		filename := alarm.DailySchedule{Hour: 9, Minute: 0}.Filename()
+		if err := s.SaveAlarm("u1", alm.CreatedAt, alarm.RecurrenceDaily, filename, alm); err != nil {
+			t.Fatal(err)
+		}
+
+		// Cada ejecución reemplaza a la anterior sin confirmar
+		for day := 22; day <= 23; day++ {
+			if _, err := s.CheckAlarms("u1", time.Date(2025, 12, day, 9, 0, 0, 0, time.Local)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		unacked, err := s.ListUnackedAlarms("u1")
+		if err != nil || len(unacked) != 1 || unacked[0].ScheduledFor.Day() != 23 {
+			t.Fatalf("ListUnackedAlarms() = %v, %v, want solo la del 23", unacked, err)
+		}
+
+		// Pasado unackedRetention se deja de esperar confirmación
+		if got, err := s.NagAlarms("u1", time.Date(2025, 12, 24, 9, 1, 0, 0, time.Local), time.Hour); err != nil || len(got) != 0 {
+			t.Errorf("NagAlarms() = %v, %v, want ninguna", got, err)
+		}
+		if unacked, _ := s.ListUnackedAlarms("u1"); len(unacked) != 0 {
+			t.Errorf("Expected expired delivery to be dropped, got %v", unacked)
+		}
+	})
+}
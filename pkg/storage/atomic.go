@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// crashHook permite a los tests simular que el proceso muere en un punto de
+// una escritura: si retorna true para (op, path) la operación se corta ahí
+// sin limpiar nada, como haría un kill. op es "write" (a mitad del archivo
+// temporal), "rename" (antes de reemplazar el archivo) o "remove".
+var crashHook func(op, path string) bool
+
+// errCrash es el error de una escritura cortada por crashHook
+var errCrash = errors.New("escritura interrumpida")
+
+// crashAt indica si crashHook pide cortar la operación
+func crashAt(op, path string) bool {
+	return crashHook != nil && crashHook(op, path)
+}
+
+// writeFileAtomic escribe data en path de forma atómica: escribe un archivo
+// temporal en el mismo directorio, lo sincroniza a disco y lo renombra sobre
+// path. Ante cualquier falla path queda con su contenido anterior (o sin
+// existir). Los temporales (.<nombre>.*.tmp) no terminan en .json, así que
+// nunca se leen como datos.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir, base := filepath.Split(path)
+	tmp, err := os.CreateTemp(dir, "."+base+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if crashAt("write", path) {
+		tmp.Write(data[:len(data)/2])
+		tmp.Close()
+		return errCrash
+	}
+
+	cleanup := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return cleanup(err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return cleanup(err)
+	}
+
+	if crashAt("rename", path) {
+		return errCrash
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+// removeFile elimina un archivo; no es error si no existe
+func removeFile(path string) error {
+	if crashAt("remove", path) {
+		return errCrash
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// syncDir sincroniza un directorio para que los renames y borrados sobrevivan
+// a un corte de energía. Es best-effort: algunos sistemas (Windows) no
+// permiten sincronizar directorios.
+func syncDir(dir string) {
+	if dir == "" {
+		dir = "."
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
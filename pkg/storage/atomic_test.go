@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+// crashAfter hace que la operación número n (desde 0) de escritura o borrado
+// se corte como si el proceso muriera ahí. Retorna un puntero a la cantidad
+// de operaciones vistas.
+func crashAfter(t *testing.T, n int) *int {
+	count := 0
+	crashHook = func(op, path string) bool {
+		count++
+		return count == n+1
+	}
+	t.Cleanup(func() { crashHook = nil })
+	return &count
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	tests := []struct {
+		name    string
+		crashOp string
+		want    string
+	}{
+		{"completa", "", "nuevo contenido"},
+		{"corte a mitad de la escritura", "write", "viejo"},
+		{"corte antes del rename", "rename", "viejo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "data.json")
+			if err := os.WriteFile(path, []byte("viejo"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			crashHook = func(op, p string) bool { return op == tt.crashOp }
+			t.Cleanup(func() { crashHook = nil })
+
+			err := writeFileAtomic(path, []byte("nuevo contenido"), 0644)
+			if (err != nil) != (tt.crashOp != "") {
+				t.Fatalf("writeFileAtomic() error = %v", err)
+			}
+
+			data, _ := os.ReadFile(path)
+			if string(data) != tt.want {
+				t.Errorf("contenido = %q, want %q", data, tt.want)
+			}
+
+			// El temporal que deja el corte nunca se confunde con un archivo de datos
+			files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+			if len(files) != 1 {
+				t.Errorf("archivos .json = %v, want solo data.json", files)
+			}
+		})
+	}
+}
+
+func TestUpdateEntryCrash(t *testing.T) {
+	day := time.Date(2025, 11, 17, 10, 0, 0, 0, time.UTC)
+	week := calendar.NewFilter().WithDateRange(day.AddDate(0, 0, 7), day.AddDate(0, 0, 8))
+
+	setup := func(t *testing.T) (string, *calendar.Entry) {
+		dir := t.TempDir()
+		fs, err := NewFilesystemStorage(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		weekly := calendar.NewEntry("u1", "Weekly", day, 30)
+		weekly.RRule, _ = calendar.ParseRecurrenceRule("FREQ=WEEKLY")
+		weekly.UpdatedAt = day
+		if err := fs.SaveEntry("u1", weekly); err != nil {
+			t.Fatal(err)
+		}
+
+		occ := weekly.Occurrence(day.AddDate(0, 0, 7))
+		occ.Title = "Weekly (moved)"
+		occ.DateTime = occ.DateTime.Add(3 * time.Hour)
+		if err := fs.SaveEntry("u1", occ); err != nil {
+			t.Fatal(err)
+		}
+		return dir, weekly
+	}
+
+	// Cortar la actualización en cada una de sus operaciones: al volver a
+	// abrir el storage el evento existe una sola vez, con la versión vieja o
+	// la nueva completa, y conserva su override.
+	for n := 0; ; n++ {
+		dir, weekly := setup(t)
+		fs, _ := NewFilesystemStorage(dir)
+
+		updated := *weekly
+		updated.Title = "Weekly renamed"
+		count := crashAfter(t, n)
+		err := fs.UpdateEntry("u1", &updated)
+		crashHook = nil
+		if err == nil {
+			if n == 0 {
+				t.Fatal("UpdateEntry no escribió nada")
+			}
+			break
+		}
+		if *count != n+1 {
+			t.Fatalf("corte %d: UpdateEntry() error = %v", n, err)
+		}
+
+		reopened, _ := NewFilesystemStorage(dir)
+		got, err := reopened.GetEntry("u1", weekly.ID)
+		if err != nil {
+			t.Fatalf("corte %d: el evento se perdió: %v", n, err)
+		}
+		if got.Title != "Weekly" && got.Title != "Weekly renamed" {
+			t.Errorf("corte %d: título = %q", n, got.Title)
+		}
+
+		occ, err := reopened.GetOccurrence("u1", weekly.ID, day.AddDate(0, 0, 7))
+		if err != nil || occ.Title != "Weekly (moved)" {
+			t.Errorf("corte %d: GetOccurrence() = %v, %v; el override se perdió", n, occ, err)
+		}
+
+		list, err := reopened.ListEntries("u1", week)
+		if err != nil {
+			t.Fatalf("corte %d: ListEntries: %v", n, err)
+		}
+		want := []string{weekly.ID + "@2025-11-24T13:00:00Z"}
+		if !reflect.DeepEqual(entryKeys(list), want) {
+			t.Errorf("corte %d: ListEntries() = %v, want %v", n, entryKeys(list), want)
+		}
+
+		// Los restos de la actualización interrumpida ya se limpiaron
+		if scanned := scanEntries(t, reopened, "u1", week); !reflect.DeepEqual(entryKeys(scanned), want) {
+			t.Errorf("corte %d: archivos en disco = %v, want %v", n, entryKeys(scanned), want)
+		}
+	}
+}
+
+func TestUpdateEntryInvalidKeepsOld(t *testing.T) {
+	fs, err := NewFilesystemStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := calendar.NewEntry("u1", "Reunión", time.Date(2025, 11, 20, 9, 0, 0, 0, time.UTC), 30)
+	if err := fs.SaveEntry("u1", entry); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := *entry
+	invalid.Title = ""
+	if err := fs.UpdateEntry("u1", &invalid); err == nil {
+		t.Fatal("UpdateEntry() con título vacío no falló")
+	}
+
+	got, err := fs.GetEntry("u1", entry.ID)
+	if err != nil || got.Title != "Reunión" {
+		t.Errorf("GetEntry() = %v, %v; want la versión anterior", got, err)
+	}
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+// recurrenceHorizon es el máximo a futuro que se expanden eventos recurrentes
+// cuando el filtro no tiene fecha de fin
+const recurrenceHorizon = 366 * 24 * time.Hour
+
+// prepareEntry valida una entrada y normaliza sus fechas antes de guardarla
+func prepareEntry(entry *calendar.Entry) error {
+	if err := entry.Validate(); err != nil {
+		return fmt.Errorf("entrada inválida: %w", err)
+	}
+
+	// Guardar la hora en la zona de origen del evento
+	if entry.TZID != "" {
+		entry.DateTime = entry.DateTime.In(entry.TimeZone())
+	}
+
+	// Los eventos de día completo comienzan a medianoche y terminan en un día
+	if entry.AllDay {
+		entry.DateTime = calendar.StartOfDay(entry.LocalDateTime())
+		if entry.EndDate != nil {
+			end := calendar.StartOfDay(entry.EndDate.In(entry.TimeZone()))
+			entry.EndDate = &end
+		}
+	} else if entry.EndDate != nil && entry.TZID != "" {
+		end := entry.EndDate.In(entry.TimeZone())
+		entry.EndDate = &end
+	}
+
+	return nil
+}
+
+// selectEntries aplica el filtro a las entradas leídas de un backend,
+// expandiendo los eventos recurrentes (con sus overrides) dentro de la
+// ventana del filtro, y las ordena por fecha
+func selectEntries(plain, masters []*calendar.Entry, overrides map[string][]*calendar.Entry, filter *calendar.Filter) []*calendar.Entry {
+	var entries []*calendar.Entry
+
+	for _, entry := range plain {
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	// Expandir eventos recurrentes dentro de la ventana del filtro
+	for _, master := range masters {
+		if filter.From == nil && filter.To == nil {
+			if filter.Matches(master) {
+				entries = append(entries, master)
+			}
+			continue
+		}
+
+		from, to := recurrenceWindow(master, filter)
+		for _, occ := range calendar.ExpandSeries(master, overrides[master.ID], from, to) {
+			if filter.Matches(occ) {
+				entries = append(entries, occ)
+			}
+		}
+	}
+
+	// Ordenar por fecha
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].DateTime.Before(entries[j].DateTime)
+	})
+
+	return entries
+}
+
+// recurrenceWindow calcula la ventana de expansión de un evento recurrente.
+// Si el filtro no tiene To, se limita a recurrenceHorizon desde From.
+func recurrenceWindow(entry *calendar.Entry, filter *calendar.Filter) (time.Time, time.Time) {
+	from := entry.DateTime
+	if filter.From != nil {
+		from = *filter.From
+	}
+
+	to := from.Add(recurrenceHorizon)
+	if filter.To != nil {
+		to = *filter.To
+	}
+
+	return from, to
+}
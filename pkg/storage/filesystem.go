@@ -2,11 +2,13 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
-	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sebasvalencia/clical/pkg/calendar"
@@ -16,6 +18,9 @@ import (
 // FilesystemStorage implementa Storage usando sistema de archivos
 type FilesystemStorage struct {
 	dataDir string
+
+	mu      sync.Mutex
+	indexes map[string]*entryIndex // índices de eventos cargados, por usuario
 }
 
 // NewFilesystemStorage crea un nuevo storage en filesystem
@@ -24,63 +29,214 @@ func NewFilesystemStorage(dataDir string) (*FilesystemStorage, error) {
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return nil, fmt.Errorf("error creando directorio: %w", err)
 	}
+	removeTombstones(dataDir)
 
 	return &FilesystemStorage{
 		dataDir: dataDir,
+		indexes: make(map[string]*entryIndex),
 	}, nil
 }
 
-// SaveEntry guarda una entrada en filesystem
+// SaveEntry guarda una entrada en filesystem.
+// Si la entrada es una ocurrencia (RecurrenceID != nil) se guarda como override
+// junto al evento maestro.
 func (fs *FilesystemStorage) SaveEntry(userID string, entry *calendar.Entry) error {
-	if err := entry.Validate(); err != nil {
-		return fmt.Errorf("entrada inválida: %w", err)
+	if err := prepareEntry(entry); err != nil {
+		return err
+	}
+
+	if entry.IsOccurrence() {
+		return fs.saveOverride(userID, entry)
 	}
 
+	return fs.updateIndex(userID, func(idx *entryIndex) error {
+		filename := entry.GenerateFilename()
+		state, err := fs.writeEntryFiles(userID, entry.DateTime, filename, entry)
+		if err != nil {
+			return err
+		}
+		relPath := relEntryPath(entry.DateTime, filename)
+		idx.put(entry, relPath)
+		idx.Synced[relPath] = state
+		return nil
+	})
+}
+
+// writeEntryFiles escribe los archivos .md y .json de una entrada en el
+// directorio de date y retorna sus hashes, para registrarlos en el índice
+func (fs *FilesystemStorage) writeEntryFiles(userID string, date time.Time, filename string, entry *calendar.Entry) (syncState, error) {
 	// Crear directorio para la fecha
-	dir := getEntryDir(fs.dataDir, userID, entry.DateTime)
+	dir := getEntryDir(fs.dataDir, userID, date)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("error creando directorio: %w", err)
+		return syncState{}, fmt.Errorf("error creando directorio: %w", err)
 	}
 
-	filename := entry.GenerateFilename()
-
 	// Guardar Markdown
-	mdPath := getEntryPath(fs.dataDir, userID, entry.DateTime, filename, ".md")
-	mdContent := entryToMarkdown(entry)
-	if err := os.WriteFile(mdPath, []byte(mdContent), 0644); err != nil {
-		return fmt.Errorf("error escribiendo markdown: %w", err)
+	mdPath := getEntryPath(fs.dataDir, userID, date, filename, ".md")
+	mdContent := []byte(entryToMarkdown(entry))
+	if err := writeFileAtomic(mdPath, mdContent, 0644); err != nil {
+		return syncState{}, fmt.Errorf("error escribiendo markdown: %w", err)
 	}
 
 	// Guardar JSON
-	jsonPath := getEntryPath(fs.dataDir, userID, entry.DateTime, filename, ".json")
+	jsonPath := getEntryPath(fs.dataDir, userID, date, filename, ".json")
 	jsonData, err := json.MarshalIndent(entry, "", "  ")
 	if err != nil {
-		return fmt.Errorf("error serializando JSON: %w", err)
+		return syncState{}, fmt.Errorf("error serializando JSON: %w", err)
+	}
+	if err := writeFileAtomic(jsonPath, jsonData, 0644); err != nil {
+		return syncState{}, fmt.Errorf("error escribiendo JSON: %w", err)
 	}
-	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
-		return fmt.Errorf("error escribiendo JSON: %w", err)
+
+	return syncState{MD: contentHash(mdContent), JSON: contentHash(jsonData)}, nil
+}
+
+// removeEntryFiles elimina los archivos .json y .md de una entrada, dada su
+// ruta relativa en el índice. El JSON va primero: si el proceso se corta en
+// el medio sólo queda un .md huérfano, que nunca se lee.
+func (fs *FilesystemStorage) removeEntryFiles(userID, relPath string) error {
+	jsonPath := fs.indexedPath(userID, relPath)
+	mdPath := strings.TrimSuffix(jsonPath, ".json") + ".md"
+
+	if err := removeFile(jsonPath); err != nil {
+		return fmt.Errorf("error eliminando JSON: %w", err)
+	}
+
+	if err := removeFile(mdPath); err != nil {
+		return fmt.Errorf("error eliminando markdown: %w", err)
 	}
 
 	return nil
 }
 
-// GetEntry obtiene una entrada por ID
-func (fs *FilesystemStorage) GetEntry(userID, entryID string) (*calendar.Entry, error) {
-	// Buscar en todos los directorios de eventos
-	entries, err := fs.ListEntries(userID, calendar.NewFilter())
+// saveOverride guarda un override de ocurrencia junto a su evento maestro
+func (fs *FilesystemStorage) saveOverride(userID string, occ *calendar.Entry) error {
+	master, err := fs.GetEntry(userID, occ.ID)
+	if err != nil {
+		return err
+	}
+
+	if !master.HasOccurrence(*occ.RecurrenceID) {
+		return fmt.Errorf("el evento %s no tiene ocurrencia en %s", occ.ID, occ.RecurrenceID.Format("2006-01-02 15:04"))
+	}
+
+	filename := getOverrideFilename(master.GenerateFilename(), *occ.RecurrenceID)
+	return fs.updateIndex(userID, func(idx *entryIndex) error {
+		state, err := fs.writeEntryFiles(userID, master.DateTime, filename, occ)
+		if err != nil {
+			return err
+		}
+		relPath := relEntryPath(master.DateTime, filename)
+		idx.putOverride(occ.ID, relPath)
+		idx.Synced[relPath] = state
+		return nil
+	})
+}
+
+// ListOverrides retorna los overrides de ocurrencia de un evento recurrente
+func (fs *FilesystemStorage) ListOverrides(userID, entryID string) ([]*calendar.Entry, error) {
+	master, err := fs.GetEntry(userID, entryID)
 	if err != nil {
 		return nil, err
 	}
+	return fs.listOverrides(userID, master)
+}
 
-	for _, entry := range entries {
-		if entry.ID == entryID {
+// listOverrides retorna los overrides de ocurrencia de un evento maestro.
+// Normalmente están junto al maestro, pero se ubican con el índice para
+// encontrar también los que dejó una actualización interrumpida.
+func (fs *FilesystemStorage) listOverrides(userID string, master *calendar.Entry) ([]*calendar.Entry, error) {
+	idx, err := fs.loadIndex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	e, ok := idx.Entries[master.ID]
+	if !ok {
+		return nil, nil
+	}
+
+	var overrides []*calendar.Entry
+	for _, p := range e.Overrides {
+		entry, err := readEntryFile(fs.indexedPath(userID, p))
+		if err != nil {
+			return nil, err
+		}
+		if entry.ID == master.ID && entry.IsOccurrence() {
+			overrides = append(overrides, entry)
+		}
+	}
+
+	return overrides, nil
+}
+
+// readEntryFile lee y parsea el JSON de una entrada
+func readEntryFile(path string) (*calendar.Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo %s: %w", path, err)
+	}
+
+	var entry calendar.Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("error parseando %s: %w", path, err)
+	}
+
+	return &entry, nil
+}
+
+// GetEntry obtiene una entrada por ID, ubicándola con el índice
+func (fs *FilesystemStorage) GetEntry(userID, entryID string) (*calendar.Entry, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		idx, err := fs.loadIndex(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		e, ok := idx.Entries[entryID]
+		if !ok {
+			break
+		}
+
+		entry, err := readEntryFile(fs.indexedPath(userID, e.Path))
+		if err == nil && entry.ID == entryID && !entry.IsOccurrence() {
 			return entry, nil
 		}
+
+		// El archivo cambió fuera de clical: reconstruir el índice y reintentar
+		if _, err := fs.rebuildIndex(userID); err != nil {
+			return nil, err
+		}
 	}
 
 	return nil, fmt.Errorf("entrada no encontrada: %s", entryID)
 }
 
+// GetOccurrence obtiene una ocurrencia de un evento recurrente, aplicando su override si existe
+func (fs *FilesystemStorage) GetOccurrence(userID, entryID string, recurrenceID time.Time) (*calendar.Entry, error) {
+	master, err := fs.GetEntry(userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !master.HasOccurrence(recurrenceID) {
+		return nil, fmt.Errorf("el evento %s no tiene ocurrencia en %s", entryID, recurrenceID.Format("2006-01-02 15:04"))
+	}
+
+	overrides, err := fs.listOverrides(userID, master)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range overrides {
+		if o.RecurrenceID.Equal(recurrenceID) {
+			return o, nil
+		}
+	}
+
+	return master.Occurrence(recurrenceID), nil
+}
+
 // ListEntries lista todas las entradas de un usuario con filtro opcional
 func (fs *FilesystemStorage) ListEntries(userID string, filter *calendar.Filter) ([]*calendar.Entry, error) {
 	if filter == nil {
@@ -96,85 +252,168 @@ func (fs *FilesystemStorage) ListEntries(userID string, filter *calendar.Filter)
 		return entries, nil // Retornar lista vacía si no existe
 	}
 
-	// Recorrer todos los archivos JSON
-	err := filepath.Walk(eventsDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	plain, masters, overrides, err := fs.readCandidates(userID, filter)
+	if errors.Is(err, os.ErrNotExist) {
+		// Un archivo indexado ya no existe: reconstruir el índice y reintentar
+		if _, err = fs.rebuildIndex(userID); err != nil {
+			return nil, err
 		}
+		plain, masters, overrides, err = fs.readCandidates(userID, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		// Solo procesar archivos .json
-		if info.IsDir() || !strings.HasSuffix(path, ".json") {
-			return nil
-		}
+	return selectEntries(plain, masters, overrides, filter), nil
+}
 
-		// Leer y parsear JSON
-		data, err := os.ReadFile(path)
+// readCandidates lee los archivos de las entradas que el índice selecciona para el filtro
+func (fs *FilesystemStorage) readCandidates(userID string, filter *calendar.Filter) (plain, masters []*calendar.Entry, overrides map[string][]*calendar.Entry, err error) {
+	idx, err := fs.loadIndex(userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	plainPaths, masterIDs := idx.candidates(filter)
+	read := func(relPath string) (*calendar.Entry, error) {
+		return readEntryFile(fs.indexedPath(userID, relPath))
+	}
+
+	for _, p := range plainPaths {
+		entry, err := read(p)
 		if err != nil {
-			return fmt.Errorf("error leyendo %s: %w", path, err)
+			return nil, nil, nil, err
 		}
+		plain = append(plain, entry)
+	}
 
-		var entry calendar.Entry
-		if err := json.Unmarshal(data, &entry); err != nil {
-			return fmt.Errorf("error parseando %s: %w", path, err)
+	overrides = make(map[string][]*calendar.Entry)
+	for _, id := range masterIDs {
+		e := idx.Entries[id]
+		master, err := read(e.Path)
+		if err != nil {
+			return nil, nil, nil, err
 		}
-
-		// Aplicar filtro
-		if filter.Matches(&entry) {
-			entries = append(entries, &entry)
+		masters = append(masters, master)
+
+		for _, p := range e.Overrides {
+			o, err := read(p)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			overrides[id] = append(overrides[id], o)
 		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, err
 	}
 
-	// Ordenar por fecha
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].DateTime.Before(entries[j].DateTime)
-	})
-
-	return entries, nil
+	return plain, masters, overrides, nil
 }
 
-// DeleteEntry elimina una entrada
+// DeleteEntry elimina una entrada (y los overrides de ocurrencia si es recurrente)
 func (fs *FilesystemStorage) DeleteEntry(userID, entryID string) error {
-	// Primero obtener la entrada para saber su ubicación
-	entry, err := fs.GetEntry(userID, entryID)
-	if err != nil {
+	// Primero verificar que la entrada existe (GetEntry deja el índice al día)
+	if _, err := fs.GetEntry(userID, entryID); err != nil {
 		return err
 	}
 
-	filename := entry.GenerateFilename()
+	return fs.updateIndex(userID, func(idx *entryIndex) error {
+		e := idx.Entries[entryID]
+		for _, p := range e.Overrides {
+			if err := fs.removeEntryFiles(userID, p); err != nil {
+				return err
+			}
+		}
 
-	// Eliminar archivos .md y .json
-	mdPath := getEntryPath(fs.dataDir, userID, entry.DateTime, filename, ".md")
-	jsonPath := getEntryPath(fs.dataDir, userID, entry.DateTime, filename, ".json")
+		// Eliminar archivos .md y .json
+		if err := fs.removeEntryFiles(userID, e.Path); err != nil {
+			return err
+		}
+		idx.remove(entryID)
+		return nil
+	})
+}
 
-	if err := os.Remove(mdPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("error eliminando markdown: %w", err)
+// UpdateEntry actualiza una entrada existente.
+// Los overrides de un evento recurrente se conservan mientras su ocurrencia siga vigente.
+//
+// La nueva versión se escribe completa antes de eliminar la anterior: si el
+// proceso se corta en el medio quedan las dos, y al reconstruir el índice se
+// conserva la de UpdatedAt más reciente (ver rebuildIndex).
+func (fs *FilesystemStorage) UpdateEntry(userID string, entry *calendar.Entry) error {
+	// Actualizar timestamp
+	entry.UpdatedAt = time.Now()
+
+	if entry.IsOccurrence() {
+		return fs.SaveEntry(userID, entry)
 	}
 
-	if err := os.Remove(jsonPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("error eliminando JSON: %w", err)
+	if err := prepareEntry(entry); err != nil {
+		return err
 	}
 
-	return nil
-}
+	old, err := fs.GetEntry(userID, entry.ID)
+	if err != nil {
+		return err
+	}
 
-// UpdateEntry actualiza una entrada existente
-func (fs *FilesystemStorage) UpdateEntry(userID string, entry *calendar.Entry) error {
-	// Eliminar la entrada antigua
-	if err := fs.DeleteEntry(userID, entry.ID); err != nil {
+	overrides, err := fs.listOverrides(userID, old)
+	if err != nil {
 		return err
 	}
 
-	// Actualizar timestamp
-	entry.UpdatedAt = time.Now()
+	return fs.updateIndex(userID, func(idx *entryIndex) error {
+		oldEntry := idx.Entries[entry.ID]
+		oldOverrides := oldEntry.Overrides
 
-	// Guardar la nueva versión
-	return fs.SaveEntry(userID, entry)
+		// Guardar la nueva versión
+		filename := entry.GenerateFilename()
+		newPath := relEntryPath(entry.DateTime, filename)
+		state, err := fs.writeEntryFiles(userID, entry.DateTime, filename, entry)
+		if err != nil {
+			return err
+		}
+		idx.Synced[newPath] = state
+
+		// Reescribir junto al nuevo maestro los overrides que siguen vigentes
+		var keep []string
+		for _, o := range overrides {
+			if !entry.HasOccurrence(*o.RecurrenceID) {
+				continue
+			}
+			name := getOverrideFilename(filename, *o.RecurrenceID)
+			state, err := fs.writeEntryFiles(userID, entry.DateTime, name, o)
+			if err != nil {
+				return err
+			}
+			relPath := relEntryPath(entry.DateTime, name)
+			keep = append(keep, relPath)
+			idx.Synced[relPath] = state
+		}
+
+		// Recién ahora eliminar lo que quedó de la versión anterior
+		for _, p := range oldOverrides {
+			if containsString(keep, p) {
+				continue
+			}
+			if err := fs.removeEntryFiles(userID, p); err != nil {
+				return err
+			}
+		}
+		if oldEntry.Path != newPath {
+			if err := fs.removeEntryFiles(userID, oldEntry.Path); err != nil {
+				return err
+			}
+		}
+
+		idx.put(entry, newPath)
+		idx.Entries[entry.ID].Overrides = nil
+		for _, p := range keep {
+			idx.putOverride(entry.ID, p)
+		}
+		for _, p := range append(oldOverrides, oldEntry.Path) {
+			idx.touched[path.Dir(p)] = true
+		}
+		return nil
+	})
 }
 
 // SaveUser guarda un usuario
@@ -188,24 +427,7 @@ func (fs *FilesystemStorage) SaveUser(u *user.User) error {
 		return fmt.Errorf("error creando directorio: %w", err)
 	}
 
-	// Guardar Markdown
-	mdPath := getUserPath(fs.dataDir, u.ID, ".md")
-	mdContent := userToMarkdown(u)
-	if err := os.WriteFile(mdPath, []byte(mdContent), 0644); err != nil {
-		return fmt.Errorf("error escribiendo markdown: %w", err)
-	}
-
-	// Guardar JSON
-	jsonPath := getUserPath(fs.dataDir, u.ID, ".json")
-	jsonData, err := json.MarshalIndent(u, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error serializando JSON: %w", err)
-	}
-	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
-		return fmt.Errorf("error escribiendo JSON: %w", err)
-	}
-
-	return nil
+	return writeUserFiles(userDir, u)
 }
 
 // GetUser obtiene un usuario por ID
@@ -259,10 +481,54 @@ func (fs *FilesystemStorage) ListUsers() ([]*user.User, error) {
 	return users, nil
 }
 
-// DeleteUser elimina un usuario y todos sus datos
+// DeleteUser elimina un usuario y todos sus datos, salvo el historial y la
+// papelera (wrapperSubdirs), que eliminan los wrappers correspondientes
 func (fs *FilesystemStorage) DeleteUser(userID string) error {
+	fs.invalidateIndex(userID)
 	userDir := getUserDir(fs.dataDir, userID)
-	return os.RemoveAll(userDir)
+	return removeAllExcept(userDir, wrapperSubdirs)
+}
+
+// removeAllExcept elimina path y todo su contenido salvo las rutas keep
+// (relativas a path). Los directorios que contienen una ruta conservada solo
+// se eliminan si quedaron vacíos.
+func removeAllExcept(path string, keep []string) error {
+	if len(keep) == 0 {
+		return os.RemoveAll(path)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, e := range entries {
+		var inner []string
+		kept := false
+		for _, k := range keep {
+			first, rest, nested := strings.Cut(filepath.ToSlash(k), "/")
+			if first != e.Name() {
+				continue
+			}
+			if !nested {
+				kept = true
+				break
+			}
+			inner = append(inner, rest)
+		}
+		if kept {
+			continue
+		}
+		if err := removeAllExcept(filepath.Join(path, e.Name()), inner); err != nil {
+			return err
+		}
+	}
+
+	os.Remove(path) // solo si quedó vacío
+	return nil
 }
 
 // GetReportState obtiene el estado de reportes
@@ -302,7 +568,7 @@ func (fs *FilesystemStorage) SaveReportState(userID string, state *ReportState)
 		return fmt.Errorf("error serializando estado: %w", err)
 	}
 
-	if err := os.WriteFile(statePath, jsonData, 0644); err != nil {
+	if err := writeFileAtomic(statePath, jsonData, 0644); err != nil {
 		return fmt.Errorf("error escribiendo estado: %w", err)
 	}
 
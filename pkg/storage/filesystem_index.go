@@ -0,0 +1,614 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+// indexVersion se incrementa cuando cambia el formato del índice; un índice
+// de otra versión se reconstruye
+const indexVersion = 1
+
+// indexFilename es el índice de eventos, dentro de .state/
+const indexFilename = "entries-index.json"
+
+// entryIndex es el índice de eventos de un usuario en el backend de
+// filesystem. Permite ubicar una entrada por ID sin recorrer el árbol de
+// eventos y abrir solo los directorios year/month/day del rango pedido.
+//
+// Las rutas son relativas a events/, con "/" y sin extensión.
+type entryIndex struct {
+	Version int                    `json:"version"`
+	Entries map[string]*indexEntry `json:"entries"` // ID -> entrada
+	Days    map[string][]string    `json:"days"`    // "2025/11/21" -> IDs que empiezan ese día
+	Tags    map[string][]string    `json:"tags"`    // tag -> IDs
+	// Dirs guarda el mtime de cada directorio de events/. Si alguno cambia
+	// (archivos creados o borrados a mano) el índice se reconstruye.
+	Dirs map[string]int64 `json:"dirs"`
+	// Synced guarda, por ruta, el hash del .md y del .json la última vez que
+	// clical los escribió o sincronizó; `clical sync` lo usa para saber qué
+	// lado se editó a mano (ver SyncMarkdown)
+	Synced map[string]syncState `json:"synced,omitempty"`
+
+	// Derivados de Entries, no se guardan
+	masters map[string]bool // eventos recurrentes
+	long    map[string]bool // eventos de más de un día
+	touched map[string]bool // directorios modificados desde la última vez que se guardó
+	file    os.FileInfo     // archivo del índice cuando se leyó o guardó (ver indexCurrent)
+}
+
+// indexEntry es una entrada del índice
+type indexEntry struct {
+	Path      string   `json:"path"`
+	Start     int64    `json:"start"` // unix
+	End       int64    `json:"end"`   // unix
+	Recurring bool     `json:"recurring,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Overrides []string `json:"overrides,omitempty"` // rutas de los overrides de ocurrencia
+}
+
+// syncState son los hashes del .md y del .json de una entrada
+type syncState struct {
+	MD   string `json:"md"`
+	JSON string `json:"json"`
+}
+
+// newEntryIndex crea un índice vacío
+func newEntryIndex() *entryIndex {
+	idx := &entryIndex{
+		Version: indexVersion,
+		Entries: make(map[string]*indexEntry),
+		Days:    make(map[string][]string),
+		Tags:    make(map[string][]string),
+		Dirs:    make(map[string]int64),
+		Synced:  make(map[string]syncState),
+	}
+	idx.derive()
+	return idx
+}
+
+// derive calcula los conjuntos que no se guardan en disco
+func (idx *entryIndex) derive() {
+	idx.masters = make(map[string]bool)
+	idx.long = make(map[string]bool)
+	idx.touched = make(map[string]bool)
+	if idx.Synced == nil {
+		idx.Synced = make(map[string]syncState)
+	}
+	for id, e := range idx.Entries {
+		idx.classify(id, e)
+	}
+}
+
+// classify agrega la entrada a los conjuntos derivados que correspondan
+func (idx *entryIndex) classify(id string, e *indexEntry) {
+	if e.Recurring {
+		idx.masters[id] = true
+	} else if e.End-e.Start > int64(24*time.Hour/time.Second) {
+		idx.long[id] = true
+	}
+}
+
+// put agrega (o reemplaza) un evento simple o maestro
+func (idx *entryIndex) put(entry *calendar.Entry, relPath string) {
+	e := &indexEntry{
+		Path:      relPath,
+		Start:     entry.DateTime.Unix(),
+		End:       entry.EndTime().Unix(),
+		Recurring: entry.IsRecurring(),
+		Tags:      append([]string(nil), entry.Tags...),
+	}
+
+	// Los overrides se conservan mientras el maestro siga en el mismo archivo
+	if old, ok := idx.Entries[entry.ID]; ok && old.Path == relPath {
+		e.Overrides = old.Overrides
+	}
+	idx.remove(entry.ID)
+
+	idx.Entries[entry.ID] = e
+	day := path.Dir(relPath)
+	idx.Days[day] = append(idx.Days[day], entry.ID)
+	for _, tag := range e.Tags {
+		idx.Tags[tag] = append(idx.Tags[tag], entry.ID)
+	}
+	idx.classify(entry.ID, e)
+	idx.touched[day] = true
+}
+
+// putOverride registra un override de ocurrencia de un evento maestro
+func (idx *entryIndex) putOverride(entryID, relPath string) {
+	e, ok := idx.Entries[entryID]
+	if !ok {
+		return
+	}
+	for _, p := range e.Overrides {
+		if p == relPath {
+			return
+		}
+	}
+	e.Overrides = append(e.Overrides, relPath)
+	idx.touched[path.Dir(relPath)] = true
+}
+
+// pruneSynced descarta los hashes de archivos que ya no están en el índice
+func (idx *entryIndex) pruneSynced() {
+	known := make(map[string]bool, len(idx.Entries))
+	for _, e := range idx.Entries {
+		known[e.Path] = true
+		for _, p := range e.Overrides {
+			known[p] = true
+		}
+	}
+	for p := range idx.Synced {
+		if !known[p] {
+			delete(idx.Synced, p)
+		}
+	}
+}
+
+// remove quita una entrada (y sus overrides) del índice
+func (idx *entryIndex) remove(entryID string) {
+	e, ok := idx.Entries[entryID]
+	if !ok {
+		return
+	}
+
+	day := path.Dir(e.Path)
+	idx.Days[day] = removeString(idx.Days[day], entryID)
+	if len(idx.Days[day]) == 0 {
+		delete(idx.Days, day)
+	}
+	for _, tag := range e.Tags {
+		idx.Tags[tag] = removeString(idx.Tags[tag], entryID)
+		if len(idx.Tags[tag]) == 0 {
+			delete(idx.Tags, tag)
+		}
+	}
+
+	delete(idx.Entries, entryID)
+	delete(idx.masters, entryID)
+	delete(idx.long, entryID)
+	idx.touched[day] = true
+}
+
+// candidates retorna las rutas de las entradas que pueden cumplir el filtro:
+// eventos simples del rango (y con los tags pedidos) y maestros recurrentes
+// que empiezan antes del fin del rango. Es un superconjunto: el filtro
+// completo se aplica después de leer los archivos.
+func (idx *entryIndex) candidates(filter *calendar.Filter) (plain, masters []string) {
+	var from, to int64 = -1 << 63, 1<<63 - 1
+	if filter.From != nil {
+		from = filter.From.Unix()
+	}
+	if filter.To != nil {
+		to = filter.To.Unix()
+	}
+
+	matches := func(id string) bool {
+		e := idx.Entries[id]
+		if e.Start > to || e.End < from {
+			return false
+		}
+		for _, tag := range filter.Tags {
+			if !containsString(e.Tags, tag) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if len(filter.Tags) > 0 {
+		// Con tags, partir de la lista de IDs más corta entre los tags pedidos
+		for _, id := range idx.shortestPosting(filter.Tags) {
+			if !idx.masters[id] && matches(id) {
+				plain = append(plain, idx.Entries[id].Path)
+			}
+		}
+	} else {
+		for _, day := range idx.days(filter) {
+			for _, id := range idx.Days[day] {
+				if !idx.masters[id] && !idx.long[id] && matches(id) {
+					plain = append(plain, idx.Entries[id].Path)
+				}
+			}
+		}
+		for id := range idx.long {
+			if matches(id) {
+				plain = append(plain, idx.Entries[id].Path)
+			}
+		}
+	}
+	for id := range idx.masters {
+		if idx.Entries[id].Start <= to {
+			masters = append(masters, id)
+		}
+	}
+
+	// Mismo orden que al recorrer el árbol de directorios
+	sort.Slice(plain, func(i, j int) bool { return plain[i]+".json" < plain[j]+".json" })
+	sort.Slice(masters, func(i, j int) bool {
+		return idx.Entries[masters[i]].Path+".json" < idx.Entries[masters[j]].Path+".json"
+	})
+
+	return plain, masters
+}
+
+// shortestPosting retorna la lista de IDs más corta entre los tags dados
+func (idx *entryIndex) shortestPosting(tags []string) []string {
+	var shortest []string
+	for i, tag := range tags {
+		posting := idx.Tags[tag]
+		if i == 0 || len(posting) < len(shortest) {
+			shortest = posting
+		}
+	}
+	return shortest
+}
+
+// days retorna los buckets de día a revisar para el rango del filtro. Los
+// directorios usan la fecha local de cada evento, por lo que se agrega un
+// margen de zona horaria; los eventos de más de un día se revisan aparte.
+func (idx *entryIndex) days(filter *calendar.Filter) []string {
+	first, last := "", "9999/99/99"
+	if filter.From != nil {
+		first = filter.From.UTC().AddDate(0, 0, -2).Format("2006/01/02")
+	}
+	if filter.To != nil {
+		last = filter.To.UTC().AddDate(0, 0, 1).Format("2006/01/02")
+	}
+
+	// Rango acotado y corto: generar las claves día por día
+	if filter.From != nil && filter.To != nil {
+		start := calendar.StartOfDay(filter.From.UTC()).AddDate(0, 0, -2)
+		end := filter.To.UTC().AddDate(0, 0, 1)
+		if end.Sub(start) < time.Duration(len(idx.Days))*24*time.Hour {
+			var days []string
+			for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+				if key := d.Format("2006/01/02"); idx.Days[key] != nil {
+					days = append(days, key)
+				}
+			}
+			return days
+		}
+	}
+
+	var days []string
+	for day := range idx.Days {
+		if day >= first && day <= last {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// eventsDir retorna el directorio de eventos de un usuario
+func (fs *FilesystemStorage) eventsDir(userID string) string {
+	return filepath.Join(getUserDir(fs.dataDir, userID), "events")
+}
+
+// relEntryPath retorna la ruta de una entrada relativa a events/, como se guarda en el índice
+func relEntryPath(date time.Time, filename string) string {
+	year, month, day := getYearMonthDayFromDate(date)
+	return path.Join(year, month, day, filename)
+}
+
+// indexedPath convierte una ruta del índice en la ruta del archivo .json
+func (fs *FilesystemStorage) indexedPath(userID, relPath string) string {
+	return filepath.Join(fs.eventsDir(userID), filepath.FromSlash(relPath)) + ".json"
+}
+
+// loadIndex retorna el índice de eventos del usuario. El índice en memoria se
+// usa mientras el archivo del índice siga siendo el mismo (ver indexCurrent);
+// si otro proceso lo reemplazó se vuelve a leer de disco. Al leerlo de disco
+// (una vez por proceso, salvo cambios de otro proceso) se valida contra los
+// directorios de eventos, y si no existe, es de otra versión o algún
+// directorio cambió desde que se guardó (una edición a mano) se reconstruye.
+//
+// Las ediciones a mano que no cambian ningún directorio (reescribir un .json
+// en su lugar) no se detectan aquí: GetEntry lee siempre el archivo y
+// `clical sync` actualiza el índice de los .json que cambiaron.
+func (fs *FilesystemStorage) loadIndex(userID string) (*entryIndex, error) {
+	fs.mu.Lock()
+	idx, ok := fs.indexes[userID]
+	fs.mu.Unlock()
+	if ok && fs.indexCurrent(userID, idx) {
+		return idx, nil
+	}
+
+	idx, err := fs.readIndex(userID)
+	if err != nil || !fs.indexFresh(userID, idx) {
+		return fs.rebuildIndex(userID)
+	}
+
+	fs.cacheIndex(userID, idx)
+	return idx, nil
+}
+
+// indexCurrent indica si el archivo del índice es el mismo que se leyó o
+// guardó en idx. Toda escritura de eventos de clical termina guardando el
+// índice con writeFileAtomic, que lo reemplaza por un archivo nuevo, así que
+// el archivo sirve de marca de generación: alcanza con un stat por consulta.
+func (fs *FilesystemStorage) indexCurrent(userID string, idx *entryIndex) bool {
+	if idx.file == nil {
+		return false
+	}
+	info, err := os.Stat(getStatePath(fs.dataDir, userID, indexFilename))
+	return err == nil && os.SameFile(info, idx.file) && info.ModTime().Equal(idx.file.ModTime()) && info.Size() == idx.file.Size()
+}
+
+// readIndex lee el índice guardado
+func (fs *FilesystemStorage) readIndex(userID string) (*entryIndex, error) {
+	f, err := os.Open(getStatePath(fs.dataDir, userID, indexFilename))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var idx entryIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, err
+	}
+	if idx.Version != indexVersion || idx.Entries == nil || idx.Days == nil || idx.Tags == nil || idx.Dirs == nil {
+		return nil, fmt.Errorf("índice incompatible")
+	}
+
+	idx.derive()
+	idx.file = info
+	return &idx, nil
+}
+
+// indexFresh verifica que ningún directorio de eventos haya cambiado desde
+// que se guardó el índice. Un directorio nuevo cambia el mtime de su padre,
+// por lo que alcanza con revisar los directorios conocidos.
+func (fs *FilesystemStorage) indexFresh(userID string, idx *entryIndex) bool {
+	eventsDir := fs.eventsDir(userID)
+	if _, err := os.Stat(eventsDir); os.IsNotExist(err) {
+		return len(idx.Entries) == 0
+	}
+	if _, ok := idx.Dirs["."]; !ok {
+		return false
+	}
+
+	for dir, mtime := range idx.Dirs {
+		info, err := os.Stat(filepath.Join(eventsDir, filepath.FromSlash(dir)))
+		if err != nil || info.ModTime().UnixNano() != mtime {
+			return false
+		}
+	}
+	return true
+}
+
+// rebuildIndex reconstruye el índice recorriendo todos los archivos de eventos
+func (fs *FilesystemStorage) rebuildIndex(userID string) (*entryIndex, error) {
+	idx := newEntryIndex()
+	eventsDir := fs.eventsDir(userID)
+
+	// Los hashes de sincronización no se pueden recalcular: se conservan los
+	// del índice anterior
+	if old, err := fs.readIndex(userID); err == nil {
+		idx.Synced = old.Synced
+	}
+
+	if _, err := os.Stat(eventsDir); os.IsNotExist(err) {
+		fs.cacheIndex(userID, idx)
+		return idx, nil
+	}
+
+	// Un mismo ID en dos archivos es lo que deja una actualización
+	// interrumpida (la versión nueva se escribe antes de borrar la anterior):
+	// se conserva la de UpdatedAt más reciente y la otra se elimina
+	updated := make(map[string]time.Time)
+	var stale []string
+
+	type override struct {
+		path         string
+		recurrenceID time.Time
+	}
+	overrides := make(map[string][]override)
+
+	err := filepath.Walk(eventsDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(eventsDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			idx.Dirs[rel] = info.ModTime().UnixNano()
+			return nil
+		}
+		if !strings.HasSuffix(p, ".json") {
+			return nil
+		}
+
+		entry, err := readEntryFile(p)
+		if err != nil {
+			return err
+		}
+
+		rel = strings.TrimSuffix(rel, ".json")
+		if entry.IsOccurrence() {
+			overrides[entry.ID] = append(overrides[entry.ID], override{rel, *entry.RecurrenceID})
+			return nil
+		}
+
+		if prev, ok := updated[entry.ID]; ok {
+			switch {
+			case entry.UpdatedAt.After(prev):
+				stale = append(stale, idx.Entries[entry.ID].Path)
+			case entry.UpdatedAt.Before(prev):
+				stale = append(stale, rel)
+				return nil
+			default:
+				return nil // copia manual: se indexa la primera
+			}
+		}
+		updated[entry.ID] = entry.UpdatedAt
+		idx.put(entry, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reconstruyendo índice: %w", err)
+	}
+
+	for id, list := range overrides {
+		e, ok := idx.Entries[id]
+		if !ok {
+			continue
+		}
+
+		// Si una ocurrencia quedó en dos archivos se conserva el que está junto al maestro
+		chosen := make(map[string]string)
+		for _, o := range list {
+			key := o.recurrenceID.UTC().Format(time.RFC3339)
+			cur, ok := chosen[key]
+			switch {
+			case !ok:
+				chosen[key] = o.path
+			case strings.HasPrefix(o.path, e.Path+".") && !strings.HasPrefix(cur, e.Path+"."):
+				stale = append(stale, cur)
+				chosen[key] = o.path
+			default:
+				stale = append(stale, o.path)
+			}
+		}
+		for _, o := range list {
+			if chosen[o.recurrenceID.UTC().Format(time.RFC3339)] == o.path {
+				idx.putOverride(id, o.path)
+			}
+		}
+	}
+
+	idx.touched = make(map[string]bool)
+	for _, p := range stale {
+		if err := fs.removeEntryFiles(userID, p); err != nil {
+			return nil, err
+		}
+		idx.touched[path.Dir(p)] = true
+	}
+	fs.statTouched(userID, idx)
+
+	if err := fs.saveIndex(userID, idx); err != nil {
+		return nil, err
+	}
+	fs.cacheIndex(userID, idx)
+	return idx, nil
+}
+
+// updateIndex aplica fn (que escribe o borra archivos de eventos y actualiza
+// el índice) y guarda el índice con los mtime nuevos de los directorios
+// tocados. Si fn falla el índice en memoria se descarta.
+func (fs *FilesystemStorage) updateIndex(userID string, fn func(idx *entryIndex) error) error {
+	idx, err := fs.loadIndex(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(idx); err != nil {
+		fs.invalidateIndex(userID)
+		return err
+	}
+
+	fs.statTouched(userID, idx)
+	return fs.saveIndex(userID, idx)
+}
+
+// statTouched registra el mtime de los directorios tocados y de sus padres
+// (que cambian si se creó un directorio)
+func (fs *FilesystemStorage) statTouched(userID string, idx *entryIndex) {
+	eventsDir := fs.eventsDir(userID)
+	for dir := range idx.touched {
+		for d := dir; ; d = path.Dir(d) {
+			info, err := os.Stat(filepath.Join(eventsDir, filepath.FromSlash(d)))
+			if err != nil {
+				delete(idx.Dirs, d)
+			} else {
+				idx.Dirs[d] = info.ModTime().UnixNano()
+			}
+			if d == "." {
+				break
+			}
+		}
+	}
+	idx.touched = make(map[string]bool)
+}
+
+// saveIndex guarda el índice en .state/
+func (fs *FilesystemStorage) saveIndex(userID string, idx *entryIndex) error {
+	if err := os.MkdirAll(getStateDir(fs.dataDir, userID), 0755); err != nil {
+		return fmt.Errorf("error creando directorio: %w", err)
+	}
+
+	idx.pruneSynced()
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("error serializando índice: %w", err)
+	}
+	indexPath := getStatePath(fs.dataDir, userID, indexFilename)
+	if err := writeFileAtomic(indexPath, data, 0644); err != nil {
+		return fmt.Errorf("error escribiendo índice: %w", err)
+	}
+	idx.file, _ = os.Stat(indexPath)
+	return nil
+}
+
+// cacheIndex guarda el índice en memoria para el resto del proceso
+func (fs *FilesystemStorage) cacheIndex(userID string, idx *entryIndex) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.indexes == nil {
+		fs.indexes = make(map[string]*entryIndex)
+	}
+	fs.indexes[userID] = idx
+}
+
+// invalidateIndex descarta el índice en memoria; se vuelve a leer (o
+// reconstruir) en el próximo uso
+func (fs *FilesystemStorage) invalidateIndex(userID string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	delete(fs.indexes, userID)
+}
+
+// removeString retorna s sin las apariciones de v
+func removeString(s []string, v string) []string {
+	result := s[:0]
+	for _, x := range s {
+		if x != v {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// containsString indica si s contiene v
+func containsString(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
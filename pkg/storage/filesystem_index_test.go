@@ -0,0 +1,354 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+// scanEntries lista entradas recorriendo todo el árbol de eventos, sin
+// índice (el comportamiento anterior de ListEntries). Sirve de referencia.
+func scanEntries(tb testing.TB, fs *FilesystemStorage, userID string, filter *calendar.Filter) []*calendar.Entry {
+	var plain, masters []*calendar.Entry
+	overrides := make(map[string][]*calendar.Entry)
+
+	err := filepath.Walk(fs.eventsDir(userID), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		entry, err := readEntryFile(path)
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.IsOccurrence():
+			overrides[entry.ID] = append(overrides[entry.ID], entry)
+		case entry.IsRecurring():
+			masters = append(masters, entry)
+		default:
+			plain = append(plain, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		tb.Fatalf("scan: %v", err)
+	}
+
+	return selectEntries(plain, masters, overrides, filter)
+}
+
+func entryKeys(entries []*calendar.Entry) []string {
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.ID + "@" + e.DateTime.UTC().Format(time.RFC3339)
+	}
+	return keys
+}
+
+func TestFilesystemIndex(t *testing.T) {
+	dir := t.TempDir()
+	fs, err := NewFilesystemStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	day := time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)
+	at := func(d, h int) time.Time { return day.AddDate(0, 0, d).Add(time.Duration(h) * time.Hour) }
+
+	standup := calendar.NewEntry("u1", "Stand-up", at(0, 9), 15)
+	standup.Tags = []string{"work", "team"}
+	late := calendar.NewEntry("u1", "Cena", time.Date(2025, 11, 23, 23, 30, 0, 0, madrid), 60)
+	late.TZID = "Europe/Madrid"
+	trip := calendar.NewEntry("u1", "Viaje", at(-10, 8), 0)
+	tripEnd := at(5, 20)
+	trip.EndDate = &tripEnd
+	holiday := &calendar.Entry{ID: calendar.GenerateID(), UserID: "u1", Title: "Feriado", DateTime: at(1, 0), AllDay: true}
+	weekly := calendar.NewEntry("u1", "Weekly", at(-30, 10), 30)
+	weekly.RRule, _ = calendar.ParseRecurrenceRule("FREQ=WEEKLY")
+	weekly.Tags = []string{"work"}
+	old := calendar.NewEntry("u1", "Old", at(-400, 10), 30)
+
+	for _, e := range []*calendar.Entry{standup, late, trip, holiday, weekly, old} {
+		if err := fs.SaveEntry("u1", e); err != nil {
+			t.Fatalf("SaveEntry(%s): %v", e.Title, err)
+		}
+	}
+
+	// Override de una ocurrencia del evento semanal
+	occ := weekly.Occurrence(at(-2, 10))
+	occ.Title = "Weekly (moved)"
+	occ.DateTime = at(-2, 15)
+	occ.Tags = []string{"work", "team"}
+	if err := fs.SaveEntry("u1", occ); err != nil {
+		t.Fatalf("SaveEntry(override): %v", err)
+	}
+
+	filters := map[string]*calendar.Filter{
+		"all":           calendar.NewFilter(),
+		"week":          calendar.NewFilter().WithDateRange(at(-3, 0), at(4, 0)),
+		"one day":       calendar.NewFilter().WithDateRange(at(0, 0), at(1, 0)),
+		"late tz":       calendar.NewFilter().WithDateRange(at(3, 20), at(4, 0)),
+		"starts within": calendar.NewFilter().WithDateRange(at(-3, 0), at(4, 0)).WithRangeMode(calendar.RangeStartsWithin),
+		"tags":          calendar.NewFilter().WithDateRange(at(-3, 0), at(4, 0)).WithTags("team"),
+		"tags no range": calendar.NewFilter().WithTags("work"),
+		"past":          calendar.NewFilter().WithDateRange(at(-500, 0), at(-300, 0)),
+		"open end":      {From: ptr(at(2, 0))},
+		"open start":    {To: ptr(at(-5, 0))},
+	}
+
+	check := func(t *testing.T, fs *FilesystemStorage) {
+		for name, filter := range filters {
+			got, err := fs.ListEntries("u1", filter)
+			if err != nil {
+				t.Fatalf("%s: ListEntries: %v", name, err)
+			}
+			want := scanEntries(t, fs, "u1", filter)
+			if !reflect.DeepEqual(entryKeys(got), entryKeys(want)) {
+				t.Errorf("%s: got %v, want %v", name, entryKeys(got), entryKeys(want))
+			}
+		}
+	}
+
+	t.Run("incremental", func(t *testing.T) { check(t, fs) })
+
+	t.Run("reloaded", func(t *testing.T) {
+		reloaded, _ := NewFilesystemStorage(dir)
+		check(t, reloaded)
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		standup.DateTime = at(2, 11)
+		if err := fs.UpdateEntry("u1", standup); err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.DeleteEntry("u1", old.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fs.GetEntry("u1", old.ID); err == nil {
+			t.Error("Expected deleted entry not to be found")
+		}
+		got, err := fs.GetEntry("u1", standup.ID)
+		if err != nil || !got.DateTime.Equal(at(2, 11)) {
+			t.Errorf("GetEntry after update = %v, %v", got, err)
+		}
+		check(t, fs)
+
+		reloaded, _ := NewFilesystemStorage(dir)
+		check(t, reloaded)
+	})
+
+	t.Run("manual changes rebuild the index", func(t *testing.T) {
+		// Agregar un evento copiando archivos a mano, en un día nuevo
+		manual := calendar.NewEntry("u1", "Manual", at(1, 12), 30)
+		manual.Tags = []string{"team"}
+		other, _ := NewFilesystemStorage(dir)
+		if _, err := other.writeEntryFiles("u1", manual.DateTime, manual.GenerateFilename(), manual); err != nil {
+			t.Fatal(err)
+		}
+
+		// Borrar otro a mano
+		if err := os.Remove(fs.indexedPath("u1", relEntryPath(holiday.DateTime, holiday.GenerateFilename()))); err != nil {
+			t.Fatal(err)
+		}
+
+		reloaded, _ := NewFilesystemStorage(dir)
+		if _, err := reloaded.GetEntry("u1", manual.ID); err != nil {
+			t.Errorf("Expected manually added entry to be found: %v", err)
+		}
+		if _, err := reloaded.GetEntry("u1", holiday.ID); err == nil {
+			t.Error("Expected manually removed entry not to be found")
+		}
+		check(t, reloaded)
+
+		// El índice que fs tiene en memoria también detecta los cambios
+		check(t, fs)
+	})
+
+	t.Run("sync reindexes json edited in place", func(t *testing.T) {
+		moved := calendar.NewEntry("u1", "Movido", at(0, 14), 30)
+		tagged := calendar.NewEntry("u1", "Etiquetado", at(0, 16), 30)
+		for _, e := range []*calendar.Entry{moved, tagged} {
+			if err := fs.SaveEntry("u1", e); err != nil {
+				t.Fatal(err)
+			}
+		}
+		idx, err := fs.loadIndex("u1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Cambiar la fecha de uno y los tags del otro reescribiendo el .json
+		// en su lugar: ningún directorio cambia, así que el índice solo se
+		// actualiza con sync
+		moved.DateTime = at(-20, 14)
+		tagged.Tags = []string{"edited"}
+		for _, e := range []*calendar.Entry{moved, tagged} {
+			jsonPath := fs.indexedPath("u1", idx.Entries[e.ID].Path)
+			dirInfo, err := os.Stat(filepath.Dir(jsonPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, _ := json.Marshal(e)
+			if err := os.WriteFile(jsonPath, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(filepath.Dir(jsonPath), dirInfo.ModTime(), dirInfo.ModTime()); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if _, err := fs.SyncMarkdown("u1", SyncOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range []*FilesystemStorage{fs, mustFilesystemStorage(t, dir)} {
+			got, err := s.ListEntries("u1", calendar.NewFilter().WithDateRange(at(-21, 0), at(-19, 0)))
+			if err != nil || len(got) != 1 || got[0].ID != moved.ID {
+				t.Errorf("ListEntries(fecha nueva) after sync = %v, %v", entryKeys(got), err)
+			}
+			got, err = s.ListEntries("u1", calendar.NewFilter().WithTags("edited"))
+			if err != nil || len(got) != 1 || got[0].ID != tagged.ID {
+				t.Errorf("ListEntries(tag nuevo) after sync = %v, %v", entryKeys(got), err)
+			}
+			check(t, s)
+		}
+	})
+}
+
+func ptr(t time.Time) *time.Time { return &t }
+
+func mustFilesystemStorage(t *testing.T, dir string) *FilesystemStorage {
+	t.Helper()
+	fs, err := NewFilesystemStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+// benchEntries es la cantidad de eventos del calendario de los benchmarks
+const benchEntries = 50000
+
+var (
+	benchOnce sync.Once
+	benchDir  string
+	benchIDs  []string
+)
+
+// benchStorage genera (una vez por proceso) un calendario con benchEntries
+// eventos repartidos en unos 4 años
+func benchStorage(b *testing.B) string {
+	benchOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "clical-bench-")
+		if err != nil {
+			b.Fatal(err)
+		}
+		fs, err := NewFilesystemStorage(dir)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		start := time.Date(2022, 1, 1, 8, 0, 0, 0, time.UTC)
+		tags := []string{"work", "personal", "health", "family"}
+		for i := 0; i < benchEntries; i++ {
+			e := calendar.NewEntry("u1", fmt.Sprintf("Event %d", i), start.Add(time.Duration(i)*41*time.Minute), 30)
+			e.Tags = []string{tags[i%len(tags)]}
+			if _, err := fs.writeEntryFiles("u1", e.DateTime, e.GenerateFilename(), e); err != nil {
+				b.Fatal(err)
+			}
+			if i%1000 == 0 {
+				benchIDs = append(benchIDs, e.ID)
+			}
+		}
+
+		// Construir el índice una vez, como en el primer uso
+		if _, err := fs.rebuildIndex("u1"); err != nil {
+			b.Fatal(err)
+		}
+		benchDir = dir
+	})
+	if benchDir == "" {
+		b.Fatal("fixture de benchmark no disponible")
+	}
+	return benchDir
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if benchDir != "" {
+		os.RemoveAll(benchDir)
+	}
+	os.Exit(code)
+}
+
+// Cada iteración usa un storage nuevo, como una invocación de clical: el
+// índice se lee de disco y se valida en cada una.
+
+func BenchmarkGetEntry(b *testing.B) {
+	dir := benchStorage(b)
+
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			fs, _ := NewFilesystemStorage(dir)
+			id := benchIDs[i%len(benchIDs)]
+			for _, e := range scanEntries(b, fs, "u1", calendar.NewFilter()) {
+				if e.ID == id {
+					break
+				}
+			}
+		}
+	})
+
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			fs, _ := NewFilesystemStorage(dir)
+			if _, err := fs.GetEntry("u1", benchIDs[i%len(benchIDs)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// Dentro de un mismo proceso el índice en memoria se reutiliza
+	b.Run("index cached", func(b *testing.B) {
+		fs, _ := NewFilesystemStorage(dir)
+		for i := 0; i < b.N; i++ {
+			if _, err := fs.GetEntry("u1", benchIDs[i%len(benchIDs)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkListEntriesWeek(b *testing.B) {
+	dir := benchStorage(b)
+	from := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	filter := calendar.NewFilter().WithDateRange(from, from.AddDate(0, 0, 7))
+
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			fs, _ := NewFilesystemStorage(dir)
+			scanEntries(b, fs, "u1", filter)
+		}
+	})
+
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			fs, _ := NewFilesystemStorage(dir)
+			if _, err := fs.ListEntries("u1", filter); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,285 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+// Resolución de conflictos de SyncMarkdown
+const (
+	SyncPreferMarkdown = "md"   // gana la versión Markdown
+	SyncPreferJSON     = "json" // gana la versión JSON
+)
+
+// MarkdownSyncer lo implementan los backends que guardan cada evento también
+// como Markdown editable a mano
+type MarkdownSyncer interface {
+	// SyncMarkdown reconcilia los .md editados a mano con sus .json
+	SyncMarkdown(userID string, opts SyncOptions) (*SyncResult, error)
+}
+
+// SyncOptions configura una sincronización de Markdown
+type SyncOptions struct {
+	DryRun bool   // solo reportar, sin escribir nada
+	Prefer string // qué lado gana en un conflicto: "" (reportarlo), "md" o "json"
+}
+
+// SyncItem es un archivo de evento procesado por SyncMarkdown
+type SyncItem struct {
+	Path  string // ruta del .md relativa a events/
+	ID    string
+	Entry *calendar.Entry // el evento según su .json, antes de sincronizar
+	Err   error           // en Invalid: por qué no se pudo leer el .md
+}
+
+// SyncResult es el resultado de SyncMarkdown
+type SyncResult struct {
+	Imported    []SyncItem // .md editados cuyos cambios se aplicaron al evento
+	Regenerated []SyncItem // .md reescritos desde el .json (que cambió o no tenía .md)
+	Conflicts   []SyncItem // ambos lados cambiaron; no se tocaron
+	Invalid     []SyncItem // .md editados que no se pudieron parsear; no se tocaron
+	Unchanged   int
+}
+
+// contentHash retorna el hash de un archivo para detectar ediciones
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// syncFile es un par .md/.json de un evento leído para sincronizar
+type syncFile struct {
+	rel      string // ruta relativa a events/, sin extensión
+	entry    *calendar.Entry
+	md       []byte
+	state    syncState // hashes actuales
+	rendered string    // Markdown que corresponde al .json
+}
+
+// SyncMarkdown reconcilia los .md de los eventos con sus .json. Si un .md no
+// coincide con lo que se genera desde su .json, se decide qué lado cambió
+// comparando sus hashes con los que el índice guardó la última vez que
+// clical escribió el par (o, si no los tiene, cuál tiene el mtime más nuevo):
+//
+//   - cambió el .md: se parsea y se guarda el evento (se reescriben ambos)
+//   - cambió el .json: se regenera el .md
+//   - cambiaron los dos: es un conflicto y se reporta sin tocar nada, salvo
+//     que opts.Prefer indique qué lado gana
+func (fs *FilesystemStorage) SyncMarkdown(userID string, opts SyncOptions) (*SyncResult, error) {
+	switch opts.Prefer {
+	case "", SyncPreferMarkdown, SyncPreferJSON:
+	default:
+		return nil, fmt.Errorf("resolución de conflictos inválida: %s (use md o json)", opts.Prefer)
+	}
+
+	idx, err := fs.loadIndex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Los overrides van antes que su maestro: si el maestro se mueve de
+	// fecha, sus overrides se reescriben desde el .json ya actualizado
+	entries := make([]*indexEntry, 0, len(idx.Entries))
+	for _, e := range idx.Entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+
+	var paths []string
+	for _, e := range entries {
+		paths = append(paths, e.Overrides...)
+		paths = append(paths, e.Path)
+	}
+
+	result := &SyncResult{}
+	refresh := make(map[string]syncState)
+	var regenerate, reindex []*syncFile
+
+	for _, rel := range paths {
+		f, err := fs.readSyncFile(userID, rel)
+		if err != nil {
+			return nil, err
+		}
+		item := SyncItem{Path: rel + ".md", ID: f.entry.ID, Entry: f.entry}
+
+		// Un .json editado en su lugar no cambia ningún directorio, así que
+		// el índice no lo detecta (ver loadIndex): se vuelve a indexar, salvo
+		// que se importe su .md (UpdateEntry ya lo indexa)
+		jsonEdited := idx.Synced[rel].JSON != f.state.JSON
+
+		if f.md != nil && string(f.md) == f.rendered {
+			result.Unchanged++
+			if idx.Synced[rel] != f.state {
+				refresh[rel] = f.state
+			}
+			if jsonEdited {
+				reindex = append(reindex, f)
+			}
+			continue
+		}
+		if f.md == nil {
+			result.Regenerated = append(result.Regenerated, item)
+			regenerate = append(regenerate, f)
+			if jsonEdited {
+				reindex = append(reindex, f)
+			}
+			continue
+		}
+
+		mdChanged, jsonChanged, err := fs.syncChanges(userID, idx, f)
+		if err != nil {
+			return nil, err
+		}
+		if mdChanged && jsonChanged {
+			if opts.Prefer == "" {
+				result.Conflicts = append(result.Conflicts, item)
+				continue
+			}
+			mdChanged = opts.Prefer == SyncPreferMarkdown
+		}
+
+		if !mdChanged {
+			result.Regenerated = append(result.Regenerated, item)
+			regenerate = append(regenerate, f)
+			if jsonEdited {
+				reindex = append(reindex, f)
+			}
+			continue
+		}
+
+		edited, err := markdownToEntry(string(f.md), f.entry)
+		if err == nil {
+			err = edited.Validate()
+		}
+		if err != nil {
+			item.Err = err
+			result.Invalid = append(result.Invalid, item)
+			continue
+		}
+		result.Imported = append(result.Imported, item)
+		if opts.DryRun {
+			continue
+		}
+		if err := fs.UpdateEntry(userID, edited); err != nil {
+			return nil, fmt.Errorf("error aplicando %s: %w", item.Path, err)
+		}
+	}
+
+	if opts.DryRun || (len(regenerate) == 0 && len(refresh) == 0 && len(reindex) == 0) {
+		return result, nil
+	}
+
+	// Si el .json cambió de día el evento quedó en el directorio de otro día:
+	// se guarda de nuevo para moverlo (y al regenerar se saltea su .md viejo)
+	var inPlace []*syncFile
+	for _, f := range reindex {
+		if f.entry.IsOccurrence() {
+			continue
+		}
+		if path.Dir(relEntryPath(f.entry.DateTime, f.entry.GenerateFilename())) == path.Dir(f.rel) {
+			inPlace = append(inPlace, f)
+			continue
+		}
+		if err := fs.UpdateEntry(userID, f.entry); err != nil {
+			return nil, fmt.Errorf("error moviendo %s: %w", f.rel+".json", err)
+		}
+	}
+
+	err = fs.updateIndex(userID, func(idx *entryIndex) error {
+		for _, f := range inPlace {
+			if e, ok := idx.Entries[f.entry.ID]; ok && e.Path == f.rel {
+				idx.put(f.entry, f.rel)
+			}
+		}
+		for rel, state := range refresh {
+			idx.Synced[rel] = state
+		}
+		for _, f := range regenerate {
+			// Un override cuyo maestro se importó ya se reescribió en otra ruta
+			jsonPath := fs.indexedPath(userID, f.rel)
+			if _, err := os.Stat(jsonPath); os.IsNotExist(err) {
+				continue
+			}
+			mdPath := strings.TrimSuffix(jsonPath, ".json") + ".md"
+			if err := writeFileAtomic(mdPath, []byte(f.rendered), 0644); err != nil {
+				return fmt.Errorf("error escribiendo markdown: %w", err)
+			}
+			idx.Synced[f.rel] = syncState{MD: contentHash([]byte(f.rendered)), JSON: f.state.JSON}
+			idx.touched[path.Dir(f.rel)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// readSyncFile lee el .json y el .md (nil si no existe) de una entrada del índice
+func (fs *FilesystemStorage) readSyncFile(userID, rel string) (*syncFile, error) {
+	jsonPath := fs.indexedPath(userID, rel)
+	jsonData, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo %s: %w", jsonPath, err)
+	}
+	var entry calendar.Entry
+	if err := json.Unmarshal(jsonData, &entry); err != nil {
+		return nil, fmt.Errorf("error parseando %s: %w", jsonPath, err)
+	}
+
+	f := &syncFile{
+		rel:      rel,
+		entry:    &entry,
+		state:    syncState{JSON: contentHash(jsonData)},
+		rendered: entryToMarkdown(&entry),
+	}
+
+	mdPath := strings.TrimSuffix(jsonPath, ".json") + ".md"
+	f.md, err = os.ReadFile(mdPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return nil, fmt.Errorf("error leyendo %s: %w", mdPath, err)
+	}
+	f.state.MD = contentHash(f.md)
+
+	return f, nil
+}
+
+// syncChanges decide qué lado de un par .md/.json que no coinciden cambió
+// desde la última vez que clical lo escribió
+func (fs *FilesystemStorage) syncChanges(userID string, idx *entryIndex, f *syncFile) (mdChanged, jsonChanged bool, err error) {
+	if last, ok := idx.Synced[f.rel]; ok {
+		mdChanged = f.state.MD != last.MD
+		jsonChanged = f.state.JSON != last.JSON
+		if mdChanged || jsonChanged {
+			return mdChanged, jsonChanged, nil
+		}
+		// Ninguno cambió: el .md es de un formato anterior, se regenera
+		return false, true, nil
+	}
+
+	// Sin hashes (datos anteriores a la sincronización o restaurados): gana
+	// el archivo modificado más recientemente
+	jsonPath := fs.indexedPath(userID, f.rel)
+	jsonInfo, err := os.Stat(jsonPath)
+	if err != nil {
+		return false, false, err
+	}
+	mdInfo, err := os.Stat(strings.TrimSuffix(jsonPath, ".json") + ".md")
+	if err != nil {
+		return false, false, err
+	}
+	mdChanged = mdInfo.ModTime().After(jsonInfo.ModTime())
+	return mdChanged, !mdChanged, nil
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+func TestSyncMarkdown(t *testing.T) {
+	start := time.Date(2025, 11, 21, 14, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T) (*FilesystemStorage, *calendar.Entry, string, string) {
+		fs, err := NewFilesystemStorage(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry := calendar.NewEntry("u1", "Client meeting", start, 60)
+		if err := fs.SaveEntry("u1", entry); err != nil {
+			t.Fatal(err)
+		}
+		jsonPath := getEntryPath(fs.dataDir, "u1", start, entry.GenerateFilename(), ".json")
+		mdPath := strings.TrimSuffix(jsonPath, ".json") + ".md"
+		return fs, entry, mdPath, jsonPath
+	}
+
+	replaceIn := func(t *testing.T, path, old, new string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), old) {
+			t.Fatalf("%s no contiene %q", path, old)
+		}
+		if err := os.WriteFile(path, []byte(strings.Replace(string(data), old, new, 1)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count := func(r *SyncResult) [4]int {
+		return [4]int{len(r.Imported), len(r.Regenerated), len(r.Conflicts), len(r.Invalid)}
+	}
+
+	t.Run("md editado", func(t *testing.T) {
+		fs, entry, mdPath, _ := setup(t)
+		replaceIn(t, mdPath, "# Client meeting", "# Client lunch")
+		replaceIn(t, mdPath, "**Hora:** 14:00", "**Hora:** 12:30")
+
+		result, err := fs.SyncMarkdown("u1", SyncOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count(result) != [4]int{1, 0, 0, 0} {
+			t.Fatalf("resultado = %+v", result)
+		}
+
+		got, err := fs.GetEntry("u1", entry.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Title != "Client lunch" || got.DateTime.Format("15:04") != "12:30" {
+			t.Errorf("evento = %q %s", got.Title, got.DateTime.Format("15:04"))
+		}
+		if _, err := os.Stat(mdPath); !os.IsNotExist(err) {
+			t.Errorf("el .md viejo sigue en %s", mdPath)
+		}
+
+		// Ya sincronizado: nada que hacer
+		result, err = fs.SyncMarkdown("u1", SyncOptions{})
+		if err != nil || count(result) != [4]int{} || result.Unchanged != 1 {
+			t.Errorf("segunda sincronización = %+v, %v", result, err)
+		}
+	})
+
+	t.Run("json editado", func(t *testing.T) {
+		fs, _, mdPath, jsonPath := setup(t)
+		replaceIn(t, jsonPath, `"title": "Client meeting"`, `"title": "Renamed"`)
+
+		result, err := fs.SyncMarkdown("u1", SyncOptions{})
+		if err != nil || count(result) != [4]int{0, 1, 0, 0} {
+			t.Fatalf("SyncMarkdown() = %+v, %v", result, err)
+		}
+		md, _ := os.ReadFile(mdPath)
+		if !strings.HasPrefix(string(md), "# Renamed\n") {
+			t.Errorf(".md no se regeneró:\n%s", md)
+		}
+	})
+
+	t.Run("conflicto", func(t *testing.T) {
+		fs, entry, mdPath, jsonPath := setup(t)
+		replaceIn(t, jsonPath, `"title": "Client meeting"`, `"title": "From JSON"`)
+		replaceIn(t, mdPath, "# Client meeting", "# From Markdown")
+
+		result, err := fs.SyncMarkdown("u1", SyncOptions{})
+		if err != nil || count(result) != [4]int{0, 0, 1, 0} {
+			t.Fatalf("SyncMarkdown() = %+v, %v", result, err)
+		}
+		if got, _ := fs.GetEntry("u1", entry.ID); got.Title != "From JSON" {
+			t.Errorf("el conflicto modificó el evento: %q", got.Title)
+		}
+
+		result, err = fs.SyncMarkdown("u1", SyncOptions{Prefer: SyncPreferMarkdown})
+		if err != nil || count(result) != [4]int{1, 0, 0, 0} {
+			t.Fatalf("SyncMarkdown(md) = %+v, %v", result, err)
+		}
+		if got, _ := fs.GetEntry("u1", entry.ID); got.Title != "From Markdown" {
+			t.Errorf("título = %q, want From Markdown", got.Title)
+		}
+	})
+
+	t.Run("sin hashes gana el más nuevo", func(t *testing.T) {
+		fs, entry, mdPath, _ := setup(t)
+		if err := os.Remove(getStatePath(fs.dataDir, "u1", indexFilename)); err != nil {
+			t.Fatal(err)
+		}
+		replaceIn(t, mdPath, "# Client meeting", "# Edited")
+		later := time.Now().Add(time.Minute)
+		os.Chtimes(mdPath, later, later)
+
+		fresh, _ := NewFilesystemStorage(fs.dataDir)
+		result, err := fresh.SyncMarkdown("u1", SyncOptions{})
+		if err != nil || count(result) != [4]int{1, 0, 0, 0} {
+			t.Fatalf("SyncMarkdown() = %+v, %v", result, err)
+		}
+		if got, _ := fresh.GetEntry("u1", entry.ID); got.Title != "Edited" {
+			t.Errorf("título = %q, want Edited", got.Title)
+		}
+	})
+
+	t.Run("md inválido y dry run", func(t *testing.T) {
+		fs, entry, mdPath, _ := setup(t)
+		replaceIn(t, mdPath, "**Hora:** 14:00", "**Hora:** mañana")
+
+		result, err := fs.SyncMarkdown("u1", SyncOptions{})
+		if err != nil || count(result) != [4]int{0, 0, 0, 1} || result.Invalid[0].Err == nil {
+			t.Fatalf("SyncMarkdown() = %+v, %v", result, err)
+		}
+
+		replaceIn(t, mdPath, "**Hora:** mañana", "**Hora:** 16:00")
+		result, err = fs.SyncMarkdown("u1", SyncOptions{DryRun: true})
+		if err != nil || count(result) != [4]int{1, 0, 0, 0} {
+			t.Fatalf("SyncMarkdown(dry run) = %+v, %v", result, err)
+		}
+		if got, _ := fs.GetEntry("u1", entry.ID); !got.DateTime.Equal(start) {
+			t.Errorf("el dry run modificó el evento: %v", got.DateTime)
+		}
+	})
+}
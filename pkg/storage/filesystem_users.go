@@ -0,0 +1,407 @@
+package storage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/user"
+)
+
+// ArchiveUser escribe en w un .tar.gz con todos los datos del usuario
+// (eventos, alarmas y estado), bajo el directorio {userID}/
+func (fs *FilesystemStorage) ArchiveUser(userID string, w io.Writer) error {
+	userDir := getUserDir(fs.dataDir, userID)
+	if _, err := os.Stat(userDir); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("usuario no encontrado: %s", userID)
+		}
+		return fmt.Errorf("error leyendo usuario: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.Walk(userDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(userDir, path)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(userID, rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error archivando usuario: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("error archivando usuario: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("error archivando usuario: %w", err)
+	}
+
+	return nil
+}
+
+// RenameUser cambia el ID de un usuario, moviendo sus eventos, alarmas y estado.
+// Los datos se copian y actualizan en un directorio temporal que luego se
+// renombra al definitivo, por lo que ante un error el usuario original queda intacto.
+// El directorio original se renombra a una lápida en .tmp/ antes de eliminarlo,
+// así que nunca queda a medio borrar: si no se puede eliminar, se elimina al
+// abrir el storage la próxima vez.
+func (fs *FilesystemStorage) RenameUser(oldID, newID string) error {
+	if err := user.ValidateID(newID); err != nil {
+		return err
+	}
+
+	u, err := fs.GetUser(oldID)
+	if err != nil {
+		return err
+	}
+
+	newDir := getUserDir(fs.dataDir, newID)
+	if _, err := os.Stat(getUserPath(fs.dataDir, newID, ".json")); err == nil {
+		return fmt.Errorf("ya existe un usuario con id %s", newID)
+	}
+
+	// Preparar la copia en un directorio temporal dentro de dataDir (mismo
+	// filesystem, para que el rename final sea atómico)
+	tmpRoot := filepath.Join(fs.dataDir, ".tmp")
+	if err := os.MkdirAll(tmpRoot, 0755); err != nil {
+		return fmt.Errorf("error creando directorio: %w", err)
+	}
+	staging, err := os.MkdirTemp(tmpRoot, renamePrefix+newID+"-")
+	if err != nil {
+		return fmt.Errorf("error creando directorio: %w", err)
+	}
+	defer func() {
+		os.RemoveAll(staging)
+		os.Remove(tmpRoot) // solo si quedó vacío
+	}()
+	if err := os.Chmod(staging, 0755); err != nil {
+		return fmt.Errorf("error creando directorio: %w", err)
+	}
+
+	if err := copyDir(getUserDir(fs.dataDir, oldID), staging); err != nil {
+		return fmt.Errorf("error copiando datos: %w", err)
+	}
+
+	if err := rewriteEntriesUserID(filepath.Join(staging, "events"), newID); err != nil {
+		return err
+	}
+
+	u.ID = newID
+	if err := writeUserFiles(staging, u); err != nil {
+		return err
+	}
+
+	// Si newID tiene historial o papelera de un usuario eliminado del backend
+	// (ej: al migrar con --force), se conservan salvo que oldID tenga los suyos
+	var leftover string
+	if _, err := os.Stat(newDir); err == nil {
+		for _, sub := range wrapperSubdirs {
+			if _, err := os.Stat(filepath.Join(staging, sub)); err == nil {
+				continue
+			}
+			if err := moveDir(filepath.Join(newDir, sub), filepath.Join(staging, sub)); err != nil {
+				return err
+			}
+		}
+		if leftover, err = buryDir(tmpRoot, newDir); err != nil {
+			return err
+		}
+	}
+
+	fs.invalidateIndex(oldID)
+	fs.invalidateIndex(newID)
+	if err := os.Rename(staging, newDir); err != nil {
+		if leftover != "" {
+			os.Rename(leftover, newDir)
+		}
+		return fmt.Errorf("error moviendo datos: %w", err)
+	}
+	if leftover != "" {
+		os.RemoveAll(leftover)
+	}
+
+	tomb, err := buryDir(tmpRoot, getUserDir(fs.dataDir, oldID))
+	if err != nil {
+		return fmt.Errorf("datos movidos a %s, pero no se pudo eliminar %s: %w", newID, oldID, err)
+	}
+	os.RemoveAll(tomb) // si falla, se elimina en la próxima ejecución
+
+	return nil
+}
+
+// Prefijos de los directorios temporales de .tmp/
+const (
+	renamePrefix    = "rename-"  // copia en preparación de RenameUser
+	tombstonePrefix = "deleted-" // lápida: datos reemplazados, pendientes de eliminar
+)
+
+// buryDir renombra dir a una lápida dentro de tmpRoot y retorna su ruta.
+// El rename es atómico: dir desaparece entero o queda intacto.
+func buryDir(tmpRoot, dir string) (string, error) {
+	tomb := filepath.Join(tmpRoot, fmt.Sprintf("%s%s-%d", tombstonePrefix, filepath.Base(dir), time.Now().UnixNano()))
+	if err := os.Rename(dir, tomb); err != nil {
+		return "", fmt.Errorf("error eliminando %s: %w", dir, err)
+	}
+	return tomb, nil
+}
+
+// removeTombstones elimina las lápidas que quedaron en .tmp/ de un
+// RenameUser anterior que no pudo eliminarlas
+func removeTombstones(dataDir string) {
+	tmpRoot := filepath.Join(dataDir, ".tmp")
+	tombs, _ := filepath.Glob(filepath.Join(tmpRoot, tombstonePrefix+"*"))
+	for _, tomb := range tombs {
+		os.RemoveAll(tomb)
+	}
+	os.Remove(tmpRoot) // solo si quedó vacío
+}
+
+// writeUserFiles escribe user.md y user.json en dir
+func writeUserFiles(dir string, u *user.User) error {
+	mdPath := filepath.Join(dir, "user.md")
+	if err := writeFileAtomic(mdPath, []byte(userToMarkdown(u)), 0644); err != nil {
+		return fmt.Errorf("error escribiendo markdown: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializando JSON: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, "user.json"), jsonData, 0644); err != nil {
+		return fmt.Errorf("error escribiendo JSON: %w", err)
+	}
+
+	return nil
+}
+
+// rewriteEntriesUserID actualiza el user_id de todas las entradas (y overrides)
+// guardadas bajo eventsDir
+func rewriteEntriesUserID(eventsDir, userID string) error {
+	if _, err := os.Stat(eventsDir); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.Walk(eventsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error leyendo %s: %w", path, err)
+		}
+
+		var entry calendar.Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("error parseando %s: %w", path, err)
+		}
+		entry.UserID = userID
+
+		jsonData, err := json.MarshalIndent(&entry, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error serializando JSON: %w", err)
+		}
+		if err := writeFileAtomic(path, jsonData, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("error escribiendo %s: %w", path, err)
+		}
+
+		return nil
+	})
+}
+
+// copyDir copia recursivamente src en dst (que ya debe existir)
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Sync(); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
+
+// DumpUser exporta todos los datos de un usuario
+func (fs *FilesystemStorage) DumpUser(userID string) (*UserDump, error) {
+	u, err := fs.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	dump := &UserDump{User: u}
+
+	eventsDir := filepath.Join(getUserDir(fs.dataDir, userID), "events")
+	if _, err := os.Stat(eventsDir); err == nil {
+		err := filepath.Walk(eventsDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !strings.HasSuffix(path, ".json") {
+				return nil
+			}
+
+			entry, err := readEntryFile(path)
+			if err != nil {
+				return err
+			}
+			dump.Entries = append(dump.Entries, entry)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dump.AlarmFiles, err = dumpAlarmFiles(fs, userID); err != nil {
+		return nil, err
+	}
+	if dump.UnackedAlarms, err = dumpUnackedAlarms(fs, userID); err != nil {
+		return nil, err
+	}
+
+	// Solo exportar el estado de reportes si existe
+	if _, err := os.Stat(getStatePath(fs.dataDir, userID, "report-state.json")); err == nil {
+		if dump.ReportState, err = fs.GetReportState(userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return dump, nil
+}
+
+// RestoreUser importa los datos exportados con DumpUser. Las entradas se
+// escriben tal cual, sin volver a validarlas ni normalizarlas.
+func (fs *FilesystemStorage) RestoreUser(dump *UserDump) error {
+	userID := dump.User.ID
+	if err := user.ValidateID(userID); err != nil {
+		return err
+	}
+
+	// El índice de eventos se reconstruye en el próximo uso
+	fs.invalidateIndex(userID)
+	if err := os.Remove(getStatePath(fs.dataDir, userID, indexFilename)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error eliminando índice: %w", err)
+	}
+
+	userDir := getUserDir(fs.dataDir, userID)
+	if err := os.MkdirAll(userDir, 0755); err != nil {
+		return fmt.Errorf("error creando directorio: %w", err)
+	}
+	if err := writeUserFiles(userDir, dump.User); err != nil {
+		return err
+	}
+
+	// Los overrides se guardan junto a su evento maestro
+	masters := make(map[string]*calendar.Entry)
+	for _, entry := range dump.Entries {
+		if !entry.IsOccurrence() {
+			masters[entry.ID] = entry
+		}
+	}
+
+	for _, entry := range dump.Entries {
+		if !entry.IsOccurrence() {
+			if _, err := fs.writeEntryFiles(userID, entry.DateTime, entry.GenerateFilename(), entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		master, ok := masters[entry.ID]
+		if !ok {
+			return fmt.Errorf("override sin evento maestro: %s", entry.ID)
+		}
+		filename := getOverrideFilename(master.GenerateFilename(), *entry.RecurrenceID)
+		if _, err := fs.writeEntryFiles(userID, master.DateTime, filename, entry); err != nil {
+			return err
+		}
+	}
+
+	if len(dump.AlarmFiles) > 0 {
+		if err := NewAlarmPaths(fs.dataDir, userID).EnsureAlarmDirs(); err != nil {
+			return err
+		}
+		if err := restoreAlarmFiles(fs, userID, dump.AlarmFiles); err != nil {
+			return err
+		}
+	}
+	if err := fs.writeUnackedAlarms(userID, dump.UnackedAlarms); err != nil {
+		return err
+	}
+
+	if dump.ReportState != nil {
+		return fs.SaveReportState(userID, dump.ReportState)
+	}
+	return nil
+}
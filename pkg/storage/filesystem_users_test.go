@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sebasvalencia/clical/pkg/user"
+)
+
+func TestRenameUserTombstone(t *testing.T) {
+	dir := t.TempDir()
+
+	// Una lápida que un RenameUser anterior no pudo eliminar
+	tomb := filepath.Join(dir, ".tmp", tombstonePrefix+"u0-1")
+	if err := os.MkdirAll(filepath.Join(tomb, "events"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Una copia en preparación de otro proceso no se toca
+	staging := filepath.Join(dir, ".tmp", renamePrefix+"u9-1")
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err := NewFilesystemStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(tomb); !os.IsNotExist(err) {
+		t.Errorf("Expected tombstone to be removed, stat error = %v", err)
+	}
+	if _, err := os.Stat(staging); err != nil {
+		t.Errorf("Expected rename staging dir to be kept: %v", err)
+	}
+	os.RemoveAll(staging)
+
+	// El historial que quedó de un usuario eliminado del backend se conserva
+	// al renombrar otro usuario a su ID
+	if err := fs.SaveUser(user.NewUser("u1", "Ana", "UTC")); err != nil {
+		t.Fatal(err)
+	}
+	journal := filepath.Join(getStateDir(dir, "u2"), "history", historyFilename)
+	if err := os.MkdirAll(filepath.Dir(journal), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(journal, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fs.RenameUser("u1", "u2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(journal); err != nil {
+		t.Errorf("Expected history of u2 to be kept: %v", err)
+	}
+	if _, err := os.Stat(getUserDir(dir, "u1")); !os.IsNotExist(err) {
+		t.Errorf("Expected u1 directory to be removed, stat error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".tmp")); !os.IsNotExist(err) {
+		t.Errorf("Expected .tmp to be removed, stat error = %v", err)
+	}
+}
@@ -0,0 +1,826 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/user"
+)
+
+const (
+	// gitBranch es la rama del repositorio del directorio de datos
+	gitBranch = "main"
+	// gitDefaultRemote es el remoto que usa SyncRemote si no se indica otro
+	gitDefaultRemote = "origin"
+	// gitEmail es el email de los commits; el nombre es el actor
+	gitEmail = "clical@localhost"
+)
+
+// gitIgnore son los archivos del directorio de datos que no se versionan:
+// locks, temporales de escrituras atómicas y de renombres, índices, que se
+// reconstruyen solos, y la base SQLite que deja 'clical migrate' (git solo se
+// usa con el backend de filesystem)
+const gitIgnore = `# Generado por clical
+.locks/
+.tmp/
+*.tmp
+users/*/.state/` + indexFilename + `
+/` + SQLiteFilename + `
+*.db-wal
+*.db-shm
+*.db-journal
+`
+
+// gitAttributes hace que un merge del journal del historial (append-only)
+// conserve las líneas de ambos lados en lugar de marcar un conflicto
+const gitAttributes = `# Generado por clical
+users/*/.state/history/` + historyFilename + ` merge=union
+`
+
+// Versión conservada al resolver un conflicto de merge
+const (
+	GitKeptLocal       = "local"
+	GitKeptRemote      = "remote"
+	GitKeptBoth        = "both"        // alarmas de ambos lados
+	GitKeptRegenerated = "regenerated" // .md regenerado desde su .json
+)
+
+// GitCommit es un commit del directorio de datos
+type GitCommit struct {
+	Hash    string    `json:"hash"`
+	Time    time.Time `json:"time"`
+	Author  string    `json:"author"`
+	Subject string    `json:"subject"`
+}
+
+// GitLogOptions filtra el log del directorio de datos
+type GitLogOptions struct {
+	UserID  string // sólo commits que cambian datos de este usuario
+	EntryID string // sólo commits de este evento o alarma (el [id] del mensaje)
+	Limit   int    // 0: todos
+}
+
+// GitResolution es un archivo en conflicto resuelto automáticamente en un merge
+type GitResolution struct {
+	Path string `json:"path"`
+	Kept string `json:"kept"`
+}
+
+// RemoteSyncResult es el resultado de SyncRemote
+type RemoteSyncResult struct {
+	Remote   string          `json:"remote"`
+	Branch   string          `json:"branch"`
+	Pulled   int             `json:"pulled"` // commits traídos del remoto
+	Pushed   int             `json:"pushed"` // commits enviados al remoto
+	Resolved []GitResolution `json:"resolved,omitempty"`
+}
+
+// GitVersioned lo implementan los Storage que versionan el directorio de
+// datos con git
+type GitVersioned interface {
+	// GitLog retorna los commits del directorio de datos, del más nuevo al más viejo
+	GitLog(opts GitLogOptions) ([]*GitCommit, error)
+	// SyncRemote trae y mergea los commits de remote (nombre de remoto, ruta
+	// o URL; vacío: origin) y le envía los locales
+	SyncRemote(remote string) (*RemoteSyncResult, error)
+}
+
+// gitStorage envuelve un Storage haciendo un commit de git en el directorio
+// de datos después de cada operación que modifica datos, con un mensaje como
+// "add: Reunión con cliente [id]". El repositorio se crea con el primer
+// cambio. Un lock de archivo (.locks/git.lock) serializa los comandos de git
+// de procesos concurrentes.
+type gitStorage struct {
+	Storage
+	dataDir string
+	command string
+	actor   string
+	timeout time.Duration
+}
+
+// Interfaces opcionales que gitStorage implementa o reenvía al storage envuelto
+var (
+	_ GitVersioned   = (*gitStorage)(nil)
+	_ MarkdownSyncer = (*gitStorage)(nil)
+	_ HistoryKeeper  = (*gitStorage)(nil)
+	_ TrashBin       = (*gitStorage)(nil)
+	_ io.Closer      = (*gitStorage)(nil)
+)
+
+// WithGit retorna s versionando el directorio de datos con git. command y
+// actor van en cada commit; lockTimeout es cuánto se espera el lock del
+// repositorio.
+func WithGit(s Storage, dataDir, command, actor string, lockTimeout time.Duration) Storage {
+	return &gitStorage{Storage: s, dataDir: dataDir, command: command, actor: actor, timeout: lockTimeout}
+}
+
+// Close cierra el storage envuelto
+func (g *gitStorage) Close() error {
+	return Close(g.Storage)
+}
+
+// git ejecuta un comando de git en el directorio de datos y retorna su salida
+func (g *gitStorage) git(args ...string) (string, error) {
+	name := g.actor
+	if name == "" {
+		name = "clical"
+	}
+
+	cmd := exec.Command("git", append([]string{"-c", "commit.gpgsign=false", "-c", "core.quotepath=false"}, args...)...)
+	cmd.Dir = g.dataDir
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_AUTHOR_NAME="+name, "GIT_AUTHOR_EMAIL="+gitEmail,
+		"GIT_COMMITTER_NAME="+name, "GIT_COMMITTER_EMAIL="+gitEmail,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return stdout.String(), fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return stdout.String(), nil
+}
+
+// withRepo ejecuta fn con el lock del repositorio tomado, creándolo si no existe
+func (g *gitStorage) withRepo(fn func() error) error {
+	dir := filepath.Join(g.dataDir, ".locks")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creando directorio de locks: %w", err)
+	}
+	f, err := acquireLock(filepath.Join(dir, "git.lock"), "repositorio git", g.timeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		unlockFile(f)
+		f.Close()
+	}()
+
+	if err := g.ensureRepo(); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// ensureRepo inicializa el repositorio con un primer commit de los datos existentes
+func (g *gitStorage) ensureRepo() error {
+	if _, err := os.Stat(filepath.Join(g.dataDir, ".git")); os.IsNotExist(err) {
+		if _, err := g.git("init", "-q"); err != nil {
+			return err
+		}
+		if _, err := g.git("symbolic-ref", "HEAD", "refs/heads/"+gitBranch); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return fmt.Errorf("error leyendo repositorio git: %w", err)
+	}
+
+	for name, content := range map[string]string{".gitignore": gitIgnore, ".gitattributes": gitAttributes} {
+		path := filepath.Join(g.dataDir, name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if err := writeFileAtomic(path, []byte(content), 0644); err != nil {
+				return fmt.Errorf("error escribiendo %s: %w", name, err)
+			}
+		}
+	}
+
+	if _, err := g.git("rev-parse", "--verify", "-q", "HEAD"); err != nil {
+		return g.commit("init: clical data directory", "")
+	}
+	return nil
+}
+
+// commit agrega todos los cambios del directorio de datos y hace un commit,
+// si hay alguno. El cuerpo del mensaje lleva el usuario y el comando.
+func (g *gitStorage) commit(subject, userID string) error {
+	status, err := g.git("status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(status) == "" {
+		return nil
+	}
+
+	if _, err := g.git("add", "-A"); err != nil {
+		return err
+	}
+
+	args := []string{"commit", "-q", "--no-verify", "-m", subject}
+	var body []string
+	if userID != "" {
+		body = append(body, "user: "+userID)
+	}
+	if g.command != "" {
+		body = append(body, "command: "+g.command)
+	}
+	if len(body) > 0 {
+		args = append(args, "-m", strings.Join(body, "\n"))
+	}
+	_, err = g.git(args...)
+	return err
+}
+
+// change ejecuta fn, que modifica datos y retorna el asunto del commit, y
+// hace el commit si terminó bien. Todo con el lock del repositorio tomado,
+// para que un commit no se lleve cambios a medio hacer de otro proceso.
+func (g *gitStorage) change(userID string, fn func() (string, error)) error {
+	return g.withRepo(func() error {
+		subject, err := fn()
+		if err != nil {
+			return err
+		}
+		if err := g.commit(subject, userID); err != nil {
+			return fmt.Errorf("cambio guardado pero falló el commit de git: %w", err)
+		}
+		return nil
+	})
+}
+
+// gitSubject arma el asunto de un commit: "op: título [id]"
+func gitSubject(op, title, id string) string {
+	subject := op
+	if title != "" {
+		subject += ": " + title
+	}
+	if id != "" {
+		subject += " [" + id + "]"
+	}
+	return subject
+}
+
+func (g *gitStorage) SaveEntry(userID string, entry *calendar.Entry) error {
+	return g.change(userID, func() (string, error) {
+		op := "add"
+		if entry.IsOccurrence() {
+			op = "update"
+		} else if _, err := g.Storage.GetEntry(userID, entry.ID); err == nil {
+			op = "update"
+		}
+		return gitSubject(op, entry.Title, entry.ID), g.Storage.SaveEntry(userID, entry)
+	})
+}
+
+func (g *gitStorage) UpdateEntry(userID string, entry *calendar.Entry) error {
+	return g.change(userID, func() (string, error) {
+		return gitSubject("update", entry.Title, entry.ID), g.Storage.UpdateEntry(userID, entry)
+	})
+}
+
+func (g *gitStorage) DeleteEntry(userID, entryID string) error {
+	return g.change(userID, func() (string, error) {
+		title := ""
+		if entry, err := g.Storage.GetEntry(userID, entryID); err == nil {
+			title = entry.Title
+		}
+		return gitSubject("delete", title, entryID), g.Storage.DeleteEntry(userID, entryID)
+	})
+}
+
+func (g *gitStorage) SaveUser(u *user.User) error {
+	return g.change(u.ID, func() (string, error) {
+		op := "add user"
+		if _, err := g.Storage.GetUser(u.ID); err == nil {
+			op = "update user"
+		}
+		return gitSubject(op, u.Name, u.ID), g.Storage.SaveUser(u)
+	})
+}
+
+func (g *gitStorage) DeleteUser(userID string) error {
+	return g.change(userID, func() (string, error) {
+		return gitSubject("delete user", "", userID), g.Storage.DeleteUser(userID)
+	})
+}
+
+func (g *gitStorage) RenameUser(oldID, newID string) error {
+	return g.change(newID, func() (string, error) {
+		return gitSubject("rename user", oldID+" → "+newID, ""), g.Storage.RenameUser(oldID, newID)
+	})
+}
+
+func (g *gitStorage) RestoreUser(dump *UserDump) error {
+	return g.change(dump.User.ID, func() (string, error) {
+		return gitSubject("restore user", dump.User.Name, dump.User.ID), g.Storage.RestoreUser(dump)
+	})
+}
+
+func (g *gitStorage) SaveReportState(userID string, state *ReportState) error {
+	return g.change(userID, func() (string, error) {
+		return gitSubject("report state", "", userID), g.Storage.SaveReportState(userID, state)
+	})
+}
+
+func (g *gitStorage) SaveAlarm(userID string, alarmTime time.Time, recurrence alarm.Recurrence, filename string, alm *alarm.Alarm) error {
+	return g.change(userID, func() (string, error) {
+		return gitSubject("add alarm", alm.Context, alm.ID), g.Storage.SaveAlarm(userID, alarmTime, recurrence, filename, alm)
+	})
+}
+
+func (g *gitStorage) CancelAlarm(userID string, alarmID string) error {
+	return g.change(userID, func() (string, error) {
+		title := ""
+		if alarms, err := g.Storage.ListActiveAlarms(userID); err == nil {
+			for _, alm := range alarms {
+				if alm.ID == alarmID {
+					title = alm.Context
+				}
+			}
+		}
+		return gitSubject("cancel alarm", title, alarmID), g.Storage.CancelAlarm(userID, alarmID)
+	})
+}
+
+func (g *gitStorage) DeleteAlarms(userID string, recurrence alarm.Recurrence, filename string) error {
+	return g.change(userID, func() (string, error) {
+		return gitSubject("delete alarms", string(recurrence)+"/"+filename, ""), g.Storage.DeleteAlarms(userID, recurrence, filename)
+	})
+}
+
+func (g *gitStorage) MoveAlarmsToPast(userID string, recurrence alarm.Recurrence, filename string) error {
+	return g.change(userID, func() (string, error) {
+		return gitSubject("archive alarms", string(recurrence)+"/"+filename, ""), g.Storage.MoveAlarmsToPast(userID, recurrence, filename)
+	})
+}
+
+func (g *gitStorage) CheckAlarms(userID string, at time.Time) ([]*alarm.Alarm, error) {
+	var alarms []*alarm.Alarm
+	err := g.change(userID, func() (string, error) {
+		var err error
+		alarms, err = g.Storage.CheckAlarms(userID, at)
+		return gitSubject("alarm check", fmt.Sprintf("%d fired", len(alarms)), ""), err
+	})
+	return alarms, err
+}
+
+func (g *gitStorage) AckAlarm(userID string, alarmID string) error {
+	return g.change(userID, func() (string, error) {
+		return gitSubject("ack alarm", "", alarmID), g.Storage.AckAlarm(userID, alarmID)
+	})
+}
+
+func (g *gitStorage) SnoozeAlarm(userID string, alarmID string, at time.Time, d time.Duration) (*alarm.Alarm, error) {
+	var snoozed *alarm.Alarm
+	err := g.change(userID, func() (string, error) {
+		var err error
+		snoozed, err = g.Storage.SnoozeAlarm(userID, alarmID, at, d)
+		return gitSubject("snooze alarm", d.String(), alarmID), err
+	})
+	return snoozed, err
+}
+
+func (g *gitStorage) NagAlarms(userID string, at time.Time, every time.Duration) ([]*alarm.Alarm, error) {
+	var alarms []*alarm.Alarm
+	err := g.change(userID, func() (string, error) {
+		var err error
+		alarms, err = g.Storage.NagAlarms(userID, at, every)
+		return gitSubject("alarm nag", fmt.Sprintf("%d re-delivered", len(alarms)), ""), err
+	})
+	return alarms, err
+}
+
+// SyncMarkdown hace el commit de los eventos importados desde su Markdown
+func (g *gitStorage) SyncMarkdown(userID string, opts SyncOptions) (*SyncResult, error) {
+	syncer, ok := g.Storage.(MarkdownSyncer)
+	if !ok {
+		return nil, fmt.Errorf("el backend de storage no guarda archivos Markdown")
+	}
+	if opts.DryRun {
+		return syncer.SyncMarkdown(userID, opts)
+	}
+
+	var result *SyncResult
+	err := g.change(userID, func() (string, error) {
+		var err error
+		if result, err = syncer.SyncMarkdown(userID, opts); err != nil {
+			return "", err
+		}
+		return gitSubject("sync", fmt.Sprintf("%d imported, %d regenerated", len(result.Imported), len(result.Regenerated)), ""), nil
+	})
+	return result, err
+}
+
+// historyKeeper retorna el Storage envuelto como HistoryKeeper
+func (g *gitStorage) historyKeeper() (HistoryKeeper, error) {
+	keeper, ok := g.Storage.(HistoryKeeper)
+	if !ok {
+		return nil, fmt.Errorf("el historial de cambios no está habilitado")
+	}
+	return keeper, nil
+}
+
+func (g *gitStorage) History(userID, entryID string) ([]*HistoryRecord, error) {
+	keeper, err := g.historyKeeper()
+	if err != nil {
+		return nil, err
+	}
+	return keeper.History(userID, entryID)
+}
+
+func (g *gitStorage) Undo(userID string, steps int) ([]*HistoryRecord, error) {
+	keeper, err := g.historyKeeper()
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*HistoryRecord
+	err = g.change(userID, func() (string, error) {
+		var err error
+		records, err = keeper.Undo(userID, steps)
+		if len(records) == 1 {
+			return gitSubject("undo "+records[0].Op, records[0].Title(), records[0].EntryID), err
+		}
+		return gitSubject("undo", fmt.Sprintf("%d changes", len(records)), ""), err
+	})
+	return records, err
+}
+
+func (g *gitStorage) Restore(userID, entryID string, version int) (*HistoryRecord, error) {
+	keeper, err := g.historyKeeper()
+	if err != nil {
+		return nil, err
+	}
+
+	var record *HistoryRecord
+	err = g.change(userID, func() (string, error) {
+		var err error
+		if record, err = keeper.Restore(userID, entryID, version); err != nil {
+			return "", err
+		}
+		return gitSubject(fmt.Sprintf("restore v%d", version), record.Title(), entryID), nil
+	})
+	return record, err
+}
+
+// trashBin retorna el Storage envuelto como TrashBin
+func (g *gitStorage) trashBin() (TrashBin, error) {
+	bin, ok := g.Storage.(TrashBin)
+	if !ok {
+		return nil, fmt.Errorf("la papelera no está habilitada")
+	}
+	return bin, nil
+}
+
+func (g *gitStorage) ListTrash(userID string) ([]*TrashItem, error) {
+	bin, err := g.trashBin()
+	if err != nil {
+		return nil, err
+	}
+	return bin.ListTrash(userID)
+}
+
+func (g *gitStorage) RestoreTrash(userID, id string) (*TrashItem, error) {
+	bin, err := g.trashBin()
+	if err != nil {
+		return nil, err
+	}
+
+	var item *TrashItem
+	err = g.change(userID, func() (string, error) {
+		var err error
+		if item, err = bin.RestoreTrash(userID, id); err != nil {
+			return "", err
+		}
+		return gitSubject("restore "+item.Kind+" from trash", item.Title(), item.ID), nil
+	})
+	return item, err
+}
+
+func (g *gitStorage) PurgeTrash(userID string, before time.Time) (int, error) {
+	bin, err := g.trashBin()
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int
+	err = g.change(userID, func() (string, error) {
+		var err error
+		purged, err = bin.PurgeTrash(userID, before)
+		return gitSubject("purge trash", fmt.Sprintf("%d items", purged), ""), err
+	})
+	return purged, err
+}
+
+func (g *gitStorage) GitLog(opts GitLogOptions) ([]*GitCommit, error) {
+	if _, err := os.Stat(filepath.Join(g.dataDir, ".git")); os.IsNotExist(err) {
+		return nil, nil
+	}
+	if _, err := g.git("rev-parse", "--verify", "-q", "HEAD"); err != nil {
+		return nil, nil
+	}
+
+	args := []string{"log", "--format=%H%x1f%aI%x1f%an%x1f%s"}
+	if opts.Limit > 0 {
+		args = append(args, "-n", strconv.Itoa(opts.Limit))
+	}
+	if opts.EntryID != "" {
+		args = append(args, "--fixed-strings", "--grep=["+opts.EntryID+"]")
+	}
+	args = append(args, "--")
+	if opts.UserID != "" {
+		args = append(args, "users/"+opts.UserID+"/")
+	}
+
+	out, err := g.git(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*GitCommit
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\x1f", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("fecha de commit inválida %q: %w", fields[1], err)
+		}
+		commits = append(commits, &GitCommit{Hash: fields[0], Time: t, Author: fields[2], Subject: fields[3]})
+	}
+	return commits, nil
+}
+
+// SyncRemote hace el commit de los cambios pendientes (por ejemplo ediciones
+// a mano), trae y mergea la rama del remoto y le envía el resultado. Los
+// conflictos se resuelven con resolveConflicts.
+func (g *gitStorage) SyncRemote(remote string) (*RemoteSyncResult, error) {
+	if remote == "" {
+		remote = gitDefaultRemote
+	}
+
+	var result *RemoteSyncResult
+	err := g.withRepo(func() error {
+		if err := g.commit("commit pending changes", ""); err != nil {
+			return err
+		}
+
+		name, err := g.resolveRemote(remote)
+		if err != nil {
+			return err
+		}
+		out, err := g.git("symbolic-ref", "--short", "HEAD")
+		if err != nil {
+			return err
+		}
+		branch := strings.TrimSpace(out)
+		result = &RemoteSyncResult{Remote: name, Branch: branch}
+
+		// La rama no existe en un remoto recién creado: sólo se envía
+		out, err = g.git("ls-remote", "--heads", name, branch)
+		if err != nil {
+			return err
+		}
+		base := ""
+		if strings.TrimSpace(out) != "" {
+			if _, err := g.git("fetch", "-q", name, branch); err != nil {
+				return err
+			}
+			if result.Pulled, err = g.count("HEAD..FETCH_HEAD"); err != nil {
+				return err
+			}
+			if result.Pulled > 0 {
+				if result.Resolved, err = g.merge("FETCH_HEAD", gitSubject("merge", name, "")); err != nil {
+					return err
+				}
+				g.dropIndexes()
+			}
+			base = "FETCH_HEAD.."
+		}
+
+		if result.Pushed, err = g.count(base + "HEAD"); err != nil {
+			return err
+		}
+		if result.Pushed > 0 {
+			if _, err := g.git("push", "-q", name, "HEAD:refs/heads/"+branch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// resolveRemote retorna el remoto a usar: un remoto configurado, o una ruta
+// o URL, que se configura como origin si todavía no hay uno
+func (g *gitStorage) resolveRemote(remote string) (string, error) {
+	out, err := g.git("remote")
+	if err != nil {
+		return "", err
+	}
+	names := strings.Fields(out)
+	for _, n := range names {
+		if n == remote {
+			return remote, nil
+		}
+	}
+	if remote == gitDefaultRemote {
+		return "", fmt.Errorf("no hay un remoto %s configurado: indique la ruta o URL del repositorio", gitDefaultRemote)
+	}
+
+	// Las rutas locales son relativas al directorio actual, no al de datos
+	if _, err := os.Stat(remote); err == nil {
+		if abs, err := filepath.Abs(remote); err == nil {
+			remote = abs
+		}
+	}
+	for _, n := range names {
+		if n == gitDefaultRemote {
+			return remote, nil
+		}
+	}
+	if _, err := g.git("remote", "add", gitDefaultRemote, remote); err != nil {
+		return "", err
+	}
+	return gitDefaultRemote, nil
+}
+
+// count retorna la cantidad de commits de un rango
+func (g *gitStorage) count(revs string) (int, error) {
+	out, err := g.git("rev-list", "--count", revs)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(out))
+}
+
+// merge mergea rev en la rama actual resolviendo los conflictos
+func (g *gitStorage) merge(rev, message string) ([]GitResolution, error) {
+	_, mergeErr := g.git("merge", "-q", "--no-edit", "--no-verify", "--allow-unrelated-histories", "-m", message, rev)
+	if mergeErr == nil {
+		return nil, nil
+	}
+
+	resolved, err := g.resolveConflicts()
+	if err != nil || len(resolved) == 0 {
+		g.git("merge", "--abort")
+		if err == nil {
+			err = mergeErr
+		}
+		return nil, err
+	}
+	if _, err := g.git("commit", "-q", "--no-verify", "--no-edit"); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// resolveConflicts resuelve los archivos en conflicto de un merge, aprovechando
+// que cada evento es un archivo:
+//   - .json de un evento: gana la versión con updated_at más reciente
+//   - .md de un evento: se regenera desde su .json ya mergeado
+//   - archivo de alarmas: las alarmas de ambos lados, sin repetir IDs
+//   - un lado lo eliminó y el otro lo modificó: se conserva el modificado
+//   - cualquier otro archivo: se conserva la versión local
+func (g *gitStorage) resolveConflicts() ([]GitResolution, error) {
+	out, err := g.git("diff", "--name-only", "--diff-filter=U", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved []GitResolution
+	var markdown []string
+	for _, path := range strings.Split(out, "\x00") {
+		if path == "" {
+			continue
+		}
+		if isEventFile(path) && strings.HasSuffix(path, ".md") {
+			if !containsString(markdown, path) {
+				markdown = append(markdown, path)
+			}
+			continue
+		}
+
+		ours, hasOurs := g.stage(2, path)
+		theirs, hasTheirs := g.stage(3, path)
+		data, kept := ours, GitKeptLocal
+		switch {
+		case !hasOurs:
+			data, kept = theirs, GitKeptRemote
+		case !hasTheirs:
+		case isEventFile(path) && strings.HasSuffix(path, ".json"):
+			if newerEntry(theirs, ours) {
+				data, kept = theirs, GitKeptRemote
+			}
+			md := strings.TrimSuffix(path, ".json") + ".md"
+			if !containsString(markdown, md) {
+				markdown = append(markdown, md)
+			}
+		case isAlarmFile(path):
+			if union, err := unionAlarms(ours, theirs); err == nil {
+				data, kept = union, GitKeptBoth
+			}
+		}
+
+		if err := g.writeResolved(path, data); err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, GitResolution{Path: path, Kept: kept})
+	}
+
+	for _, path := range markdown {
+		data, kept := []byte(nil), GitKeptRegenerated
+		jsonData, err := os.ReadFile(filepath.Join(g.dataDir, filepath.FromSlash(strings.TrimSuffix(path, ".md")+".json")))
+		var entry calendar.Entry
+		if err == nil && json.Unmarshal(jsonData, &entry) == nil {
+			data = []byte(entryToMarkdown(&entry))
+		} else if ours, ok := g.stage(2, path); ok {
+			data, kept = ours, GitKeptLocal
+		} else if theirs, ok := g.stage(3, path); ok {
+			data, kept = theirs, GitKeptRemote
+		}
+		if err := g.writeResolved(path, data); err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, GitResolution{Path: path, Kept: kept})
+	}
+
+	return resolved, nil
+}
+
+// stage retorna una versión de un archivo en conflicto (2: local, 3: remota)
+func (g *gitStorage) stage(n int, path string) ([]byte, bool) {
+	out, err := g.git("show", fmt.Sprintf(":%d:%s", n, path))
+	if err != nil {
+		return nil, false
+	}
+	return []byte(out), true
+}
+
+// writeResolved escribe la resolución de un conflicto y la agrega al índice de git
+func (g *gitStorage) writeResolved(path string, data []byte) error {
+	full := filepath.Join(g.dataDir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return fmt.Errorf("error creando directorio: %w", err)
+	}
+	if err := writeFileAtomic(full, data, 0644); err != nil {
+		return fmt.Errorf("error escribiendo %s: %w", path, err)
+	}
+	_, err := g.git("add", "--", path)
+	return err
+}
+
+// dropIndexes elimina los índices de eventos, que un merge deja
+// desactualizados; se reconstruyen en la próxima lectura
+func (g *gitStorage) dropIndexes() {
+	paths, _ := filepath.Glob(filepath.Join(g.dataDir, "users", "*", ".state", indexFilename))
+	for _, p := range paths {
+		os.Remove(p)
+	}
+}
+
+// isEventFile indica si path (relativo al directorio de datos) es un archivo de evento
+func isEventFile(path string) bool {
+	return strings.HasPrefix(path, "users/") && strings.Contains(path, "/events/")
+}
+
+// isAlarmFile indica si path (relativo al directorio de datos) es un archivo de alarmas
+func isAlarmFile(path string) bool {
+	return strings.HasPrefix(path, "users/") && strings.Contains(path, "/alarms/") && strings.HasSuffix(path, ".json")
+}
+
+// newerEntry indica si el evento serializado en a es más reciente que el de b
+func newerEntry(a, b []byte) bool {
+	var ea, eb calendar.Entry
+	if json.Unmarshal(a, &ea) != nil || json.Unmarshal(b, &eb) != nil {
+		return false
+	}
+	return ea.UpdatedAt.After(eb.UpdatedAt)
+}
+
+// unionAlarms retorna las alarmas de ours seguidas de las de theirs que no están en ours
+func unionAlarms(ours, theirs []byte) ([]byte, error) {
+	var a, b []*alarm.Alarm
+	if err := json.Unmarshal(ours, &a); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(theirs, &b); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, alm := range a {
+		seen[alm.ID] = true
+	}
+	for _, alm := range b {
+		if !seen[alm.ID] {
+			a = append(a, alm)
+		}
+	}
+	return json.MarshalIndent(a, "", "  ")
+}
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+func newGitTestStorage(t *testing.T, dir, actor string) Storage {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git no está instalado")
+	}
+	fs, err := NewFilesystemStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := WithHistory(fs, dir, "clical test", actor)
+	return WithGit(s, dir, "clical test", actor, time.Second)
+}
+
+func gitSubjects(t *testing.T, s Storage, opts GitLogOptions) []string {
+	commits, err := s.(GitVersioned).GitLog(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subjects []string
+	for _, c := range commits {
+		subjects = append(subjects, c.Subject)
+	}
+	return subjects
+}
+
+func TestGitCommits(t *testing.T) {
+	s := newGitTestStorage(t, t.TempDir(), "tester")
+
+	entry := calendar.NewEntry("u1", "Meeting with client", time.Date(2025, 11, 21, 14, 0, 0, 0, time.UTC), 60)
+	if err := s.SaveEntry("u1", entry); err != nil {
+		t.Fatal(err)
+	}
+	entry.Notes = "Bring the contract"
+	if err := s.UpdateEntry("u1", entry); err != nil {
+		t.Fatal(err)
+	}
+	other := calendar.NewEntry("u1", "Other", time.Date(2025, 11, 22, 9, 0, 0, 0, time.UTC), 30)
+	if err := s.SaveEntry("u1", other); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteEntry("u1", entry.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	got := gitSubjects(t, s, GitLogOptions{})
+	want := []string{
+		"delete: Meeting with client [" + entry.ID + "]",
+		"add: Other [" + other.ID + "]",
+		"update: Meeting with client [" + entry.ID + "]",
+		"add: Meeting with client [" + entry.ID + "]",
+		"init: clical data directory",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("log =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if got := gitSubjects(t, s, GitLogOptions{EntryID: entry.ID, Limit: 2}); len(got) != 2 || !strings.HasPrefix(got[0], "delete:") {
+		t.Errorf("log --id = %v", got)
+	}
+	if got := gitSubjects(t, s, GitLogOptions{UserID: "u2"}); len(got) != 0 {
+		t.Errorf("log de otro usuario = %v", got)
+	}
+}
+
+func TestGitSyncRemote(t *testing.T) {
+	bare := filepath.Join(t.TempDir(), "remote.git")
+	a := newGitTestStorage(t, t.TempDir(), "alice")
+	b := newGitTestStorage(t, t.TempDir(), "bob")
+	if out, err := exec.Command("git", "init", "-q", "--bare", bare).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+
+	entry := calendar.NewEntry("u1", "Planning", time.Date(2025, 11, 21, 14, 0, 0, 0, time.UTC), 60)
+	if err := a.SaveEntry("u1", entry); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.(GitVersioned).SyncRemote(bare); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.(GitVersioned).SyncRemote(bare); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.GetEntry("u1", entry.ID); err != nil {
+		t.Fatalf("el evento no llegó al segundo clon: %v", err)
+	}
+
+	// Ambos editan el mismo evento y agregan una alarma al mismo archivo;
+	// la edición de a es la más reciente
+	filename := alarm.DailySchedule{Hour: 9, Minute: 0}.Filename()
+	edit := func(s Storage, notes, context string) {
+		e, err := s.GetEntry("u1", entry.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.Notes = notes
+		if err := s.UpdateEntry("u1", e); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SaveAlarm("u1", time.Now(), alarm.RecurrenceDaily, filename, alarm.NewAlarm(context, alarm.RecurrenceDaily)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	edit(b, "from bob", "bob's alarm")
+	time.Sleep(10 * time.Millisecond)
+	edit(a, "from alice", "alice's alarm")
+
+	if _, err := a.(GitVersioned).SyncRemote(""); err != nil {
+		t.Fatal(err)
+	}
+	result, err := b.(GitVersioned).SyncRemote("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Pulled == 0 || result.Pushed == 0 || len(result.Resolved) == 0 {
+		t.Errorf("SyncRemote() = %+v", result)
+	}
+
+	got, err := b.GetEntry("u1", entry.ID)
+	if err != nil || got.Notes != "from alice" {
+		t.Fatalf("evento tras el merge = %v, %v; want las notas de alice", got, err)
+	}
+	alarms, err := b.GetAlarms("u1", alarm.RecurrenceDaily, filename)
+	if err != nil || len(alarms) != 2 {
+		t.Errorf("alarmas tras el merge = %d, %v; want 2", len(alarms), err)
+	}
+	records, err := b.(HistoryKeeper).History("u1", entry.ID)
+	if err != nil || len(records) != 3 {
+		t.Errorf("historial tras el merge = %d registros, %v; want 3", len(records), err)
+	}
+
+	// El .md se regeneró y no quedaron marcas de conflicto
+	bDir := b.(*gitStorage).dataDir
+	mds, _ := filepath.Glob(filepath.Join(bDir, "users", "u1", "events", "*", "*", "*", "*.md"))
+	for _, md := range mds {
+		data, _ := os.ReadFile(md)
+		if strings.Contains(string(data), "<<<<<<<") || !strings.Contains(string(data), "from alice") {
+			t.Errorf("%s no se regeneró:\n%s", md, data)
+		}
+	}
+
+	// a recibe el merge
+	if _, err := a.(GitVersioned).SyncRemote(""); err != nil {
+		t.Fatal(err)
+	}
+	if alarms, _ := a.GetAlarms("u1", alarm.RecurrenceDaily, filename); len(alarms) != 2 {
+		t.Errorf("alarmas en a = %d, want 2", len(alarms))
+	}
+}
@@ -0,0 +1,494 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+// historyFilename es el journal de cambios, dentro de .state/history/
+const historyFilename = "journal.jsonl"
+
+// Operaciones registradas en el historial
+const (
+	HistoryAdd     = "add"
+	HistoryUpdate  = "update"
+	HistoryDelete  = "delete"
+	HistorySync    = "sync"
+	HistoryUndo    = "undo"
+	HistoryRestore = "restore"
+)
+
+// HistoryRecord es un cambio de un evento registrado en el historial
+type HistoryRecord struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Op      string    `json:"op"`
+	EntryID string    `json:"entry_id"`
+	// Before y After son el evento antes y después del cambio (nil si no
+	// existía o se eliminó). En overrides son la ocurrencia.
+	Before *calendar.Entry `json:"before,omitempty"`
+	After  *calendar.Entry `json:"after,omitempty"`
+	// Overrides son los overrides de ocurrencia de un evento recurrente antes
+	// del cambio, para poder recuperarlos si el cambio los eliminó
+	Overrides []*calendar.Entry `json:"overrides,omitempty"`
+	Command   string            `json:"command,omitempty"` // invocación de clical que hizo el cambio
+	Actor     string            `json:"actor,omitempty"`   // quién la ejecutó
+	Undoes    string            `json:"undoes,omitempty"`  // ID del cambio que deshace (op undo)
+}
+
+// Title retorna el título del evento en el cambio
+func (r *HistoryRecord) Title() string {
+	if r.After != nil {
+		return r.After.Title
+	}
+	if r.Before != nil {
+		return r.Before.Title
+	}
+	return ""
+}
+
+// HistoryKeeper lo implementan los Storage que registran el historial de
+// cambios de los eventos
+type HistoryKeeper interface {
+	// History retorna los cambios de un evento, del más viejo al más nuevo.
+	// La versión N de un evento es el resultado del cambio N (desde 1).
+	History(userID, entryID string) ([]*HistoryRecord, error)
+	// Undo deshace los últimos steps cambios del usuario que no se hayan
+	// deshecho, y retorna los cambios deshechos
+	Undo(userID string, steps int) ([]*HistoryRecord, error)
+	// Restore vuelve un evento a una versión de su historial
+	Restore(userID, entryID string, version int) (*HistoryRecord, error)
+}
+
+// historyStorage envuelve un Storage registrando cada SaveEntry, UpdateEntry
+// y DeleteEntry en un journal append-only por usuario
+// (users/<id>/.state/history/journal.jsonl) con el evento antes y después
+// del cambio. Funciona igual con cualquier backend.
+type historyStorage struct {
+	Storage
+	dataDir string
+	command string
+	actor   string
+}
+
+// Interfaces opcionales que historyStorage implementa o reenvía al storage envuelto
+var (
+	_ HistoryKeeper  = (*historyStorage)(nil)
+	_ MarkdownSyncer = (*historyStorage)(nil)
+	_ TrashBin       = (*historyStorage)(nil)
+	_ io.Closer      = (*historyStorage)(nil)
+)
+
+// WithHistory retorna s registrando el historial de cambios de los eventos.
+// command y actor identifican la invocación y quién la hizo.
+func WithHistory(s Storage, dataDir, command, actor string) Storage {
+	return &historyStorage{Storage: s, dataDir: dataDir, command: command, actor: actor}
+}
+
+// Close cierra el storage envuelto
+func (h *historyStorage) Close() error {
+	return Close(h.Storage)
+}
+
+// historyPath retorna el journal de un usuario
+func (h *historyStorage) historyPath(userID string) string {
+	return filepath.Join(getStateDir(h.dataDir, userID), "history", historyFilename)
+}
+
+// snapshot retorna el estado actual de un evento (o de una ocurrencia) y sus
+// overrides; nil si no existe
+func (h *historyStorage) snapshot(userID string, entry *calendar.Entry) (*calendar.Entry, []*calendar.Entry) {
+	if entry.IsOccurrence() {
+		occ, err := h.Storage.GetOccurrence(userID, entry.ID, *entry.RecurrenceID)
+		if err != nil {
+			return nil, nil
+		}
+		return occ, nil
+	}
+
+	current, err := h.Storage.GetEntry(userID, entry.ID)
+	if err != nil {
+		return nil, nil
+	}
+	var overrides []*calendar.Entry
+	if current.IsRecurring() {
+		overrides, _ = h.Storage.ListOverrides(userID, entry.ID)
+	}
+	return current, overrides
+}
+
+// record agrega un cambio al journal. Los eventos se serializan en el
+// momento, así que cambios posteriores a los punteros no lo afectan.
+func (h *historyStorage) record(userID string, r *HistoryRecord) error {
+	r.ID = calendar.GenerateID()
+	r.Time = time.Now()
+	r.Command = h.command
+	r.Actor = h.actor
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("error serializando historial: %w", err)
+	}
+
+	path := h.historyPath(userID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creando directorio de historial: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("error abriendo historial: %w", err)
+	}
+	defer f.Close()
+
+	// Si una escritura anterior se cortó a mitad de línea, empezar una nueva
+	if info, err := f.Stat(); err == nil && info.Size() > 0 && !endsWithNewline(path, info.Size()) {
+		data = append([]byte("\n"), data...)
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("error escribiendo historial: %w", err)
+	}
+	return f.Sync()
+}
+
+// endsWithNewline indica si el archivo de tamaño size termina en "\n"
+func endsWithNewline(path string, size int64) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	b := make([]byte, 1)
+	if _, err := f.ReadAt(b, size-1); err != nil {
+		return false
+	}
+	return b[0] == '\n'
+}
+
+// readHistory lee el journal de un usuario. Las líneas que no se pueden
+// parsear (una escritura cortada) se ignoran.
+func (h *historyStorage) readHistory(userID string) ([]*HistoryRecord, error) {
+	f, err := os.Open(h.historyPath(userID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error leyendo historial: %w", err)
+	}
+	defer f.Close()
+
+	var records []*HistoryRecord
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var r HistoryRecord
+			if json.Unmarshal(line, &r) == nil && r.ID != "" {
+				records = append(records, &r)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo historial: %w", err)
+		}
+	}
+
+	return records, nil
+}
+
+func (h *historyStorage) SaveEntry(userID string, entry *calendar.Entry) error {
+	before, overrides := h.snapshot(userID, entry)
+	if err := h.Storage.SaveEntry(userID, entry); err != nil {
+		return err
+	}
+
+	op := HistoryAdd
+	if before != nil {
+		op = HistoryUpdate
+	}
+	return h.record(userID, &HistoryRecord{Op: op, EntryID: entry.ID, Before: before, After: entry, Overrides: overrides})
+}
+
+func (h *historyStorage) UpdateEntry(userID string, entry *calendar.Entry) error {
+	before, overrides := h.snapshot(userID, entry)
+	if err := h.Storage.UpdateEntry(userID, entry); err != nil {
+		return err
+	}
+	return h.record(userID, &HistoryRecord{Op: HistoryUpdate, EntryID: entry.ID, Before: before, After: entry, Overrides: overrides})
+}
+
+func (h *historyStorage) DeleteEntry(userID, entryID string) error {
+	before, overrides := h.snapshot(userID, &calendar.Entry{ID: entryID})
+	if err := h.Storage.DeleteEntry(userID, entryID); err != nil {
+		return err
+	}
+	return h.record(userID, &HistoryRecord{Op: HistoryDelete, EntryID: entryID, Before: before, Overrides: overrides})
+}
+
+// RenameUser mueve también el historial (con el backend de filesystem ya
+// se movió junto con el resto del directorio del usuario)
+func (h *historyStorage) RenameUser(oldID, newID string) error {
+	if err := h.Storage.RenameUser(oldID, newID); err != nil {
+		return err
+	}
+	return moveUserSubdir(h.dataDir, oldID, newID, filepath.Join(".state", "history"))
+}
+
+// moveUserSubdir mueve un subdirectorio de datos de un usuario a otro ID, si
+// existe. Lo usan los wrappers que guardan archivos propios en el directorio
+// del usuario con cualquier backend.
+func moveUserSubdir(dataDir, oldID, newID, sub string) error {
+	return moveDir(filepath.Join(getUserDir(dataDir, oldID), sub), filepath.Join(getUserDir(dataDir, newID), sub))
+}
+
+// moveDir mueve oldDir a newDir (creando sus padres), si oldDir existe
+func moveDir(oldDir, newDir string) error {
+	if _, err := os.Stat(oldDir); err != nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(newDir), 0755); err != nil {
+		return fmt.Errorf("error creando directorio: %w", err)
+	}
+	if err := os.Rename(oldDir, newDir); err != nil {
+		return fmt.Errorf("error moviendo %s: %w", oldDir, err)
+	}
+	return nil
+}
+
+// removeUserSubdir elimina un subdirectorio de datos de un usuario y los
+// directorios que lo contienen, si quedaron vacíos
+func removeUserSubdir(dataDir, userID, sub string) error {
+	userDir := getUserDir(dataDir, userID)
+	dir := filepath.Join(userDir, sub)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	for dir = filepath.Dir(dir); strings.HasPrefix(dir, userDir); dir = filepath.Dir(dir) {
+		os.Remove(dir) // solo si quedó vacío
+	}
+	return nil
+}
+
+// DeleteUser elimina también el historial del usuario
+func (h *historyStorage) DeleteUser(userID string) error {
+	if err := h.Storage.DeleteUser(userID); err != nil {
+		return err
+	}
+	return removeUserSubdir(h.dataDir, userID, filepath.Join(".state", "history"))
+}
+
+// SyncMarkdown registra como cambios (op sync) los eventos importados desde
+// su Markdown
+func (h *historyStorage) SyncMarkdown(userID string, opts SyncOptions) (*SyncResult, error) {
+	syncer, ok := h.Storage.(MarkdownSyncer)
+	if !ok {
+		return nil, fmt.Errorf("el backend de storage no guarda archivos Markdown")
+	}
+	if opts.DryRun {
+		return syncer.SyncMarkdown(userID, opts)
+	}
+
+	// Un dry run indica qué eventos se van a importar, para guardar su estado anterior
+	preview, err := syncer.SyncMarkdown(userID, SyncOptions{DryRun: true, Prefer: opts.Prefer})
+	if err != nil {
+		return nil, err
+	}
+	type state struct {
+		before    *calendar.Entry
+		overrides []*calendar.Entry
+	}
+	before := make(map[string]state)
+	for _, item := range preview.Imported {
+		b, o := h.snapshot(userID, item.Entry)
+		before[item.Path] = state{b, o}
+	}
+
+	result, err := syncer.SyncMarkdown(userID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range result.Imported {
+		b, ok := before[item.Path]
+		if !ok {
+			continue
+		}
+		after, _ := h.snapshot(userID, item.Entry)
+		if err := h.record(userID, &HistoryRecord{Op: HistorySync, EntryID: item.ID, Before: b.before, After: after, Overrides: b.overrides}); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// trashBin retorna el Storage envuelto como TrashBin
+func (h *historyStorage) trashBin() (TrashBin, error) {
+	bin, ok := h.Storage.(TrashBin)
+	if !ok {
+		return nil, fmt.Errorf("la papelera no está habilitada")
+	}
+	return bin, nil
+}
+
+func (h *historyStorage) ListTrash(userID string) ([]*TrashItem, error) {
+	bin, err := h.trashBin()
+	if err != nil {
+		return nil, err
+	}
+	return bin.ListTrash(userID)
+}
+
+// RestoreTrash registra como restore los eventos recuperados de la papelera
+func (h *historyStorage) RestoreTrash(userID, id string) (*TrashItem, error) {
+	bin, err := h.trashBin()
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := bin.RestoreTrash(userID, id)
+	if err != nil || item.Kind != TrashEvent {
+		return item, err
+	}
+	return item, h.record(userID, &HistoryRecord{Op: HistoryRestore, EntryID: item.ID, After: item.Entry})
+}
+
+func (h *historyStorage) PurgeTrash(userID string, before time.Time) (int, error) {
+	bin, err := h.trashBin()
+	if err != nil {
+		return 0, err
+	}
+	return bin.PurgeTrash(userID, before)
+}
+
+func (h *historyStorage) History(userID, entryID string) ([]*HistoryRecord, error) {
+	records, err := h.readHistory(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*HistoryRecord
+	for _, r := range records {
+		if r.EntryID == entryID {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (h *historyStorage) Undo(userID string, steps int) ([]*HistoryRecord, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("la cantidad de pasos debe ser mayor a 0")
+	}
+
+	records, err := h.readHistory(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	undone := make(map[string]bool)
+	for _, r := range records {
+		if r.Undoes != "" {
+			undone[r.Undoes] = true
+		}
+	}
+
+	var result []*HistoryRecord
+	for i := len(records) - 1; i >= 0 && len(result) < steps; i-- {
+		r := records[i]
+		if r.Op == HistoryUndo || undone[r.ID] {
+			continue
+		}
+
+		if err := h.apply(userID, r.EntryID, r.Before, r.Overrides, HistoryUndo, r.ID); err != nil {
+			return result, fmt.Errorf("error deshaciendo el cambio %s (%s %s): %w", r.ID, r.Op, r.Title(), err)
+		}
+		result = append(result, r)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no hay cambios para deshacer")
+	}
+	return result, nil
+}
+
+func (h *historyStorage) Restore(userID, entryID string, version int) (*HistoryRecord, error) {
+	records, err := h.History(userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if version < 1 || version > len(records) {
+		return nil, fmt.Errorf("versión inexistente: %d (el evento %s tiene %d)", version, entryID, len(records))
+	}
+
+	r := records[version-1]
+	if r.After == nil {
+		return nil, fmt.Errorf("la versión %d es la eliminación del evento", version)
+	}
+
+	if err := h.apply(userID, entryID, r.After, nil, HistoryRestore, ""); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// apply deja el evento entryID en el estado target (nil: eliminado),
+// recupera los overrides indicados y lo registra con la operación op
+func (h *historyStorage) apply(userID, entryID string, target *calendar.Entry, overrides []*calendar.Entry, op, undoes string) error {
+	probe := target
+	if probe == nil {
+		probe = &calendar.Entry{ID: entryID}
+	}
+	before, beforeOverrides := h.snapshot(userID, probe)
+
+	switch {
+	case target == nil:
+		if before != nil {
+			if err := h.Storage.DeleteEntry(userID, entryID); err != nil {
+				return err
+			}
+		}
+	case target.IsOccurrence():
+		target.UserID = userID
+		if err := h.Storage.SaveEntry(userID, target); err != nil {
+			return err
+		}
+	default:
+		target.UserID = userID
+		save := h.Storage.UpdateEntry
+		if before == nil {
+			save = h.Storage.SaveEntry
+		}
+		if err := save(userID, target); err != nil {
+			return err
+		}
+		for _, o := range overrides {
+			if !target.HasOccurrence(*o.RecurrenceID) {
+				continue
+			}
+			o.UserID = userID
+			if err := h.Storage.SaveEntry(userID, o); err != nil {
+				return err
+			}
+		}
+	}
+
+	return h.record(userID, &HistoryRecord{
+		Op:        op,
+		EntryID:   entryID,
+		Before:    before,
+		After:     target,
+		Overrides: beforeOverrides,
+		Undoes:    undoes,
+	})
+}
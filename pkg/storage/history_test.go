@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+func newHistoryTestStorage(t *testing.T, backend string) (Storage, HistoryKeeper) {
+	base, dir := newTestStorage(t, backend)
+	s := WithHistory(base, dir, "clical test", "tester")
+	return s, s.(HistoryKeeper)
+}
+
+func TestHistoryUndo(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, keeper := newHistoryTestStorage(t, backend)
+		start := time.Date(2025, 11, 21, 14, 0, 0, 0, time.UTC)
+
+		entry := calendar.NewEntry("u1", "Client meeting", start, 60)
+		if err := s.SaveEntry("u1", entry); err != nil {
+			t.Fatal(err)
+		}
+
+		edited := *entry
+		edited.Title = "Client lunch"
+		edited.DateTime = start.Add(-2 * time.Hour)
+		if err := s.UpdateEntry("u1", &edited); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.DeleteEntry("u1", entry.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := keeper.History("u1", entry.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ops []string
+		for _, r := range records {
+			ops = append(ops, r.Op)
+			if r.Command != "clical test" || r.Actor != "tester" {
+				t.Errorf("comando/actor = %q %q", r.Command, r.Actor)
+			}
+		}
+		if len(ops) != 3 || ops[0] != HistoryAdd || ops[1] != HistoryUpdate || ops[2] != HistoryDelete {
+			t.Fatalf("ops = %v", ops)
+		}
+		if records[1].Before.Title != "Client meeting" || records[1].After.Title != "Client lunch" {
+			t.Errorf("update before/after = %q/%q", records[1].Before.Title, records[1].After.Title)
+		}
+
+		// Deshacer el borrado recupera la versión editada
+		if _, err := keeper.Undo("u1", 1); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.GetEntry("u1", entry.ID)
+		if err != nil || got.Title != "Client lunch" {
+			t.Fatalf("tras deshacer el borrado: %v, %v", got, err)
+		}
+
+		// Los dos cambios siguientes son la edición y el alta
+		undone, err := keeper.Undo("u1", 1)
+		if err != nil || len(undone) != 1 || undone[0].Op != HistoryUpdate {
+			t.Fatalf("Undo() = %v, %v", undone, err)
+		}
+		got, _ = s.GetEntry("u1", entry.ID)
+		if got.Title != "Client meeting" || !got.DateTime.Equal(start) {
+			t.Errorf("tras deshacer la edición: %q %v", got.Title, got.DateTime)
+		}
+
+		if _, err := keeper.Undo("u1", 5); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.GetEntry("u1", entry.ID); err == nil {
+			t.Error("deshacer el alta no eliminó el evento")
+		}
+		if _, err := keeper.Undo("u1", 1); err == nil {
+			t.Error("Undo() sin cambios pendientes no falló")
+		}
+
+		// restore trae de vuelta cualquier versión, aunque el evento no exista
+		if _, err := keeper.Restore("u1", entry.ID, 2); err != nil {
+			t.Fatal(err)
+		}
+		got, err = s.GetEntry("u1", entry.ID)
+		if err != nil || got.Title != "Client lunch" {
+			t.Errorf("tras restore: %v, %v", got, err)
+		}
+		if _, err := keeper.Restore("u1", entry.ID, 3); err == nil {
+			t.Error("Restore() de una eliminación no falló")
+		}
+	})
+}
+
+func TestHistoryUndoRestoresOverrides(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, keeper := newHistoryTestStorage(t, backend)
+		start := time.Date(2025, 11, 17, 10, 0, 0, 0, time.UTC)
+
+		weekly := calendar.NewEntry("u1", "Weekly", start, 30)
+		weekly.RRule, _ = calendar.ParseRecurrenceRule("FREQ=WEEKLY")
+		if err := s.SaveEntry("u1", weekly); err != nil {
+			t.Fatal(err)
+		}
+		occ := weekly.Occurrence(start.AddDate(0, 0, 7))
+		occ.Title = "Weekly (moved)"
+		if err := s.SaveEntry("u1", occ); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := s.DeleteEntry("u1", weekly.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := keeper.Undo("u1", 1); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := s.GetOccurrence("u1", weekly.ID, start.AddDate(0, 0, 7))
+		if err != nil || got.Title != "Weekly (moved)" {
+			t.Errorf("override tras deshacer el borrado = %v, %v", got, err)
+		}
+	})
+}
+
+func TestHistoryTornLine(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, keeper := newHistoryTestStorage(t, backend)
+		h := s.(*historyStorage)
+
+		entry := calendar.NewEntry("u1", "A", time.Date(2025, 11, 21, 14, 0, 0, 0, time.UTC), 60)
+		if err := s.SaveEntry("u1", entry); err != nil {
+			t.Fatal(err)
+		}
+
+		// Una escritura cortada a mitad de línea no rompe el journal
+		f, err := os.OpenFile(h.historyPath("u1"), os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(`{"id":"x","op":"upd`)
+		f.Close()
+
+		entry.Title = "B"
+		if err := s.UpdateEntry("u1", entry); err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := keeper.History("u1", entry.ID)
+		if err != nil || len(records) != 2 {
+			t.Fatalf("History() = %d registros, %v", len(records), err)
+		}
+	})
+}
@@ -0,0 +1,411 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/user"
+)
+
+// lockRetry es el intervalo entre intentos de tomar un lock ocupado
+const lockRetry = 25 * time.Millisecond
+
+// ErrLocked indica que otro proceso tiene el lock de un usuario
+var ErrLocked = errors.New("datos del usuario en uso por otro proceso de clical")
+
+// lockedStorage envuelve un Storage tomando un lock de archivo (flock) por
+// usuario alrededor de cada operación que modifica datos y de CheckAlarms,
+// para que invocaciones concurrentes de clical (por ejemplo `alarm check`
+// desde cron mientras se agregan eventos) no pisen sus read-modify-write.
+// Las lecturas no toman el lock.
+//
+// No embebe el Storage: cada método se reenvía explícitamente, así un método
+// nuevo de Storage (o de las interfaces opcionales) no compila hasta decidir
+// si toma el lock, en lugar de quedar sin lock en silencio.
+type lockedStorage struct {
+	inner   Storage
+	dir     string // <dataDir>/.locks
+	timeout time.Duration
+}
+
+// Interfaces opcionales que lockedStorage reenvía al storage envuelto
+var (
+	_ Storage        = (*lockedStorage)(nil)
+	_ MarkdownSyncer = (*lockedStorage)(nil)
+	_ HistoryKeeper  = (*lockedStorage)(nil)
+	_ TrashBin       = (*lockedStorage)(nil)
+	_ GitVersioned   = (*lockedStorage)(nil)
+	_ io.Closer      = (*lockedStorage)(nil)
+)
+
+// WithLocking retorna s con locking entre procesos por usuario. Los locks
+// son archivos en <dataDir>/.locks; timeout es cuánto se espera un lock
+// ocupado antes de fallar con ErrLocked (0 o negativo: no esperar).
+func WithLocking(s Storage, dataDir string, timeout time.Duration) Storage {
+	return &lockedStorage{
+		inner:   s,
+		dir:     filepath.Join(dataDir, ".locks"),
+		timeout: timeout,
+	}
+}
+
+// Close cierra el storage envuelto; no toma el lock
+func (l *lockedStorage) Close() error {
+	return Close(l.inner)
+}
+
+// lock toma el lock de los usuarios indicados (en orden, para que dos
+// procesos que bloquean los mismos usuarios no se traben entre sí) y
+// retorna la función que los libera
+func (l *lockedStorage) lock(userIDs ...string) (func(), error) {
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creando directorio de locks: %w", err)
+	}
+
+	ids := append([]string(nil), userIDs...)
+	sort.Strings(ids)
+
+	var files []*os.File
+	unlock := func() {
+		for i := len(files) - 1; i >= 0; i-- {
+			unlockFile(files[i])
+			files[i].Close()
+		}
+	}
+
+	for i, id := range ids {
+		if i > 0 && id == ids[i-1] {
+			continue
+		}
+		f, err := l.lockUser(id)
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	return unlock, nil
+}
+
+// lockUser abre el archivo de lock de un usuario y espera hasta timeout a obtenerlo
+func (l *lockedStorage) lockUser(userID string) (*os.File, error) {
+	return acquireLock(filepath.Join(l.dir, userID+".lock"), "usuario "+userID, l.timeout)
+}
+
+// acquireLock abre el archivo de lock path y espera hasta timeout a
+// obtenerlo. what describe lo que protege el lock, para el error.
+func acquireLock(path, what string, timeout time.Duration) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo lock %s: %w", path, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("error tomando lock %s: %w", path, err)
+		}
+		if ok {
+			return f, nil
+		}
+		if !time.Now().Before(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%w: %s (se esperó %s; lock: %s)", ErrLocked, what, timeout, path)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// withLock ejecuta fn con el lock de los usuarios indicados
+func (l *lockedStorage) withLock(fn func() error, userIDs ...string) error {
+	unlock, err := l.lock(userIDs...)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
+
+// Lecturas: se reenvían sin tomar el lock
+
+func (l *lockedStorage) GetEntry(userID, entryID string) (*calendar.Entry, error) {
+	return l.inner.GetEntry(userID, entryID)
+}
+
+func (l *lockedStorage) ListEntries(userID string, filter *calendar.Filter) ([]*calendar.Entry, error) {
+	return l.inner.ListEntries(userID, filter)
+}
+
+func (l *lockedStorage) GetOccurrence(userID, entryID string, recurrenceID time.Time) (*calendar.Entry, error) {
+	return l.inner.GetOccurrence(userID, entryID, recurrenceID)
+}
+
+func (l *lockedStorage) ListOverrides(userID, entryID string) ([]*calendar.Entry, error) {
+	return l.inner.ListOverrides(userID, entryID)
+}
+
+func (l *lockedStorage) GetUser(userID string) (*user.User, error) {
+	return l.inner.GetUser(userID)
+}
+
+func (l *lockedStorage) ListUsers() ([]*user.User, error) {
+	return l.inner.ListUsers()
+}
+
+func (l *lockedStorage) ArchiveUser(userID string, w io.Writer) error {
+	return l.inner.ArchiveUser(userID, w)
+}
+
+func (l *lockedStorage) GetReportState(userID string) (*ReportState, error) {
+	return l.inner.GetReportState(userID)
+}
+
+func (l *lockedStorage) GetAlarms(userID string, recurrence alarm.Recurrence, filename string) ([]*alarm.Alarm, error) {
+	return l.inner.GetAlarms(userID, recurrence, filename)
+}
+
+func (l *lockedStorage) ListActiveAlarms(userID string) ([]*alarm.Alarm, error) {
+	return l.inner.ListActiveAlarms(userID)
+}
+
+func (l *lockedStorage) ListPastAlarms(userID string) ([]*alarm.Alarm, error) {
+	return l.inner.ListPastAlarms(userID)
+}
+
+func (l *lockedStorage) ListUnackedAlarms(userID string) ([]*alarm.Alarm, error) {
+	return l.inner.ListUnackedAlarms(userID)
+}
+
+func (l *lockedStorage) DumpUser(userID string) (*UserDump, error) {
+	return l.inner.DumpUser(userID)
+}
+
+// Escrituras: toman el lock de los usuarios afectados
+
+func (l *lockedStorage) SaveEntry(userID string, entry *calendar.Entry) error {
+	return l.withLock(func() error { return l.inner.SaveEntry(userID, entry) }, userID)
+}
+
+func (l *lockedStorage) DeleteEntry(userID, entryID string) error {
+	return l.withLock(func() error { return l.inner.DeleteEntry(userID, entryID) }, userID)
+}
+
+func (l *lockedStorage) UpdateEntry(userID string, entry *calendar.Entry) error {
+	return l.withLock(func() error { return l.inner.UpdateEntry(userID, entry) }, userID)
+}
+
+func (l *lockedStorage) SaveUser(u *user.User) error {
+	return l.withLock(func() error { return l.inner.SaveUser(u) }, u.ID)
+}
+
+func (l *lockedStorage) DeleteUser(userID string) error {
+	return l.withLock(func() error { return l.inner.DeleteUser(userID) }, userID)
+}
+
+func (l *lockedStorage) RenameUser(oldID, newID string) error {
+	return l.withLock(func() error { return l.inner.RenameUser(oldID, newID) }, oldID, newID)
+}
+
+func (l *lockedStorage) SaveReportState(userID string, state *ReportState) error {
+	return l.withLock(func() error { return l.inner.SaveReportState(userID, state) }, userID)
+}
+
+func (l *lockedStorage) SaveAlarm(userID string, alarmTime time.Time, recurrence alarm.Recurrence, filename string, alm *alarm.Alarm) error {
+	return l.withLock(func() error {
+		return l.inner.SaveAlarm(userID, alarmTime, recurrence, filename, alm)
+	}, userID)
+}
+
+func (l *lockedStorage) DeleteAlarms(userID string, recurrence alarm.Recurrence, filename string) error {
+	return l.withLock(func() error { return l.inner.DeleteAlarms(userID, recurrence, filename) }, userID)
+}
+
+func (l *lockedStorage) CheckAlarms(userID string, at time.Time) ([]*alarm.Alarm, error) {
+	var alarms []*alarm.Alarm
+	err := l.withLock(func() error {
+		var err error
+		alarms, err = l.inner.CheckAlarms(userID, at)
+		return err
+	}, userID)
+	return alarms, err
+}
+
+func (l *lockedStorage) CancelAlarm(userID string, alarmID string) error {
+	return l.withLock(func() error { return l.inner.CancelAlarm(userID, alarmID) }, userID)
+}
+
+func (l *lockedStorage) MoveAlarmsToPast(userID string, recurrence alarm.Recurrence, filename string) error {
+	return l.withLock(func() error { return l.inner.MoveAlarmsToPast(userID, recurrence, filename) }, userID)
+}
+
+func (l *lockedStorage) AckAlarm(userID string, alarmID string) error {
+	return l.withLock(func() error { return l.inner.AckAlarm(userID, alarmID) }, userID)
+}
+
+func (l *lockedStorage) SnoozeAlarm(userID string, alarmID string, at time.Time, d time.Duration) (*alarm.Alarm, error) {
+	var snoozed *alarm.Alarm
+	err := l.withLock(func() error {
+		var err error
+		snoozed, err = l.inner.SnoozeAlarm(userID, alarmID, at, d)
+		return err
+	}, userID)
+	return snoozed, err
+}
+
+func (l *lockedStorage) NagAlarms(userID string, at time.Time, every time.Duration) ([]*alarm.Alarm, error) {
+	var alarms []*alarm.Alarm
+	err := l.withLock(func() error {
+		var err error
+		alarms, err = l.inner.NagAlarms(userID, at, every)
+		return err
+	}, userID)
+	return alarms, err
+}
+
+func (l *lockedStorage) RestoreUser(dump *UserDump) error {
+	return l.withLock(func() error { return l.inner.RestoreUser(dump) }, dump.User.ID)
+}
+
+// SyncMarkdown sincroniza los .md del usuario con el lock tomado, si el
+// backend los tiene
+func (l *lockedStorage) SyncMarkdown(userID string, opts SyncOptions) (*SyncResult, error) {
+	syncer, ok := l.inner.(MarkdownSyncer)
+	if !ok {
+		return nil, fmt.Errorf("el backend de storage no guarda archivos Markdown")
+	}
+
+	var result *SyncResult
+	err := l.withLock(func() error {
+		var err error
+		result, err = syncer.SyncMarkdown(userID, opts)
+		return err
+	}, userID)
+	return result, err
+}
+
+// History lee el historial de un evento; no toma el lock
+func (l *lockedStorage) History(userID, entryID string) ([]*HistoryRecord, error) {
+	keeper, ok := l.inner.(HistoryKeeper)
+	if !ok {
+		return nil, fmt.Errorf("el historial de cambios no está habilitado")
+	}
+	return keeper.History(userID, entryID)
+}
+
+// Undo deshace cambios con el lock del usuario tomado
+func (l *lockedStorage) Undo(userID string, steps int) ([]*HistoryRecord, error) {
+	keeper, ok := l.inner.(HistoryKeeper)
+	if !ok {
+		return nil, fmt.Errorf("el historial de cambios no está habilitado")
+	}
+
+	var records []*HistoryRecord
+	err := l.withLock(func() error {
+		var err error
+		records, err = keeper.Undo(userID, steps)
+		return err
+	}, userID)
+	return records, err
+}
+
+// Restore vuelve un evento a una versión con el lock del usuario tomado
+func (l *lockedStorage) Restore(userID, entryID string, version int) (*HistoryRecord, error) {
+	keeper, ok := l.inner.(HistoryKeeper)
+	if !ok {
+		return nil, fmt.Errorf("el historial de cambios no está habilitado")
+	}
+
+	var record *HistoryRecord
+	err := l.withLock(func() error {
+		var err error
+		record, err = keeper.Restore(userID, entryID, version)
+		return err
+	}, userID)
+	return record, err
+}
+
+// ListTrash lista la papelera; no toma el lock
+func (l *lockedStorage) ListTrash(userID string) ([]*TrashItem, error) {
+	bin, ok := l.inner.(TrashBin)
+	if !ok {
+		return nil, fmt.Errorf("la papelera no está habilitada")
+	}
+	return bin.ListTrash(userID)
+}
+
+// RestoreTrash recupera un elemento de la papelera con el lock del usuario tomado
+func (l *lockedStorage) RestoreTrash(userID, id string) (*TrashItem, error) {
+	bin, ok := l.inner.(TrashBin)
+	if !ok {
+		return nil, fmt.Errorf("la papelera no está habilitada")
+	}
+
+	var item *TrashItem
+	err := l.withLock(func() error {
+		var err error
+		item, err = bin.RestoreTrash(userID, id)
+		return err
+	}, userID)
+	return item, err
+}
+
+// PurgeTrash purga la papelera con el lock del usuario tomado
+func (l *lockedStorage) PurgeTrash(userID string, before time.Time) (int, error) {
+	bin, ok := l.inner.(TrashBin)
+	if !ok {
+		return 0, fmt.Errorf("la papelera no está habilitada")
+	}
+
+	var purged int
+	err := l.withLock(func() error {
+		var err error
+		purged, err = bin.PurgeTrash(userID, before)
+		return err
+	}, userID)
+	return purged, err
+}
+
+// GitLog lee el log del directorio de datos; no toma el lock
+func (l *lockedStorage) GitLog(opts GitLogOptions) ([]*GitCommit, error) {
+	versioned, ok := l.inner.(GitVersioned)
+	if !ok {
+		return nil, fmt.Errorf("el versionado con git no está habilitado")
+	}
+	return versioned.GitLog(opts)
+}
+
+// SyncRemote sincroniza con el remoto con el lock de todos los usuarios
+// tomado, ya que el merge puede cambiar datos de cualquiera
+func (l *lockedStorage) SyncRemote(remote string) (*RemoteSyncResult, error) {
+	versioned, ok := l.inner.(GitVersioned)
+	if !ok {
+		return nil, fmt.Errorf("el versionado con git no está habilitado")
+	}
+
+	users, err := l.inner.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+
+	var result *RemoteSyncResult
+	err = l.withLock(func() error {
+		var err error
+		result, err = versioned.SyncRemote(remote)
+		return err
+	}, ids...)
+	return result, err
+}
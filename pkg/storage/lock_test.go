@@ -0,0 +1,249 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/user"
+)
+
+func newLockedTestStorage(t *testing.T, backend string, timeout time.Duration) (Storage, string) {
+	base, dir := newTestStorage(t, backend)
+	return WithLocking(base, dir, timeout), dir
+}
+
+func TestLockingTimeout(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, dir := newLockedTestStorage(t, backend, 50*time.Millisecond)
+
+		// Otro "proceso" (otro archivo abierto sobre el mismo lock) tiene el usuario
+		other := WithLocking(s.(*lockedStorage).inner, dir, 0).(*lockedStorage)
+		unlock, err := other.lock("u1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		alm := alarm.NewAlarm("reunión", alarm.RecurrenceDaily)
+		start := time.Now()
+		err = s.SaveAlarm("u1", time.Now(), alarm.RecurrenceDaily, "09-00", alm)
+		if !errors.Is(err, ErrLocked) {
+			t.Fatalf("SaveAlarm() error = %v, want ErrLocked", err)
+		}
+		if waited := time.Since(start); waited < 50*time.Millisecond {
+			t.Errorf("se esperó %s, want al menos el timeout", waited)
+		}
+
+		// El lock de un usuario no bloquea a los demás
+		if err := s.SaveAlarm("u2", time.Now(), alarm.RecurrenceDaily, "09-00", alm); err != nil {
+			t.Errorf("SaveAlarm(u2) error = %v", err)
+		}
+
+		unlock()
+		if err := s.SaveAlarm("u1", time.Now(), alarm.RecurrenceDaily, "09-00", alm); err != nil {
+			t.Errorf("SaveAlarm() tras liberar el lock error = %v", err)
+		}
+	})
+}
+
+func TestLockingConcurrentSaveAlarm(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newLockedTestStorage(t, backend, 5*time.Second)
+
+		// Cada goroutine usa su propio archivo de lock, como procesos distintos
+		const n = 20
+		var wg sync.WaitGroup
+		errs := make(chan error, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				alm := alarm.NewAlarm("reunión", alarm.RecurrenceDaily)
+				errs <- s.SaveAlarm("u1", time.Now(), alarm.RecurrenceDaily, "09-00", alm)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("SaveAlarm() error = %v", err)
+			}
+		}
+
+		alarms, err := s.GetAlarms("u1", alarm.RecurrenceDaily, "09-00")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(alarms) != n {
+			t.Errorf("alarmas guardadas = %d, want %d", len(alarms), n)
+		}
+	})
+}
+
+// readOnlyMethods son los métodos de Storage y de las interfaces opcionales
+// que no modifican datos: los wrappers los reenvían sin lock ni commit
+var readOnlyMethods = map[string]bool{
+	"GetEntry": true, "ListEntries": true, "GetOccurrence": true, "ListOverrides": true,
+	"GetUser": true, "ListUsers": true, "ArchiveUser": true, "GetReportState": true,
+	"GetAlarms": true, "ListActiveAlarms": true, "ListPastAlarms": true, "ListUnackedAlarms": true,
+	"DumpUser": true, "History": true, "ListTrash": true, "GitLog": true,
+	"Close": true,
+}
+
+// wrappedInterfaces son Storage y las interfaces opcionales que reenvían los wrappers
+var wrappedInterfaces = []reflect.Type{
+	reflect.TypeOf((*Storage)(nil)).Elem(),
+	reflect.TypeOf((*MarkdownSyncer)(nil)).Elem(),
+	reflect.TypeOf((*HistoryKeeper)(nil)).Elem(),
+	reflect.TypeOf((*TrashBin)(nil)).Elem(),
+	reflect.TypeOf((*GitVersioned)(nil)).Elem(),
+	reflect.TypeOf((*io.Closer)(nil)).Elem(),
+}
+
+func TestLockingCoversMutations(t *testing.T) {
+	dir := t.TempDir()
+	fs, err := NewFilesystemStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.SaveUser(user.NewUser("u1", "Ana", "UTC")); err != nil {
+		t.Fatal(err)
+	}
+	chain := WithGit(WithHistory(WithTrash(fs, dir, 0), dir, "clical test", "tester"), dir, "clical test", "tester", 0)
+	s := WithLocking(chain, dir, 0)
+
+	// Otro proceso tiene el lock de u1: toda operación que modifica datos
+	// debe fallar con ErrLocked sin llegar al storage
+	unlock, err := WithLocking(fs, dir, 0).(*lockedStorage).lock("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	for _, iface := range wrappedInterfaces {
+		if !reflect.TypeOf(s).Implements(iface) {
+			t.Errorf("WithLocking() no implementa %s", iface.Name())
+			continue
+		}
+		for i := 0; i < iface.NumMethod(); i++ {
+			m := iface.Method(i)
+			if readOnlyMethods[m.Name] {
+				continue
+			}
+			if err := callWithTestArgs(reflect.ValueOf(s).MethodByName(m.Name), m.Type); !errors.Is(err, ErrLocked) {
+				t.Errorf("%s.%s no toma el lock: error = %v", iface.Name(), m.Name, err)
+			}
+		}
+	}
+}
+
+// callWithTestArgs llama a fn con argumentos del usuario u1 (y valores cero
+// para el resto) y retorna su error
+func callWithTestArgs(fn reflect.Value, ft reflect.Type) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	args := make([]reflect.Value, ft.NumIn())
+	for i := range args {
+		switch in := ft.In(i); in {
+		case reflect.TypeOf(""):
+			args[i] = reflect.ValueOf("u1")
+		case reflect.TypeOf(&user.User{}):
+			args[i] = reflect.ValueOf(user.NewUser("u1", "Ana", "UTC"))
+		case reflect.TypeOf(&UserDump{}):
+			args[i] = reflect.ValueOf(&UserDump{User: user.NewUser("u1", "Ana", "UTC")})
+		default:
+			args[i] = reflect.Zero(in)
+		}
+	}
+
+	out := fn.Call(args)
+	err, _ = out[len(out)-1].Interface().(error)
+	return err
+}
+
+// TestWrappersOverrideMutations verifica que los wrappers que actúan después
+// de cada cambio (commit de git, purga de la papelera) declaren todos los
+// métodos que modifican datos, en lugar de heredarlos sin ese paso del
+// Storage embebido
+func TestWrappersOverrideMutations(t *testing.T) {
+	declared := map[string]map[string]bool{}
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil {
+				continue
+			}
+			star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			if ident, ok := star.X.(*ast.Ident); ok {
+				if declared[ident.Name] == nil {
+					declared[ident.Name] = map[string]bool{}
+				}
+				declared[ident.Name][fn.Name.Name] = true
+			}
+		}
+	}
+
+	storageType := wrappedInterfaces[0]
+	for _, wrapper := range []string{"gitStorage", "trashStorage"} {
+		for i := 0; i < storageType.NumMethod(); i++ {
+			name := storageType.Method(i).Name
+			if !readOnlyMethods[name] && !declared[wrapper][name] {
+				t.Errorf("%s no redefine %s", wrapper, name)
+			}
+		}
+	}
+}
+
+func TestWrappersClose(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(BackendSQLite, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = WithLocking(WithHistory(WithTrash(s, dir, 0), dir, "clical test", ""), dir, time.Second)
+	if err := s.SaveUser(user.NewUser("u1", "Ana", "UTC")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cerrar a través de los wrappers cierra la base: SQLite integra el WAL
+	// y borra los archivos -wal y -shm
+	if err := Close(s); err != nil {
+		t.Fatal(err)
+	}
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if _, err := os.Stat(filepath.Join(dir, SQLiteFilename+suffix)); !os.IsNotExist(err) {
+			t.Errorf("%s%s sigue existiendo después de Close: %v", SQLiteFilename, suffix, err)
+		}
+	}
+}
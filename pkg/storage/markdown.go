@@ -2,6 +2,9 @@ package storage
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,8 +21,40 @@ func entryToMarkdown(entry *calendar.Entry) string {
 
 	// Metadata principal
 	md.WriteString(fmt.Sprintf("**Fecha:** %s  \n", entry.DateTime.Format("2006-01-02")))
-	md.WriteString(fmt.Sprintf("**Hora:** %s  \n", entry.DateTime.Format("15:04")))
-	md.WriteString(fmt.Sprintf("**Duration:** %d minutos  \n", entry.Duration))
+	if entry.AllDay {
+		md.WriteString("**Hora:** todo el día  \n")
+	} else {
+		md.WriteString(fmt.Sprintf("**Hora:** %s  \n", entry.DateTime.Format("15:04")))
+	}
+	if entry.EndDate != nil {
+		if entry.AllDay {
+			md.WriteString(fmt.Sprintf("**Hasta:** %s  \n", entry.EndDate.Format("2006-01-02")))
+		} else {
+			md.WriteString(fmt.Sprintf("**Hasta:** %s  \n", entry.EndDate.Format("2006-01-02 15:04")))
+		}
+	}
+	if entry.TZID != "" {
+		md.WriteString(fmt.Sprintf("**Zona horaria:** %s  \n", entry.TZID))
+	}
+	if !entry.AllDay && entry.EndDate == nil {
+		md.WriteString(fmt.Sprintf("**Duration:** %d minutos  \n", entry.Duration))
+	}
+
+	if entry.RRule != nil {
+		md.WriteString(fmt.Sprintf("**Recurrence:** %s  \n", entry.RRule.String()))
+	}
+
+	if len(entry.ExDates) > 0 {
+		exdates := make([]string, len(entry.ExDates))
+		for i, ex := range entry.ExDates {
+			exdates[i] = ex.Format("2006-01-02 15:04")
+		}
+		md.WriteString(fmt.Sprintf("**Excepciones:** %s  \n", strings.Join(exdates, ", ")))
+	}
+
+	if entry.RecurrenceID != nil {
+		md.WriteString(fmt.Sprintf("**Ocurrencia:** %s  \n", entry.RecurrenceID.Format("2006-01-02 15:04")))
+	}
 
 	if entry.Location != "" {
 		md.WriteString(fmt.Sprintf("**Location:** %s  \n", entry.Location))
@@ -33,6 +68,10 @@ func entryToMarkdown(entry *calendar.Entry) string {
 		md.WriteString(fmt.Sprintf("**Tags:** %s  \n", strings.Join(tags, " ")))
 	}
 
+	if len(entry.Reminders) > 0 {
+		md.WriteString(fmt.Sprintf("**Reminders:** %s  \n", strings.ReplaceAll(calendar.FormatReminders(entry.Reminders), ",", ", ")))
+	}
+
 	md.WriteString("\n")
 
 	// Notas
@@ -45,8 +84,13 @@ func entryToMarkdown(entry *calendar.Entry) string {
 	// Metadata adicional
 	if len(entry.Metadata) > 0 {
 		md.WriteString("## Metadata\n\n")
-		for k, v := range entry.Metadata {
-			md.WriteString(fmt.Sprintf("- **%s:** %s\n", k, v))
+		keys := make([]string, 0, len(entry.Metadata))
+		for k := range entry.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			md.WriteString(fmt.Sprintf("- **%s:** %s\n", k, entry.Metadata[k]))
 		}
 		md.WriteString("\n")
 	}
@@ -60,6 +104,215 @@ func entryToMarkdown(entry *calendar.Entry) string {
 	return md.String()
 }
 
+// mdFieldRe reconoce una línea "**Campo:** valor" del encabezado de un evento
+var mdFieldRe = regexp.MustCompile(`^\*\*([^*]+):\*\*\s*(.*?)\s*$`)
+
+// mdFooterRe reconoce una línea "*Campo: valor*" del pie de un evento
+var mdFooterRe = regexp.MustCompile(`^\*([^*:]+):\s*(.*?)\*\s*$`)
+
+// markdownToEntry parsea el Markdown de un evento (el formato que genera
+// entryToMarkdown, posiblemente editado a mano) sobre una copia de base, su
+// versión JSON. Los campos editables que faltan en el Markdown quedan vacíos;
+// el ID y la ocurrencia identifican el archivo y no se pueden cambiar, y las
+// fechas de creación y actualización se toman de base.
+func markdownToEntry(md string, base *calendar.Entry) (*calendar.Entry, error) {
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+
+	// Separar el pie (después del último ---)
+	body, footer := md, ""
+	if i := strings.LastIndex(md, "\n---\n"); i >= 0 {
+		body, footer = md[:i+1], md[i+len("\n---\n"):]
+	}
+
+	entry := *base
+	entry.Title = ""
+	entry.Location = ""
+	entry.Notes = ""
+	entry.Tags = []string{}
+	entry.Metadata = make(map[string]string)
+	entry.RRule = nil
+	entry.ExDates = nil
+	entry.TZID = ""
+	entry.AllDay = false
+	entry.EndDate = nil
+	entry.Reminders = nil
+
+	// Encabezado: título y campos hasta la primera sección
+	var date, hour, until, rrule, recurrenceID string
+	var exdates []string
+	lines := strings.Split(body, "\n")
+	n := 0
+	for ; n < len(lines); n++ {
+		line := strings.TrimSpace(lines[n])
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "## ") {
+			break
+		}
+		if strings.HasPrefix(line, "# ") {
+			entry.Title = strings.TrimSpace(line[2:])
+			continue
+		}
+
+		m := mdFieldRe.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("línea no reconocida: %q", line)
+		}
+		value := m[2]
+		switch m[1] {
+		case "Fecha":
+			date = value
+		case "Hora":
+			hour = value
+		case "Hasta":
+			until = value
+		case "Zona horaria":
+			entry.TZID = value
+		case "Duration":
+			minutes, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(value, "minutos")))
+			if err != nil {
+				return nil, fmt.Errorf("duración inválida: %q", value)
+			}
+			entry.Duration = minutes
+		case "Recurrence":
+			rrule = value
+		case "Excepciones":
+			for _, ex := range strings.Split(value, ",") {
+				if ex = strings.TrimSpace(ex); ex != "" {
+					exdates = append(exdates, ex)
+				}
+			}
+		case "Ocurrencia":
+			recurrenceID = value
+		case "Location":
+			entry.Location = value
+		case "Tags":
+			for _, tag := range strings.Fields(value) {
+				if tag = strings.TrimPrefix(tag, "#"); tag != "" {
+					entry.Tags = append(entry.Tags, tag)
+				}
+			}
+		case "Reminders":
+			reminders, err := calendar.ParseReminders(value)
+			if err != nil {
+				return nil, err
+			}
+			entry.Reminders = reminders
+		default:
+			return nil, fmt.Errorf("campo desconocido: %s", m[1])
+		}
+	}
+	if entry.Title == "" {
+		return nil, fmt.Errorf("falta el título (# Título)")
+	}
+
+	// Las fechas se leen en la zona del evento
+	loc := base.DateTime.Location()
+	if entry.TZID != "" {
+		tz, err := time.LoadLocation(entry.TZID)
+		if err != nil {
+			return nil, fmt.Errorf("zona horaria inválida: %s", entry.TZID)
+		}
+		loc = tz
+	}
+
+	if date == "" || hour == "" {
+		return nil, fmt.Errorf("faltan los campos Fecha y Hora")
+	}
+	entry.AllDay = hour == "todo el día"
+	var err error
+	if entry.AllDay {
+		entry.DateTime, err = time.ParseInLocation("2006-01-02", date, loc)
+	} else {
+		entry.DateTime, err = time.ParseInLocation("2006-01-02 15:04", date+" "+hour, loc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fecha u hora inválida: %s %s", date, hour)
+	}
+
+	if until != "" {
+		layout := "2006-01-02 15:04"
+		if entry.AllDay {
+			layout = "2006-01-02"
+		}
+		end, err := time.ParseInLocation(layout, until, loc)
+		if err != nil {
+			return nil, fmt.Errorf("fecha de fin inválida: %s", until)
+		}
+		entry.EndDate = &end
+	}
+
+	if rrule != "" {
+		entry.RRule, err = calendar.ParseRecurrenceRuleIn(rrule, loc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ex := range exdates {
+		t, err := time.ParseInLocation("2006-01-02 15:04", ex, loc)
+		if err != nil {
+			return nil, fmt.Errorf("excepción inválida: %s", ex)
+		}
+		entry.ExDates = append(entry.ExDates, t)
+	}
+
+	if (recurrenceID != "") != (base.RecurrenceID != nil) {
+		return nil, fmt.Errorf("no se puede agregar ni quitar el campo Ocurrencia")
+	}
+	if recurrenceID != "" {
+		t, err := time.ParseInLocation("2006-01-02 15:04", recurrenceID, loc)
+		if err != nil || !t.Equal(*base.RecurrenceID) {
+			return nil, fmt.Errorf("no se puede cambiar la ocurrencia de un override: %s", recurrenceID)
+		}
+	}
+
+	// Secciones
+	section := ""
+	var notes []string
+	for _, line := range lines[n:] {
+		switch strings.TrimSpace(line) {
+		case "## Notas":
+			section = "notas"
+			continue
+		case "## Metadata":
+			section = "metadata"
+			continue
+		}
+
+		switch section {
+		case "notas":
+			notes = append(notes, line)
+		case "metadata":
+			item := strings.TrimSpace(line)
+			if item == "" {
+				continue
+			}
+			m := mdFieldRe.FindStringSubmatch(strings.TrimPrefix(item, "- "))
+			if !strings.HasPrefix(item, "- ") || m == nil {
+				return nil, fmt.Errorf("línea de metadata inválida: %q", item)
+			}
+			entry.Metadata[m[1]] = m[2]
+		default:
+			if strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("sección desconocida: %q", line)
+			}
+		}
+	}
+	entry.Notes = strings.Trim(strings.Join(notes, "\n"), "\n")
+
+	// Pie: sólo se verifica el ID
+	for _, line := range strings.Split(footer, "\n") {
+		m := mdFooterRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m != nil && m[1] == "ID" && m[2] != base.ID {
+			return nil, fmt.Errorf("el ID %s no coincide con el del evento (%s)", m[2], base.ID)
+		}
+	}
+
+	return &entry, nil
+}
+
 // userToMarkdown convierte un User a formato Markdown
 func userToMarkdown(u *user.User) string {
 	var md strings.Builder
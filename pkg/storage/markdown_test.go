@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip("sin base de zonas horarias")
+	}
+	start := time.Date(2025, 11, 21, 14, 0, 0, 0, time.UTC)
+
+	plain := calendar.NewEntry("u1", "Client meeting", start, 90)
+	plain.Location = "Main Office"
+	plain.Tags = []string{"work", "client"}
+	plain.Notes = "Review Q4 proposal.\n\n- timeline\n- budget"
+	plain.Metadata = map[string]string{"room": "4B", "agenda": "Q4"}
+	plain.Reminders = []int{15, 24 * 60}
+
+	allDay := calendar.NewEntry("u1", "Vacaciones", start, 0)
+	allDay.AllDay = true
+	allDay.DateTime = calendar.StartOfDay(start)
+	allDay.EndDate = ptr(start.AddDate(0, 0, 4))
+
+	zoned := calendar.NewEntry("u1", "Viaje", start.In(madrid), 0)
+	zoned.TZID = "Europe/Madrid"
+	zoned.EndDate = ptr(start.Add(50 * time.Hour).In(madrid))
+
+	weekly := calendar.NewEntry("u1", "Weekly", start, 30)
+	weekly.RRule, _ = calendar.ParseRecurrenceRule("FREQ=WEEKLY;BYDAY=FR;COUNT=10")
+	weekly.ExDates = []time.Time{start.AddDate(0, 0, 7)}
+
+	occ := weekly.Occurrence(start.AddDate(0, 0, 14))
+	occ.Title = "Weekly (moved)"
+
+	for _, entry := range []*calendar.Entry{plain, allDay, zoned, weekly, occ} {
+		t.Run(entry.Title, func(t *testing.T) {
+			if err := prepareEntry(entry); err != nil {
+				t.Fatal(err)
+			}
+			md := entryToMarkdown(entry)
+
+			parsed, err := markdownToEntry(md, entry)
+			if err != nil {
+				t.Fatalf("markdownToEntry() error = %v\n%s", err, md)
+			}
+			if got := entryToMarkdown(parsed); got != md {
+				t.Errorf("round trip:\n%s\nwant:\n%s", got, md)
+			}
+			if !parsed.DateTime.Equal(entry.DateTime) || !parsed.EndTime().Equal(entry.EndTime()) {
+				t.Errorf("horario = %v - %v, want %v - %v", parsed.DateTime, parsed.EndTime(), entry.DateTime, entry.EndTime())
+			}
+			if parsed.Notes != entry.Notes || len(parsed.Metadata) != len(entry.Metadata) {
+				t.Errorf("notas/metadata = %q %v, want %q %v", parsed.Notes, parsed.Metadata, entry.Notes, entry.Metadata)
+			}
+		})
+	}
+}
+
+func TestMarkdownToEntryEdits(t *testing.T) {
+	start := time.Date(2025, 11, 21, 14, 0, 0, 0, time.UTC)
+	entry := calendar.NewEntry("u1", "Client meeting", start, 60)
+	entry.Location = "Main Office"
+	entry.Tags = []string{"work"}
+	md := entryToMarkdown(entry)
+
+	edit := func(old, new string) string { return strings.Replace(md, old, new, 1) }
+
+	t.Run("campos editados", func(t *testing.T) {
+		edited := edit("# Client meeting", "# Client lunch")
+		edited = strings.Replace(edited, "**Hora:** 14:00", "**Hora:** 12:30", 1)
+		edited = strings.Replace(edited, "**Location:** Main Office  \n", "", 1)
+		edited = strings.Replace(edited, "#work", "#work #food", 1)
+		edited = strings.Replace(edited, "\n---\n", "\n## Notas\n\nBring slides\n\n---\n", 1)
+
+		got, err := markdownToEntry(edited, entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Title != "Client lunch" || got.DateTime.Format("15:04") != "12:30" {
+			t.Errorf("título/hora = %q %s", got.Title, got.DateTime.Format("15:04"))
+		}
+		if got.Location != "" || len(got.Tags) != 2 || got.Notes != "Bring slides" {
+			t.Errorf("location/tags/notas = %q %v %q", got.Location, got.Tags, got.Notes)
+		}
+		if got.ID != entry.ID || !got.CreatedAt.Equal(entry.CreatedAt) {
+			t.Errorf("ID/creado cambiaron: %s %v", got.ID, got.CreatedAt)
+		}
+	})
+
+	errors := []struct {
+		name string
+		md   string
+	}{
+		{"ID distinto", edit("*ID: "+entry.ID+"*", "*ID: otro*")},
+		{"hora inválida", edit("**Hora:** 14:00", "**Hora:** 25:99")},
+		{"sin título", edit("# Client meeting", "")},
+		{"campo desconocido", edit("**Location:**", "**Lugar:**")},
+		{"ocurrencia agregada", edit("**Location:**", "**Ocurrencia:** 2025-11-21 14:00  \n**Location:**")},
+	}
+	for _, tt := range errors {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := markdownToEntry(tt.md, entry); err == nil {
+				t.Errorf("markdownToEntry() sin error para:\n%s", tt.md)
+			}
+		})
+	}
+}
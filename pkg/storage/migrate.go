@@ -0,0 +1,249 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/user"
+)
+
+// UserDump contiene todos los datos de un usuario en un formato común a
+// todos los backends. Se usa para migrar datos entre backends.
+type UserDump struct {
+	User *user.User `json:"user"`
+	// Entries incluye eventos simples, maestros recurrentes (sin expandir)
+	// y overrides de ocurrencia
+	Entries     []*calendar.Entry `json:"entries"`
+	AlarmFiles  []AlarmFile       `json:"alarm_files"`
+	ReportState *ReportState      `json:"report_state,omitempty"` // nil si nunca se generó un reporte
+	// UnackedAlarms son las alarmas disparadas que esperan confirmación
+	UnackedAlarms []*alarm.Alarm `json:"unacked_alarms,omitempty"`
+}
+
+// AlarmFile es un archivo de alarmas: la lista de alarmas de un schedule
+type AlarmFile struct {
+	Past       bool             `json:"past"`
+	Recurrence alarm.Recurrence `json:"recurrence"`
+	Filename   string           `json:"filename"`
+	Alarms     []*alarm.Alarm   `json:"alarms"`
+}
+
+// MigrateResult resume una migración
+type MigrateResult struct {
+	Users      int
+	Entries    int
+	AlarmFiles int
+}
+
+// migrateStagingSuffix se agrega al ID de un usuario para restaurarlo en el
+// destino antes de reemplazar al usuario existente
+const migrateStagingSuffix = ".migrating"
+
+// Migrate copia todos los usuarios de src a dst y verifica que los datos
+// en dst sean idénticos a los de origen. Si overwrite es false, falla sin
+// escribir nada cuando algún usuario ya existe en dst.
+//
+// Cada usuario se restaura y verifica primero con un ID temporal; el usuario
+// existente en dst solo se reemplaza cuando la copia está completa.
+func Migrate(src, dst Storage, overwrite bool) (*MigrateResult, error) {
+	users, err := src.ListUsers()
+	if err != nil {
+		return nil, fmt.Errorf("error listando usuarios: %w", err)
+	}
+
+	if !overwrite {
+		for _, u := range users {
+			if _, err := dst.GetUser(u.ID); err == nil {
+				return nil, fmt.Errorf("el usuario %s ya existe en el destino", u.ID)
+			}
+		}
+	}
+
+	result := &MigrateResult{}
+	for _, u := range users {
+		dump, err := src.DumpUser(u.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error exportando usuario %s: %w", u.ID, err)
+		}
+
+		if err := migrateUser(dst, dump); err != nil {
+			return nil, err
+		}
+
+		result.Users++
+		result.Entries += len(dump.Entries)
+		result.AlarmFiles += len(dump.AlarmFiles)
+	}
+
+	return result, nil
+}
+
+// migrateUser restaura dump en dst con un ID temporal, lo verifica y recién
+// entonces reemplaza al usuario de dst. Si la copia falla, dst queda intacto.
+func migrateUser(dst Storage, dump *UserDump) error {
+	userID := dump.User.ID
+	stagingID := userID + migrateStagingSuffix
+
+	staged, err := dumpWithUserID(dump, stagingID)
+	if err != nil {
+		return err
+	}
+
+	// Restos de una migración interrumpida
+	if err := dst.DeleteUser(stagingID); err != nil {
+		return fmt.Errorf("error limpiando %s en el destino: %w", stagingID, err)
+	}
+	if err := dst.RestoreUser(staged); err != nil {
+		dst.DeleteUser(stagingID)
+		return fmt.Errorf("error importando usuario %s: %w", userID, err)
+	}
+
+	copied, err := dst.DumpUser(stagingID)
+	if err == nil {
+		err = compareDumps(staged, copied)
+	}
+	if err != nil {
+		dst.DeleteUser(stagingID)
+		return fmt.Errorf("verificación fallida para el usuario %s: %w", userID, err)
+	}
+
+	// Reemplazar al usuario existente por la copia verificada
+	if err := dst.DeleteUser(userID); err != nil {
+		dst.DeleteUser(stagingID)
+		return fmt.Errorf("error limpiando usuario %s en el destino: %w", userID, err)
+	}
+	if err := dst.RenameUser(stagingID, userID); err != nil {
+		return fmt.Errorf("error moviendo usuario %s (la copia verificada quedó en %s): %w", userID, stagingID, err)
+	}
+
+	copied, err = dst.DumpUser(userID)
+	if err == nil {
+		err = compareDumps(dump, copied)
+	}
+	if err != nil {
+		return fmt.Errorf("verificación fallida para el usuario %s: %w", userID, err)
+	}
+	return nil
+}
+
+// dumpWithUserID retorna una copia de dump con el usuario y sus entradas
+// asignados a userID
+func dumpWithUserID(dump *UserDump, userID string) (*UserDump, error) {
+	data, err := json.Marshal(dump)
+	if err != nil {
+		return nil, fmt.Errorf("error serializando usuario %s: %w", dump.User.ID, err)
+	}
+	var copied UserDump
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, fmt.Errorf("error serializando usuario %s: %w", dump.User.ID, err)
+	}
+
+	copied.User.ID = userID
+	for _, entry := range copied.Entries {
+		entry.UserID = userID
+	}
+	return &copied, nil
+}
+
+// compareDumps compara dos dumps por su serialización JSON
+func compareDumps(a, b *UserDump) error {
+	sortDump(a)
+	sortDump(b)
+
+	if len(a.Entries) != len(b.Entries) {
+		return fmt.Errorf("se esperaban %d entradas, hay %d", len(a.Entries), len(b.Entries))
+	}
+	if len(a.AlarmFiles) != len(b.AlarmFiles) {
+		return fmt.Errorf("se esperaban %d archivos de alarmas, hay %d", len(a.AlarmFiles), len(b.AlarmFiles))
+	}
+
+	dataA, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	dataB, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(dataA, dataB) {
+		return fmt.Errorf("los datos copiados no coinciden con el origen")
+	}
+	return nil
+}
+
+// sortDump ordena entradas y archivos de alarmas para que dos dumps de
+// backends distintos sean comparables
+func sortDump(dump *UserDump) {
+	sort.Slice(dump.Entries, func(i, j int) bool {
+		a, b := dump.Entries[i], dump.Entries[j]
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return recurrenceKey(a) < recurrenceKey(b)
+	})
+
+	sort.Slice(dump.AlarmFiles, func(i, j int) bool {
+		a, b := dump.AlarmFiles[i], dump.AlarmFiles[j]
+		if a.Past != b.Past {
+			return !a.Past
+		}
+		if a.Recurrence != b.Recurrence {
+			return a.Recurrence < b.Recurrence
+		}
+		return a.Filename < b.Filename
+	})
+}
+
+// recurrenceKey identifica un override dentro de su serie ("" para el maestro
+// o un evento simple)
+func recurrenceKey(entry *calendar.Entry) string {
+	if entry.RecurrenceID == nil {
+		return ""
+	}
+	return entry.RecurrenceID.UTC().Format(time.RFC3339Nano)
+}
+
+// dumpAlarmFiles exporta todos los archivos de alarmas (activos y pasados) de un usuario
+func dumpAlarmFiles(files alarmFiles, userID string) ([]AlarmFile, error) {
+	var result []AlarmFile
+	for _, past := range []bool{false, true} {
+		for _, rec := range allRecurrences {
+			filenames, err := files.listAlarmFiles(userID, past, rec)
+			if err != nil {
+				return nil, fmt.Errorf("error listando alarmas %s: %w", rec, err)
+			}
+			for _, filename := range filenames {
+				alarms, _, err := files.readAlarmFile(userID, past, rec, filename)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, AlarmFile{Past: past, Recurrence: rec, Filename: filename, Alarms: alarms})
+			}
+		}
+	}
+	return result, nil
+}
+
+// dumpUnackedAlarms exporta las alarmas sin confirmar (nil si no hay)
+func dumpUnackedAlarms(files alarmFiles, userID string) ([]*alarm.Alarm, error) {
+	alarms, err := files.readUnackedAlarms(userID)
+	if err != nil || len(alarms) == 0 {
+		return nil, err
+	}
+	return alarms, nil
+}
+
+// restoreAlarmFiles importa archivos de alarmas exportados con dumpAlarmFiles
+func restoreAlarmFiles(files alarmFiles, userID string, dumped []AlarmFile) error {
+	for _, f := range dumped {
+		if err := files.writeAlarmFile(userID, f.Past, f.Recurrence, f.Filename, f.Alarms); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/user"
+)
+
+// seedMigrateUser carga en s un usuario con todos los tipos de datos que
+// debe preservar una migración
+func seedMigrateUser(t *testing.T, s Storage) {
+	t.Helper()
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(s.SaveUser(user.NewUser("u1", "Ana", "America/Bogota")))
+
+	start := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	// Maestro recurrente con una ocurrencia movida y otra cancelada
+	weekly := calendar.NewEntry("u1", "Weekly", start, 30)
+	weekly.RRule, _ = calendar.ParseRecurrenceRule("FREQ=WEEKLY;BYDAY=MO,WE")
+	weekly.Tags = []string{"trabajo"}
+	weekly.Reminders = []int{10}
+	weekly.CreatedAt = start.AddDate(0, -1, 0)
+	weekly.ExcludeOccurrence(start.AddDate(0, 0, 14))
+	must(s.SaveEntry("u1", weekly))
+	moved := weekly.Occurrence(start.AddDate(0, 0, 7))
+	moved.DateTime = moved.DateTime.Add(2 * time.Hour)
+	moved.Title = "Weekly (moved)"
+	must(s.SaveEntry("u1", moved))
+
+	// Día completo y de varios días
+	holiday := calendar.NewEntry("u1", "Feriado", start.AddDate(0, 0, 7), 0)
+	holiday.AllDay = true
+	must(s.SaveEntry("u1", holiday))
+	tripEnd := start.AddDate(0, 0, 3)
+	trip := calendar.NewEntry("u1", "Viaje", start.AddDate(0, 0, 1), 0)
+	trip.AllDay = true
+	trip.EndDate = &tripEnd
+	must(s.SaveEntry("u1", trip))
+	confEnd := start.Add(50 * time.Hour)
+	conf := calendar.NewEntry("u1", "Conferencia", start.Add(4*time.Hour), 0)
+	conf.EndDate = &confEnd
+	must(s.SaveEntry("u1", conf))
+
+	// Una alarma de cada tipo pendiente y otra movida a past/
+	cron, _ := alarm.ParseCron("*/15 9-17 * * mon-fri")
+	nightly, _ := alarm.ParseCron("0 22 * * *")
+	window, _ := alarm.ParseTimeWindow("08:00-20:00")
+	every, _ := alarm.NewIntervalSchedule(start, 2*time.Hour, window)
+	hourly, _ := alarm.NewIntervalSchedule(start, time.Hour, nil)
+	schedules := map[alarm.Recurrence][2]string{
+		alarm.RecurrenceOnce:     {alarm.OneTimeFilename(start.AddDate(1, 0, 0)), alarm.OneTimeFilename(start.AddDate(-1, 0, 0))},
+		alarm.RecurrenceDaily:    {alarm.DailySchedule{Hour: 8}.Filename(), alarm.DailySchedule{Hour: 21, Minute: 30}.Filename()},
+		alarm.RecurrenceWeekly:   {alarm.WeeklySchedule{Weekday: time.Monday, Hour: 9}.Filename(), alarm.WeeklySchedule{Weekday: time.Friday, Hour: 17}.Filename()},
+		alarm.RecurrenceMonthly:  {alarm.MonthlySchedule{Day: 31, Clamp: true, Hour: 9}.Filename(), alarm.MonthlySchedule{Nth: alarm.LastWeek, Weekday: time.Friday, Hour: 9}.Filename()},
+		alarm.RecurrenceYearly:   {alarm.YearlySchedule{Month: time.March, Day: 15, Hour: 9}.Filename(), alarm.YearlySchedule{Month: time.February, Day: 29, Clamp: true, Hour: 9}.Filename()},
+		alarm.RecurrenceCron:     {cron.Filename(), nightly.Filename()},
+		alarm.RecurrenceInterval: {every.Filename(), hourly.Filename()},
+	}
+	for rec, files := range schedules {
+		for _, filename := range files {
+			must(s.SaveAlarm("u1", start, rec, filename, alarm.NewAlarm(string(rec)+" "+filename, rec)))
+		}
+		must(s.MoveAlarmsToPast("u1", rec, files[1]))
+	}
+
+	// Una alarma y un recordatorio disparados sin confirmar (el nombre de
+	// archivo de la alarma one-time está en la zona del usuario)
+	bogota, err := time.LoadLocation("America/Bogota")
+	must(err)
+	at := start.AddDate(0, 0, 2).In(bogota)
+	must(s.SaveAlarm("u1", at, alarm.RecurrenceOnce, alarm.OneTimeFilename(at), alarm.NewAlarm("pagar", alarm.RecurrenceOnce)))
+	if _, err := s.CheckAlarms("u1", at); err != nil {
+		t.Fatal(err)
+	}
+
+	state := NewReportState()
+	daily := "2025-12-01"
+	state.LastDailyReport = &daily
+	state.ReportedEvents[weekly.ID] = "2025-12-01T09:00:00Z"
+	must(s.SaveReportState("u1", state))
+}
+
+func TestMigrateRoundTrip(t *testing.T) {
+	fs, _ := newTestStorage(t, BackendFilesystem)
+	seedMigrateUser(t, fs)
+
+	before, err := fs.DumpUser("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unacked, _ := fs.ListUnackedAlarms("u1")
+	past, _ := fs.ListPastAlarms("u1")
+	if len(before.Entries) != 5 || len(unacked) != 2 || len(past) == 0 || before.ReportState == nil {
+		t.Fatalf("Datos iniciales incompletos: %d entradas, %d sin confirmar, %d pasadas", len(before.Entries), len(unacked), len(past))
+	}
+	kinds := map[alarm.Recurrence][2]bool{}
+	for _, f := range before.AlarmFiles {
+		k := kinds[f.Recurrence]
+		if f.Past {
+			k[1] = true
+		} else {
+			k[0] = true
+		}
+		kinds[f.Recurrence] = k
+	}
+	for _, rec := range allRecurrences {
+		if rec != alarm.RecurrenceReminder && kinds[rec] != [2]bool{true, true} {
+			t.Errorf("Faltan alarmas %s en pending/ o past/: %v", rec, kinds[rec])
+		}
+	}
+	if !kinds[alarm.RecurrenceReminder][1] {
+		t.Error("Falta el registro del recordatorio en past/")
+	}
+
+	db, _ := newTestStorage(t, BackendSQLite)
+	back, _ := newTestStorage(t, BackendFilesystem)
+	for _, step := range []struct {
+		name     string
+		src, dst Storage
+	}{{"fs->sqlite", fs, db}, {"sqlite->fs", db, back}} {
+		result, err := Migrate(step.src, step.dst, false)
+		if err != nil {
+			t.Fatalf("Migrate(%s) error = %v", step.name, err)
+		}
+		if result.Users != 1 || result.Entries != len(before.Entries) || result.AlarmFiles != len(before.AlarmFiles) {
+			t.Errorf("Migrate(%s) = %+v", step.name, result)
+		}
+	}
+
+	after, err := back.DumpUser("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := compareDumps(before, after); err != nil {
+		t.Errorf("Round trip fs->sqlite->fs: %v", err)
+	}
+
+	// Los datos migrados siguen funcionando
+	start := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	for _, s := range []Storage{db, back} {
+		got, err := s.ListEntries("u1", &calendar.Filter{From: &start, To: ptr(start.AddDate(0, 0, 20))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		titles := map[string]int{}
+		for _, e := range got {
+			titles[e.Title]++
+		}
+		// 6 ocurrencias del Weekly en 3 semanas: una movida y una cancelada
+		if titles["Weekly"] != 4 || titles["Weekly (moved)"] != 1 || titles["Feriado"] != 1 || titles["Viaje"] != 1 || titles["Conferencia"] != 1 {
+			t.Errorf("ListEntries() tras migrar = %v", titles)
+		}
+		if unacked, err := s.ListUnackedAlarms("u1"); err != nil || len(unacked) != 2 {
+			t.Errorf("ListUnackedAlarms() tras migrar = %v, %v", unacked, err)
+		}
+		if state, err := s.GetReportState("u1"); err != nil || state.LastDailyReport == nil || *state.LastDailyReport != "2025-12-01" {
+			t.Errorf("GetReportState() tras migrar = %+v, %v", state, err)
+		}
+	}
+}
+
+func TestMigrateExistingUser(t *testing.T) {
+	src, _ := newTestStorage(t, BackendFilesystem)
+	seedMigrateUser(t, src)
+	dst, _ := newTestStorage(t, BackendSQLite)
+	if err := dst.SaveUser(user.NewUser("u1", "Otra", "UTC")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Migrate(src, dst, false); err == nil {
+		t.Fatal("Expected error migrating over an existing user")
+	}
+	if u, err := dst.GetUser("u1"); err != nil || u.Name != "Otra" {
+		t.Errorf("El usuario existente cambió sin --force: %v, %v", u, err)
+	}
+
+	if _, err := Migrate(src, dst, true); err != nil {
+		t.Fatalf("Migrate(overwrite) error = %v", err)
+	}
+	if u, err := dst.GetUser("u1"); err != nil || u.Name != "Ana" {
+		t.Errorf("GetUser() tras --force = %v, %v", u, err)
+	}
+}
+
+// failingRestore simula un error al restaurar un usuario en el destino
+type failingRestore struct {
+	Storage
+}
+
+func (f failingRestore) RestoreUser(dump *UserDump) error {
+	if err := f.Storage.RestoreUser(dump); err != nil {
+		return err
+	}
+	return fmt.Errorf("disco lleno")
+}
+
+func TestMigrateFailureKeepsDestination(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		src, _ := newTestStorage(t, BackendFilesystem)
+		seedMigrateUser(t, src)
+		dst, _ := newTestStorage(t, backend)
+		if err := dst.SaveUser(user.NewUser("u1", "Otra", "UTC")); err != nil {
+			t.Fatal(err)
+		}
+		entry := calendar.NewEntry("u1", "Existente", time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC), 30)
+		if err := dst.SaveEntry("u1", entry); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Migrate(src, failingRestore{dst}, true); err == nil {
+			t.Fatal("Expected error when the restore fails")
+		}
+
+		// El usuario del destino queda intacto y no quedan restos de la copia
+		if u, err := dst.GetUser("u1"); err != nil || u.Name != "Otra" {
+			t.Errorf("GetUser() = %v, %v, want el usuario original", u, err)
+		}
+		if got, err := dst.GetEntry("u1", entry.ID); err != nil || got.Title != "Existente" {
+			t.Errorf("GetEntry() = %v, %v, want la entrada original", got, err)
+		}
+		if users, err := dst.ListUsers(); err != nil || len(users) != 1 {
+			t.Errorf("ListUsers() = %v, %v, want solo u1", users, err)
+		}
+	})
+}
+
+func TestMigrateKeepsHistoryAndTrash(t *testing.T) {
+	// Ambos backends comparten el directorio de datos, como en 'clical migrate'
+	dir := t.TempDir()
+	db, err := NewSQLiteStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	fs, err := NewFilesystemStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrap := func(s Storage) Storage {
+		return WithHistory(WithTrash(s, dir, 0), dir, "clical test", "tester")
+	}
+
+	s := wrap(db)
+	if err := s.SaveUser(user.NewUser("u1", "Ana", "UTC")); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	kept := calendar.NewEntry("u1", "Reunión", start, 30)
+	deleted := calendar.NewEntry("u1", "Borrada", start.Add(time.Hour), 30)
+	for _, e := range []*calendar.Entry{kept, deleted} {
+		if err := s.SaveEntry("u1", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	kept.Title = "Reunión (editada)"
+	if err := s.UpdateEntry("u1", kept); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteEntry("u1", deleted.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// El usuario ya existe en el destino: hace falta --force
+	if err := fs.SaveUser(user.NewUser("u1", "Vieja", "UTC")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Migrate(db, fs, true); err != nil {
+		t.Fatal(err)
+	}
+
+	s = wrap(fs)
+	if u, err := s.GetUser("u1"); err != nil || u.Name != "Ana" {
+		t.Errorf("GetUser() = %v, %v", u, err)
+	}
+	if records, err := s.(HistoryKeeper).History("u1", kept.ID); err != nil || len(records) != 2 {
+		t.Errorf("History() tras migrar = %d cambios, %v, want 2", len(records), err)
+	}
+	if items, err := s.(TrashBin).ListTrash("u1"); err != nil || len(items) != 1 || items[0].ID != deleted.ID {
+		t.Errorf("ListTrash() tras migrar = %v, %v", items, err)
+	}
+
+	// Eliminar el usuario con los wrappers sí borra todo
+	if err := s.DeleteUser("u1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(getUserDir(dir, "u1")); !os.IsNotExist(err) {
+		t.Errorf("Expected user directory to be removed, stat error = %v", err)
+	}
+}
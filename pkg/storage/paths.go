@@ -22,6 +22,13 @@ func getEntryPath(dataDir, userID string, datetime time.Time, filename string, e
 	return filepath.Join(dir, filename+ext)
 }
 
+// getOverrideFilename retorna el nombre de archivo (sin extensión) de un override
+// de ocurrencia, que se guarda junto al evento maestro
+// Formato: HH-MM-titulo-slug.YYYYMMDDTHHMMSS
+func getOverrideFilename(masterFilename string, recurrenceID time.Time) string {
+	return fmt.Sprintf("%s.%s", masterFilename, recurrenceID.Format("20060102T150405"))
+}
+
 // getUserDir retorna el directorio de un usuario
 func getUserDir(dataDir, userID string) string {
 	return filepath.Join(dataDir, "users", userID)
@@ -33,6 +40,11 @@ func getUserPath(dataDir, userID string, ext string) string {
 	return filepath.Join(dir, "user"+ext)
 }
 
+// wrapperSubdirs son los subdirectorios de un usuario que mantienen los
+// wrappers (historial y papelera) con cualquier backend. El backend de
+// filesystem no los elimina: de eso se encargan los propios wrappers.
+var wrapperSubdirs = []string{filepath.Join(".state", "history"), trashDirname}
+
 // getStateDir retorna el directorio de estado de un usuario
 func getStateDir(dataDir, userID string) string {
 	return filepath.Join(dataDir, "users", userID, ".state")
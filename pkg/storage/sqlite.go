@@ -0,0 +1,848 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/user"
+
+	_ "modernc.org/sqlite" // driver SQLite en Go puro (sin cgo)
+)
+
+// SQLiteFilename es el nombre de la base de datos dentro del directorio de datos
+const SQLiteFilename = "clical.db"
+
+// sqliteSchema crea las tablas e índices. Cada fila guarda el JSON completo
+// del objeto (el mismo que escribe el backend de filesystem); las demás
+// columnas son índices para filtrar sin deserializar.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS users (
+	id   TEXT PRIMARY KEY,
+	data TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS entries (
+	user_id       TEXT NOT NULL,
+	id            TEXT NOT NULL,
+	recurrence_id TEXT NOT NULL DEFAULT '', -- '' en eventos simples y maestros
+	recurring     INTEGER NOT NULL,
+	start_at      INTEGER NOT NULL,         -- unix
+	end_at        INTEGER NOT NULL,         -- unix
+	data          TEXT NOT NULL,
+	PRIMARY KEY (user_id, id, recurrence_id)
+);
+CREATE INDEX IF NOT EXISTS idx_entries_datetime ON entries (user_id, start_at);
+
+CREATE TABLE IF NOT EXISTS entry_tags (
+	user_id       TEXT NOT NULL,
+	entry_id      TEXT NOT NULL,
+	recurrence_id TEXT NOT NULL DEFAULT '',
+	tag           TEXT NOT NULL,
+	PRIMARY KEY (user_id, entry_id, recurrence_id, tag)
+);
+CREATE INDEX IF NOT EXISTS idx_entry_tags_tag ON entry_tags (user_id, tag);
+
+CREATE TABLE IF NOT EXISTS alarm_files (
+	user_id    TEXT NOT NULL,
+	past       INTEGER NOT NULL,
+	recurrence TEXT NOT NULL,
+	filename   TEXT NOT NULL, -- schedule, ej: 2025-12-21_01-10-00.json
+	data       TEXT NOT NULL,
+	PRIMARY KEY (user_id, past, recurrence, filename)
+);
+CREATE INDEX IF NOT EXISTS idx_alarm_files_schedule ON alarm_files (user_id, recurrence, filename);
+
+CREATE TABLE IF NOT EXISTS alarm_unacked (
+	user_id TEXT PRIMARY KEY,
+	data    TEXT NOT NULL -- alarmas disparadas sin confirmar
+);
+
+CREATE TABLE IF NOT EXISTS report_state (
+	user_id TEXT PRIMARY KEY,
+	data    TEXT NOT NULL
+);
+`
+
+// SQLiteStorage implementa Storage usando una base SQLite embebida
+type SQLiteStorage struct {
+	db *sql.DB
+}
+
+// execer es la parte común de *sql.DB y *sql.Tx usada para escribir
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// NewSQLiteStorage abre (o crea) la base SQLite del directorio de datos
+func NewSQLiteStorage(dataDir string) (*SQLiteStorage, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("error creando directorio: %w", err)
+	}
+
+	dsn := "file:" + filepath.Join(dataDir, SQLiteFilename) +
+		"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo base de datos: %w", err)
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creando esquema: %w", err)
+	}
+
+	return &SQLiteStorage{db: db}, nil
+}
+
+// Close cierra la base de datos
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
+
+// SaveEntry guarda una entrada. Si es una ocurrencia (RecurrenceID != nil)
+// se guarda como override de su evento maestro.
+func (s *SQLiteStorage) SaveEntry(userID string, entry *calendar.Entry) error {
+	if err := prepareEntry(entry); err != nil {
+		return err
+	}
+
+	if entry.IsOccurrence() {
+		master, err := s.GetEntry(userID, entry.ID)
+		if err != nil {
+			return err
+		}
+		if !master.HasOccurrence(*entry.RecurrenceID) {
+			return fmt.Errorf("el evento %s no tiene ocurrencia en %s", entry.ID, entry.RecurrenceID.Format("2006-01-02 15:04"))
+		}
+	}
+
+	return s.inTx(func(tx *sql.Tx) error {
+		return putEntry(tx, userID, entry)
+	})
+}
+
+// putEntry inserta o reemplaza una entrada y sus tags
+func putEntry(db execer, userID string, entry *calendar.Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error serializando JSON: %w", err)
+	}
+
+	key := recurrenceKey(entry)
+	_, err = db.Exec(`INSERT OR REPLACE INTO entries
+		(user_id, id, recurrence_id, recurring, start_at, end_at, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		userID, entry.ID, key, entry.IsRecurring(), entry.DateTime.Unix(), entry.EndTime().Unix(), string(data))
+	if err != nil {
+		return fmt.Errorf("error guardando entrada: %w", err)
+	}
+
+	if _, err := db.Exec(`DELETE FROM entry_tags WHERE user_id = ? AND entry_id = ? AND recurrence_id = ?`,
+		userID, entry.ID, key); err != nil {
+		return fmt.Errorf("error guardando tags: %w", err)
+	}
+	for _, tag := range entry.Tags {
+		if _, err := db.Exec(`INSERT OR IGNORE INTO entry_tags (user_id, entry_id, recurrence_id, tag) VALUES (?, ?, ?, ?)`,
+			userID, entry.ID, key, tag); err != nil {
+			return fmt.Errorf("error guardando tags: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteEntryRows elimina una entrada y todos sus overrides
+func deleteEntryRows(db execer, userID, entryID string) error {
+	if _, err := db.Exec(`DELETE FROM entries WHERE user_id = ? AND id = ?`, userID, entryID); err != nil {
+		return fmt.Errorf("error eliminando entrada: %w", err)
+	}
+	if _, err := db.Exec(`DELETE FROM entry_tags WHERE user_id = ? AND entry_id = ?`, userID, entryID); err != nil {
+		return fmt.Errorf("error eliminando tags: %w", err)
+	}
+	return nil
+}
+
+// queryEntries ejecuta una consulta que retorna la columna data de entries
+func (s *SQLiteStorage) queryEntries(query string, args ...any) ([]*calendar.Entry, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando entradas: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*calendar.Entry
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("error leyendo entrada: %w", err)
+		}
+
+		var entry calendar.Entry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			return nil, fmt.Errorf("error parseando entrada: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetEntry obtiene una entrada por ID
+func (s *SQLiteStorage) GetEntry(userID, entryID string) (*calendar.Entry, error) {
+	entries, err := s.queryEntries(`SELECT data FROM entries
+		WHERE user_id = ? AND id = ? AND recurrence_id = ''`, userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("entrada no encontrada: %s", entryID)
+	}
+	return entries[0], nil
+}
+
+// GetOccurrence obtiene una ocurrencia de un evento recurrente, aplicando su override si existe
+func (s *SQLiteStorage) GetOccurrence(userID, entryID string, recurrenceID time.Time) (*calendar.Entry, error) {
+	master, err := s.GetEntry(userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !master.HasOccurrence(recurrenceID) {
+		return nil, fmt.Errorf("el evento %s no tiene ocurrencia en %s", entryID, recurrenceID.Format("2006-01-02 15:04"))
+	}
+
+	overrides, err := s.queryEntries(`SELECT data FROM entries
+		WHERE user_id = ? AND id = ? AND recurrence_id = ?`,
+		userID, entryID, recurrenceID.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, err
+	}
+	if len(overrides) > 0 {
+		return overrides[0], nil
+	}
+
+	return master.Occurrence(recurrenceID), nil
+}
+
+// ListOverrides retorna los overrides de ocurrencia de un evento recurrente
+func (s *SQLiteStorage) ListOverrides(userID, entryID string) ([]*calendar.Entry, error) {
+	if _, err := s.GetEntry(userID, entryID); err != nil {
+		return nil, err
+	}
+	return s.listOverrides(userID, entryID)
+}
+
+// listOverrides retorna los overrides guardados de un evento
+func (s *SQLiteStorage) listOverrides(userID, entryID string) ([]*calendar.Entry, error) {
+	return s.queryEntries(`SELECT data FROM entries
+		WHERE user_id = ? AND id = ? AND recurrence_id != ''
+		ORDER BY recurrence_id`, userID, entryID)
+}
+
+// ListEntries lista las entradas de un usuario con filtro opcional.
+// El rango de fechas y los tags se prefiltran con los índices; el filtro
+// completo se aplica después, igual que en el backend de filesystem.
+func (s *SQLiteStorage) ListEntries(userID string, filter *calendar.Filter) ([]*calendar.Entry, error) {
+	if filter == nil {
+		filter = calendar.NewFilter()
+	}
+
+	// Prefiltro por rango: superconjunto de ambos modos (superposición e inicio en rango)
+	var where []string
+	var args []any
+	if filter.To != nil {
+		where = append(where, "start_at <= ?")
+		args = append(args, filter.To.Unix())
+	}
+
+	plainWhere, plainArgs := where, args
+	if filter.From != nil {
+		plainWhere = append(plainWhere, "end_at >= ?")
+		plainArgs = append(plainArgs, filter.From.Unix())
+	}
+	for _, tag := range filter.Tags {
+		plainWhere = append(plainWhere, `EXISTS (SELECT 1 FROM entry_tags t
+			WHERE t.user_id = entries.user_id AND t.entry_id = entries.id
+			AND t.recurrence_id = '' AND t.tag = ?)`)
+		plainArgs = append(plainArgs, tag)
+	}
+
+	plain, err := s.queryEntries(`SELECT data FROM entries
+		WHERE user_id = ? AND recurrence_id = '' AND recurring = 0`+andClauses(plainWhere),
+		append([]any{userID}, plainArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Los maestros no se prefiltran por fin ni por tags: sus ocurrencias
+	// (y overrides) pueden caer en el rango o tener otros tags
+	masters, err := s.queryEntries(`SELECT data FROM entries
+		WHERE user_id = ? AND recurrence_id = '' AND recurring = 1`+andClauses(where),
+		append([]any{userID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[string][]*calendar.Entry)
+	if len(masters) > 0 && (filter.From != nil || filter.To != nil) {
+		all, err := s.queryEntries(`SELECT data FROM entries
+			WHERE user_id = ? AND recurrence_id != ''`, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range all {
+			overrides[o.ID] = append(overrides[o.ID], o)
+		}
+	}
+
+	return selectEntries(plain, masters, overrides, filter), nil
+}
+
+// andClauses une condiciones SQL con AND, precedidas por AND
+func andClauses(clauses []string) string {
+	if len(clauses) == 0 {
+		return ""
+	}
+	return " AND " + strings.Join(clauses, " AND ")
+}
+
+// DeleteEntry elimina una entrada (y los overrides de ocurrencia si es recurrente)
+func (s *SQLiteStorage) DeleteEntry(userID, entryID string) error {
+	if _, err := s.GetEntry(userID, entryID); err != nil {
+		return err
+	}
+	return s.inTx(func(tx *sql.Tx) error {
+		return deleteEntryRows(tx, userID, entryID)
+	})
+}
+
+// UpdateEntry actualiza una entrada existente.
+// Los overrides de un evento recurrente se conservan mientras su ocurrencia siga vigente.
+func (s *SQLiteStorage) UpdateEntry(userID string, entry *calendar.Entry) error {
+	// Actualizar timestamp
+	entry.UpdatedAt = time.Now()
+
+	if entry.IsOccurrence() {
+		return s.SaveEntry(userID, entry)
+	}
+
+	if _, err := s.GetEntry(userID, entry.ID); err != nil {
+		return err
+	}
+
+	overrides, err := s.listOverrides(userID, entry.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := prepareEntry(entry); err != nil {
+		return err
+	}
+
+	return s.inTx(func(tx *sql.Tx) error {
+		if err := deleteEntryRows(tx, userID, entry.ID); err != nil {
+			return err
+		}
+		if err := putEntry(tx, userID, entry); err != nil {
+			return err
+		}
+		for _, o := range overrides {
+			if entry.HasOccurrence(*o.RecurrenceID) {
+				if err := putEntry(tx, userID, o); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// SaveUser guarda un usuario
+func (s *SQLiteStorage) SaveUser(u *user.User) error {
+	if err := u.Validate(); err != nil {
+		return fmt.Errorf("usuario inválido: %w", err)
+	}
+	return putUser(s.db, u)
+}
+
+// putUser inserta o reemplaza un usuario
+func putUser(db execer, u *user.User) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return fmt.Errorf("error serializando JSON: %w", err)
+	}
+	if _, err := db.Exec(`INSERT OR REPLACE INTO users (id, data) VALUES (?, ?)`, u.ID, string(data)); err != nil {
+		return fmt.Errorf("error guardando usuario: %w", err)
+	}
+	return nil
+}
+
+// GetUser obtiene un usuario por ID
+func (s *SQLiteStorage) GetUser(userID string) (*user.User, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM users WHERE id = ?`, userID).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("usuario no encontrado: %s", userID)
+		}
+		return nil, fmt.Errorf("error leyendo usuario: %w", err)
+	}
+
+	var u user.User
+	if err := json.Unmarshal([]byte(data), &u); err != nil {
+		return nil, fmt.Errorf("error parseando usuario: %w", err)
+	}
+
+	return &u, nil
+}
+
+// ListUsers lista todos los usuarios
+func (s *SQLiteStorage) ListUsers() ([]*user.User, error) {
+	rows, err := s.db.Query(`SELECT data FROM users ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error listando usuarios: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*user.User
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("error leyendo usuario: %w", err)
+		}
+
+		var u user.User
+		if err := json.Unmarshal([]byte(data), &u); err != nil {
+			continue // Skip usuarios con errores
+		}
+		users = append(users, &u)
+	}
+
+	return users, rows.Err()
+}
+
+// userTables son las tablas con datos de un usuario y su columna de usuario
+var userTables = []struct{ name, column string }{
+	{"users", "id"},
+	{"entries", "user_id"},
+	{"entry_tags", "user_id"},
+	{"alarm_files", "user_id"},
+	{"alarm_unacked", "user_id"},
+	{"report_state", "user_id"},
+}
+
+// DeleteUser elimina un usuario y todos sus datos
+func (s *SQLiteStorage) DeleteUser(userID string) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		for _, t := range userTables {
+			if _, err := tx.Exec(`DELETE FROM `+t.name+` WHERE `+t.column+` = ?`, userID); err != nil {
+				return fmt.Errorf("error eliminando usuario: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// RenameUser cambia el ID de un usuario, moviendo sus eventos, alarmas y
+// estado en una sola transacción
+func (s *SQLiteStorage) RenameUser(oldID, newID string) error {
+	if err := user.ValidateID(newID); err != nil {
+		return err
+	}
+
+	u, err := s.GetUser(oldID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.GetUser(newID); err == nil {
+		return fmt.Errorf("ya existe un usuario con id %s", newID)
+	}
+
+	entries, err := s.queryEntries(`SELECT data FROM entries WHERE user_id = ?`, oldID)
+	if err != nil {
+		return err
+	}
+
+	return s.inTx(func(tx *sql.Tx) error {
+		for _, t := range userTables {
+			if _, err := tx.Exec(`UPDATE `+t.name+` SET `+t.column+` = ? WHERE `+t.column+` = ?`, newID, oldID); err != nil {
+				return fmt.Errorf("error moviendo datos: %w", err)
+			}
+		}
+
+		u.ID = newID
+		if err := putUser(tx, u); err != nil {
+			return err
+		}
+
+		// El JSON de cada entrada también guarda el user_id
+		for _, entry := range entries {
+			entry.UserID = newID
+			if err := putEntry(tx, newID, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ArchiveUser escribe en w un .tar.gz con todos los datos del usuario, con
+// la misma estructura de archivos que el backend de filesystem
+func (s *SQLiteStorage) ArchiveUser(userID string, w io.Writer) error {
+	dump, err := s.DumpUser(userID)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "clical-archive-")
+	if err != nil {
+		return fmt.Errorf("error creando directorio: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	fs, err := NewFilesystemStorage(tmpDir)
+	if err != nil {
+		return err
+	}
+	if err := fs.RestoreUser(dump); err != nil {
+		return fmt.Errorf("error archivando usuario: %w", err)
+	}
+
+	return fs.ArchiveUser(userID, w)
+}
+
+// GetReportState obtiene el estado de reportes
+func (s *SQLiteStorage) GetReportState(userID string) (*ReportState, error) {
+	state, err := s.getReportState(userID)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return NewReportState(), nil
+	}
+	return state, nil
+}
+
+// getReportState retorna el estado guardado, o nil si no existe
+func (s *SQLiteStorage) getReportState(userID string) (*ReportState, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM report_state WHERE user_id = ?`, userID).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error leyendo estado: %w", err)
+	}
+
+	var state ReportState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("error parseando estado: %w", err)
+	}
+
+	if state.ReportedEvents == nil {
+		state.ReportedEvents = make(map[string]string)
+	}
+
+	return &state, nil
+}
+
+// SaveReportState guarda el estado de reportes
+func (s *SQLiteStorage) SaveReportState(userID string, state *ReportState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("error serializando estado: %w", err)
+	}
+
+	if _, err := s.db.Exec(`INSERT OR REPLACE INTO report_state (user_id, data) VALUES (?, ?)`,
+		userID, string(data)); err != nil {
+		return fmt.Errorf("error escribiendo estado: %w", err)
+	}
+
+	return nil
+}
+
+// DumpUser exporta todos los datos de un usuario
+func (s *SQLiteStorage) DumpUser(userID string) (*UserDump, error) {
+	u, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	dump := &UserDump{User: u}
+
+	if dump.Entries, err = s.queryEntries(`SELECT data FROM entries WHERE user_id = ?`, userID); err != nil {
+		return nil, err
+	}
+	if dump.AlarmFiles, err = dumpAlarmFiles(s, userID); err != nil {
+		return nil, err
+	}
+	if dump.UnackedAlarms, err = dumpUnackedAlarms(s, userID); err != nil {
+		return nil, err
+	}
+	if dump.ReportState, err = s.getReportState(userID); err != nil {
+		return nil, err
+	}
+
+	return dump, nil
+}
+
+// RestoreUser importa los datos exportados con DumpUser. Las entradas se
+// escriben tal cual, sin volver a validarlas ni normalizarlas.
+func (s *SQLiteStorage) RestoreUser(dump *UserDump) error {
+	userID := dump.User.ID
+	if err := user.ValidateID(userID); err != nil {
+		return err
+	}
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		if err := putUser(tx, dump.User); err != nil {
+			return err
+		}
+		for _, entry := range dump.Entries {
+			if err := putEntry(tx, userID, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := restoreAlarmFiles(s, userID, dump.AlarmFiles); err != nil {
+		return err
+	}
+	if err := s.writeUnackedAlarms(userID, dump.UnackedAlarms); err != nil {
+		return err
+	}
+
+	if dump.ReportState != nil {
+		return s.SaveReportState(userID, dump.ReportState)
+	}
+	return nil
+}
+
+// inTx ejecuta fn en una transacción, haciendo rollback si falla
+func (s *SQLiteStorage) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando transacción: %w", err)
+	}
+	return nil
+}
+
+// SaveAlarm guarda una alarma en el archivo correspondiente
+func (s *SQLiteStorage) SaveAlarm(userID string, alarmTime time.Time, recurrence alarm.Recurrence, filename string, alm *alarm.Alarm) error {
+	return saveAlarm(s, userID, recurrence, filename, alm)
+}
+
+// GetAlarms lee todas las alarmas de un archivo
+func (s *SQLiteStorage) GetAlarms(userID string, recurrence alarm.Recurrence, filename string) ([]*alarm.Alarm, error) {
+	return getAlarms(s, userID, recurrence, filename)
+}
+
+// DeleteAlarms elimina un archivo de alarmas
+func (s *SQLiteStorage) DeleteAlarms(userID string, recurrence alarm.Recurrence, filename string) error {
+	return s.removeAlarmFile(userID, recurrence, filename)
+}
+
+// CheckAlarms verifica alarmas que deben ejecutarse en el momento dado, con
+// los horarios en la zona horaria del usuario
+func (s *SQLiteStorage) CheckAlarms(userID string, at time.Time) ([]*alarm.Alarm, error) {
+	return checkAlarms(s, s, userID, at.In(alarmLocation(s, userID, at.Location())))
+}
+
+// ListActiveAlarms lista todas las alarmas activas
+func (s *SQLiteStorage) ListActiveAlarms(userID string) ([]*alarm.Alarm, error) {
+	return listActiveAlarms(s, userID, time.Now().In(alarmLocation(s, userID, time.Local)))
+}
+
+// ListPastAlarms lista todas las alarmas pasadas
+func (s *SQLiteStorage) ListPastAlarms(userID string) ([]*alarm.Alarm, error) {
+	return listPastAlarms(s, userID)
+}
+
+// CancelAlarm cancela (elimina) una alarma por ID
+func (s *SQLiteStorage) CancelAlarm(userID string, alarmID string) error {
+	return cancelAlarm(s, userID, alarmID)
+}
+
+// MoveAlarmsToPast mueve un archivo de alarmas a past
+func (s *SQLiteStorage) MoveAlarmsToPast(userID string, recurrence alarm.Recurrence, filename string) error {
+	return s.moveAlarmFileToPast(userID, recurrence, filename)
+}
+
+// ListUnackedAlarms lista las alarmas disparadas sin confirmar
+func (s *SQLiteStorage) ListUnackedAlarms(userID string) ([]*alarm.Alarm, error) {
+	return s.readUnackedAlarms(userID)
+}
+
+// AckAlarm confirma una alarma disparada
+func (s *SQLiteStorage) AckAlarm(userID string, alarmID string) error {
+	return ackAlarm(s, userID, alarmID)
+}
+
+// SnoozeAlarm repite una alarma disparada como una alarma one-time
+func (s *SQLiteStorage) SnoozeAlarm(userID string, alarmID string, at time.Time, d time.Duration) (*alarm.Alarm, error) {
+	return snoozeAlarm(s, userID, alarmID, at.In(alarmLocation(s, userID, at.Location())), d)
+}
+
+// NagAlarms vuelve a entregar las alarmas sin confirmar
+func (s *SQLiteStorage) NagAlarms(userID string, at time.Time, every time.Duration) ([]*alarm.Alarm, error) {
+	return nagAlarms(s, userID, at, every)
+}
+
+// readAlarmFile implementa alarmFiles
+func (s *SQLiteStorage) readAlarmFile(userID string, past bool, recurrence alarm.Recurrence, filename string) ([]*alarm.Alarm, bool, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM alarm_files
+		WHERE user_id = ? AND past = ? AND recurrence = ? AND filename = ?`,
+		userID, past, string(recurrence), filename).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("error leyendo alarmas: %w", err)
+	}
+
+	var alarms []*alarm.Alarm
+	if err := json.Unmarshal([]byte(data), &alarms); err != nil {
+		return nil, false, fmt.Errorf("error deserializando alarmas: %w", err)
+	}
+
+	return alarms, true, nil
+}
+
+// alarmFileExists implementa alarmFiles
+func (s *SQLiteStorage) alarmFileExists(userID string, past bool, recurrence alarm.Recurrence, filename string) (bool, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM alarm_files
+		WHERE user_id = ? AND past = ? AND recurrence = ? AND filename = ?`,
+		userID, past, string(recurrence), filename).Scan(&n)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// writeAlarmFile implementa alarmFiles
+func (s *SQLiteStorage) writeAlarmFile(userID string, past bool, recurrence alarm.Recurrence, filename string, alarms []*alarm.Alarm) error {
+	data, err := json.Marshal(alarms)
+	if err != nil {
+		return fmt.Errorf("error serializando alarmas: %w", err)
+	}
+
+	if _, err := s.db.Exec(`INSERT OR REPLACE INTO alarm_files (user_id, past, recurrence, filename, data)
+		VALUES (?, ?, ?, ?, ?)`, userID, past, string(recurrence), filename, string(data)); err != nil {
+		return fmt.Errorf("error escribiendo alarmas: %w", err)
+	}
+
+	return nil
+}
+
+// removeAlarmFile implementa alarmFiles
+func (s *SQLiteStorage) removeAlarmFile(userID string, recurrence alarm.Recurrence, filename string) error {
+	if _, err := s.db.Exec(`DELETE FROM alarm_files
+		WHERE user_id = ? AND past = 0 AND recurrence = ? AND filename = ?`,
+		userID, string(recurrence), filename); err != nil {
+		return fmt.Errorf("error eliminando alarmas: %w", err)
+	}
+	return nil
+}
+
+// listAlarmFiles implementa alarmFiles
+func (s *SQLiteStorage) listAlarmFiles(userID string, past bool, recurrence alarm.Recurrence) ([]string, error) {
+	rows, err := s.db.Query(`SELECT filename FROM alarm_files
+		WHERE user_id = ? AND past = ? AND recurrence = ?
+		ORDER BY filename`, userID, past, string(recurrence))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var filenames []string
+	for rows.Next() {
+		var filename string
+		if err := rows.Scan(&filename); err != nil {
+			return nil, err
+		}
+		filenames = append(filenames, filename)
+	}
+	return filenames, rows.Err()
+}
+
+// readUnackedAlarms implementa alarmFiles
+func (s *SQLiteStorage) readUnackedAlarms(userID string) ([]*alarm.Alarm, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM alarm_unacked WHERE user_id = ?`, userID).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []*alarm.Alarm{}, nil
+		}
+		return nil, fmt.Errorf("error leyendo alarmas sin confirmar: %w", err)
+	}
+
+	var alarms []*alarm.Alarm
+	if err := json.Unmarshal([]byte(data), &alarms); err != nil {
+		return nil, fmt.Errorf("error deserializando alarmas sin confirmar: %w", err)
+	}
+	return alarms, nil
+}
+
+// writeUnackedAlarms implementa alarmFiles
+func (s *SQLiteStorage) writeUnackedAlarms(userID string, alarms []*alarm.Alarm) error {
+	if len(alarms) == 0 {
+		if _, err := s.db.Exec(`DELETE FROM alarm_unacked WHERE user_id = ?`, userID); err != nil {
+			return fmt.Errorf("error eliminando alarmas sin confirmar: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(alarms)
+	if err != nil {
+		return fmt.Errorf("error serializando alarmas: %w", err)
+	}
+	if _, err := s.db.Exec(`INSERT OR REPLACE INTO alarm_unacked (user_id, data) VALUES (?, ?)`,
+		userID, string(data)); err != nil {
+		return fmt.Errorf("error escribiendo alarmas sin confirmar: %w", err)
+	}
+	return nil
+}
+
+// moveAlarmFileToPast implementa alarmFiles
+func (s *SQLiteStorage) moveAlarmFileToPast(userID string, recurrence alarm.Recurrence, filename string) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		// Reemplazar un archivo pasado con el mismo nombre, como hace rename
+		if _, err := tx.Exec(`DELETE FROM alarm_files
+			WHERE user_id = ? AND past = 1 AND recurrence = ? AND filename = ?`,
+			userID, string(recurrence), filename); err != nil {
+			return fmt.Errorf("error moviendo alarma a past: %w", err)
+		}
+
+		res, err := tx.Exec(`UPDATE alarm_files SET past = 1
+			WHERE user_id = ? AND past = 0 AND recurrence = ? AND filename = ?`,
+			userID, string(recurrence), filename)
+		if err != nil {
+			return fmt.Errorf("error moviendo alarma a past: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("error moviendo alarma a past: %s no existe", filename)
+		}
+		return nil
+	})
+}
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"fmt"
+	"io"
 	"time"
 
 	"github.com/sebasvalencia/clical/pkg/alarm"
@@ -16,12 +18,20 @@ type Storage interface {
 	ListEntries(userID string, filter *calendar.Filter) ([]*calendar.Entry, error)
 	DeleteEntry(userID, entryID string) error
 	UpdateEntry(userID string, entry *calendar.Entry) error
+	// GetOccurrence retorna una ocurrencia de un evento recurrente (override si existe)
+	GetOccurrence(userID, entryID string, recurrenceID time.Time) (*calendar.Entry, error)
+	// ListOverrides retorna los overrides de ocurrencia de un evento recurrente
+	ListOverrides(userID, entryID string) ([]*calendar.Entry, error)
 
 	// Users
 	SaveUser(user *user.User) error
 	GetUser(userID string) (*user.User, error)
 	ListUsers() ([]*user.User, error)
 	DeleteUser(userID string) error
+	// RenameUser cambia el ID de un usuario, moviendo sus eventos, alarmas y estado
+	RenameUser(oldID, newID string) error
+	// ArchiveUser escribe en w un .tar.gz con todos los datos del usuario
+	ArchiveUser(userID string, w io.Writer) error
 
 	// State (para reportes)
 	GetReportState(userID string) (*ReportState, error)
@@ -36,6 +46,45 @@ type Storage interface {
 	ListPastAlarms(userID string) ([]*alarm.Alarm, error)
 	CancelAlarm(userID string, alarmID string) error
 	MoveAlarmsToPast(userID string, recurrence alarm.Recurrence, filename string) error
+	// ListUnackedAlarms lista las alarmas disparadas que esperan confirmación
+	ListUnackedAlarms(userID string) ([]*alarm.Alarm, error)
+	AckAlarm(userID string, alarmID string) error
+	// SnoozeAlarm repite una alarma disparada como una alarma one-time para at+d
+	SnoozeAlarm(userID string, alarmID string, at time.Time, d time.Duration) (*alarm.Alarm, error)
+	// NagAlarms vuelve a entregar las alarmas sin confirmar entregadas hace every o más
+	NagAlarms(userID string, at time.Time, every time.Duration) ([]*alarm.Alarm, error)
+
+	// Migración entre backends
+	// DumpUser exporta todos los datos de un usuario
+	DumpUser(userID string) (*UserDump, error)
+	// RestoreUser importa los datos exportados con DumpUser
+	RestoreUser(dump *UserDump) error
+}
+
+// Backends de almacenamiento soportados
+const (
+	BackendFilesystem = "fs"
+	BackendSQLite     = "sqlite"
+)
+
+// New crea el storage del backend indicado ("fs" o "sqlite") en dataDir
+func New(backend, dataDir string) (Storage, error) {
+	switch backend {
+	case "", BackendFilesystem:
+		return NewFilesystemStorage(dataDir)
+	case BackendSQLite:
+		return NewSQLiteStorage(dataDir)
+	}
+	return nil, fmt.Errorf("backend de storage desconocido: %s (use fs o sqlite)", backend)
+}
+
+// Close libera los recursos abiertos de s (la base del backend SQLite, a
+// través de los wrappers). Con el backend de filesystem no hace nada.
+func Close(s Storage) error {
+	if c, ok := s.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
 }
 
 // ReportState almacena el estado de los reportes generados
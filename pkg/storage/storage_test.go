@@ -0,0 +1,245 @@
+package storage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/user"
+)
+
+// testBackends son los backends sobre los que corren los tests comunes
+var testBackends = []string{BackendFilesystem, BackendSQLite}
+
+// forEachBackend ejecuta fn como un subtest por cada backend
+func forEachBackend(t *testing.T, fn func(t *testing.T, backend string)) {
+	for _, backend := range testBackends {
+		t.Run(backend, func(t *testing.T) {
+			fn(t, backend)
+		})
+	}
+}
+
+// newTestStorage crea un storage vacío del backend indicado en un directorio
+// temporal y retorna el storage y el directorio
+func newTestStorage(t *testing.T, backend string) (Storage, string) {
+	t.Helper()
+	dir := t.TempDir()
+	s, err := New(backend, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := s.(io.Closer); ok {
+		t.Cleanup(func() { c.Close() })
+	}
+	return s, dir
+}
+
+// seedRenameUser crea un usuario con un evento, una serie con override, una
+// alarma disparada y estado de reportes, y lista sus eventos para que el
+// backend de filesystem arme su índice
+func seedRenameUser(t *testing.T, s Storage, userID string) (*calendar.Entry, *calendar.Entry) {
+	t.Helper()
+	if err := s.SaveUser(user.NewUser(userID, "Ana", "UTC")); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	single := calendar.NewEntry(userID, "Dentista", start, 30)
+	weekly := calendar.NewEntry(userID, "Weekly", start.Add(time.Hour), 30)
+	weekly.RRule, _ = calendar.ParseRecurrenceRule("FREQ=WEEKLY")
+	for _, e := range []*calendar.Entry{single, weekly} {
+		if err := s.SaveEntry(userID, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	moved := weekly.Occurrence(weekly.DateTime.AddDate(0, 0, 7))
+	moved.Title = "Weekly (moved)"
+	if err := s.SaveEntry(userID, moved); err != nil {
+		t.Fatal(err)
+	}
+
+	filename := alarm.DailySchedule{Hour: 8}.Filename()
+	alm := alarm.NewAlarm("stand-up", alarm.RecurrenceDaily)
+	alm.CreatedAt = start.AddDate(0, 0, -1)
+	if err := s.SaveAlarm(userID, alm.CreatedAt, alarm.RecurrenceDaily, filename, alm); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CheckAlarms(userID, start.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	state := NewReportState()
+	state.ReportedEvents[single.ID] = "2025-12-01T08:00:00Z"
+	if err := s.SaveReportState(userID, state); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.ListEntries(userID, calendar.NewFilter()); err != nil {
+		t.Fatal(err)
+	}
+	return single, weekly
+}
+
+func TestRenameUser(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, dir := newTestStorage(t, backend)
+		single, weekly := seedRenameUser(t, s, "u1")
+
+		if err := s.RenameUser("u1", "u2"); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := s.GetUser("u1"); err == nil {
+			t.Error("Expected u1 to be gone")
+		}
+		if u, err := s.GetUser("u2"); err != nil || u.ID != "u2" {
+			t.Fatalf("GetUser(u2) = %v, %v", u, err)
+		}
+		if users, err := s.ListUsers(); err != nil || len(users) != 1 {
+			t.Errorf("ListUsers() = %v, %v, want solo u2", users, err)
+		}
+
+		// Eventos (y el índice, que se reconstruye con el nuevo ID)
+		got, err := s.GetEntry("u2", single.ID)
+		if err != nil || got.UserID != "u2" || got.Title != "Dentista" {
+			t.Errorf("GetEntry() = %v, %v", got, err)
+		}
+		from := weekly.DateTime
+		to := from.AddDate(0, 0, 8)
+		entries, err := s.ListEntries("u2", calendar.NewFilter().WithDateRange(from, to))
+		if err != nil || len(entries) != 2 || entries[1].Title != "Weekly (moved)" {
+			t.Errorf("ListEntries() = %v, %v, want la serie con su override", entries, err)
+		}
+		if entries, _ := s.ListEntries("u1", calendar.NewFilter()); len(entries) != 0 {
+			t.Errorf("ListEntries(u1) = %v, want ninguno", entries)
+		}
+
+		// Alarmas y estado
+		if active, err := s.ListActiveAlarms("u2"); err != nil || len(active) != 1 {
+			t.Errorf("ListActiveAlarms() = %v, %v", active, err)
+		}
+		if past, err := s.ListPastAlarms("u2"); err != nil || len(past) != 1 {
+			t.Errorf("ListPastAlarms() = %v, %v", past, err)
+		}
+		if unacked, err := s.ListUnackedAlarms("u2"); err != nil || len(unacked) != 1 {
+			t.Errorf("ListUnackedAlarms() = %v, %v", unacked, err)
+		}
+		if state, err := s.GetReportState("u2"); err != nil || state.ReportedEvents[single.ID] == "" {
+			t.Errorf("GetReportState() = %+v, %v", state, err)
+		}
+
+		// No quedan directorios temporales ni lápidas
+		if _, err := os.Stat(filepath.Join(dir, ".tmp")); !os.IsNotExist(err) {
+			t.Errorf("Expected .tmp to be removed, stat error = %v", err)
+		}
+	})
+}
+
+func TestRenameUserExisting(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newTestStorage(t, backend)
+		single, _ := seedRenameUser(t, s, "u1")
+		if err := s.SaveUser(user.NewUser("u2", "Beto", "UTC")); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := s.RenameUser("u1", "u2"); err == nil {
+			t.Fatal("Expected error renaming to an existing user")
+		}
+		if err := s.RenameUser("u1", "../u3"); err == nil {
+			t.Error("Expected error renaming to an invalid ID")
+		}
+
+		// Ambos usuarios quedan intactos
+		if u, err := s.GetUser("u2"); err != nil || u.Name != "Beto" {
+			t.Errorf("GetUser(u2) = %v, %v", u, err)
+		}
+		if got, err := s.GetEntry("u1", single.ID); err != nil || got.UserID != "u1" {
+			t.Errorf("GetEntry(u1) = %v, %v", got, err)
+		}
+		if entries, _ := s.ListEntries("u2", calendar.NewFilter()); len(entries) != 0 {
+			t.Errorf("ListEntries(u2) = %v, want ninguno", entries)
+		}
+	})
+}
+
+func TestArchiveUser(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, _ := newTestStorage(t, backend)
+		single, _ := seedRenameUser(t, s, "u1")
+
+		var buf bytes.Buffer
+		if err := s.ArchiveUser("u1", &buf); err != nil {
+			t.Fatal(err)
+		}
+
+		gz, err := gzip.NewReader(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr := tar.NewReader(gz)
+		files := map[string]string{}
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(header.Name, "u1/") {
+				t.Errorf("Archivo fuera de u1/: %s", header.Name)
+			}
+			if header.Typeflag == tar.TypeReg {
+				data, _ := io.ReadAll(tr)
+				files[header.Name] = string(data)
+			}
+		}
+
+		if !strings.Contains(files["u1/user.json"], `"id": "u1"`) {
+			t.Errorf("user.json = %q", files["u1/user.json"])
+		}
+		var events, overrides, alarms, past int
+		for name, data := range files {
+			switch {
+			case strings.HasPrefix(name, "u1/events/") && strings.HasSuffix(name, ".json"):
+				if strings.Contains(data, `"recurrence_id"`) {
+					overrides++
+				} else {
+					events++
+				}
+			case strings.HasPrefix(name, "u1/alarms/past/"):
+				past++
+			case strings.HasPrefix(name, "u1/alarms/") && !strings.HasSuffix(name, "unacked.json"):
+				alarms++
+			}
+		}
+		if events != 2 || overrides != 1 || alarms != 1 || past != 1 {
+			t.Errorf("Archivo: %d eventos, %d overrides, %d alarmas, %d pasadas; files = %v", events, overrides, alarms, past, fileNames(files))
+		}
+		if _, ok := files["u1/alarms/unacked.json"]; !ok {
+			t.Error("Falta alarms/unacked.json")
+		}
+		if state := files["u1/.state/report-state.json"]; !strings.Contains(state, single.ID) {
+			t.Errorf("report-state.json = %q", state)
+		}
+	})
+}
+
+// fileNames retorna los nombres de los archivos leídos, para mensajes de error
+func fileNames(m map[string]string) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
@@ -0,0 +1,347 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/calendar"
+	"github.com/sebasvalencia/clical/pkg/user"
+)
+
+// trashDirname es la papelera de un usuario, dentro de su directorio
+const trashDirname = ".trash"
+
+// Tipos de elementos de la papelera
+const (
+	TrashEvent = "event"
+	TrashAlarm = "alarm"
+)
+
+// TrashItem es un evento eliminado o una alarma cancelada
+type TrashItem struct {
+	Kind      string    `json:"kind"` // TrashEvent o TrashAlarm
+	ID        string    `json:"id"`   // ID del evento o de la alarma
+	DeletedAt time.Time `json:"deleted_at"`
+
+	// Evento eliminado, con sus overrides de ocurrencia
+	Entry     *calendar.Entry   `json:"entry,omitempty"`
+	Overrides []*calendar.Entry `json:"overrides,omitempty"`
+
+	// Alarma cancelada y el archivo (schedule) en el que estaba
+	Alarm      *alarm.Alarm     `json:"alarm,omitempty"`
+	Recurrence alarm.Recurrence `json:"recurrence,omitempty"`
+	Filename   string           `json:"filename,omitempty"`
+
+	file string // nombre del archivo en .trash/
+}
+
+// Title describe el elemento: el título del evento o el contexto de la alarma
+func (t *TrashItem) Title() string {
+	if t.Entry != nil {
+		return t.Entry.Title
+	}
+	if t.Alarm != nil {
+		return t.Alarm.Context
+	}
+	return ""
+}
+
+// TrashBin lo implementan los Storage que mueven a una papelera los eventos
+// eliminados y las alarmas canceladas en lugar de borrarlos
+type TrashBin interface {
+	// ListTrash retorna los elementos de la papelera, del más viejo al más nuevo
+	ListTrash(userID string) ([]*TrashItem, error)
+	// RestoreTrash recupera el elemento más reciente con el ID dado (de evento o alarma)
+	RestoreTrash(userID, id string) (*TrashItem, error)
+	// PurgeTrash elimina definitivamente los elementos borrados antes de before
+	PurgeTrash(userID string, before time.Time) (int, error)
+}
+
+// trashStorage envuelve un Storage guardando en users/<id>/.trash/ una copia
+// de cada evento antes de eliminarlo y de cada alarma antes de cancelarla.
+// Cada operación que modifica datos (y CheckAlarms) purga además los
+// elementos más viejos que retention.
+type trashStorage struct {
+	Storage
+	dataDir   string
+	retention time.Duration // 0: no purgar automáticamente
+}
+
+// Interfaces opcionales que trashStorage implementa o reenvía al backend
+var (
+	_ TrashBin       = (*trashStorage)(nil)
+	_ MarkdownSyncer = (*trashStorage)(nil)
+	_ io.Closer      = (*trashStorage)(nil)
+)
+
+// WithTrash retorna s con papelera. Los elementos se purgan automáticamente
+// después de retention (0 o negativo: nunca).
+func WithTrash(s Storage, dataDir string, retention time.Duration) Storage {
+	return &trashStorage{Storage: s, dataDir: dataDir, retention: retention}
+}
+
+// Close cierra el storage envuelto
+func (t *trashStorage) Close() error {
+	return Close(t.Storage)
+}
+
+// trashDir retorna la papelera de un usuario
+func (t *trashStorage) trashDir(userID string) string {
+	return filepath.Join(getUserDir(t.dataDir, userID), trashDirname)
+}
+
+// put guarda un elemento en la papelera
+func (t *trashStorage) put(userID string, item *TrashItem) error {
+	dir := t.trashDir(userID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creando papelera: %w", err)
+	}
+
+	item.DeletedAt = time.Now()
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializando papelera: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s-%s.json", item.DeletedAt.UTC().Format("20060102T150405.000000000"), item.Kind, item.ID)
+	if err := writeFileAtomic(filepath.Join(dir, name), data, 0644); err != nil {
+		return fmt.Errorf("error escribiendo papelera: %w", err)
+	}
+	return nil
+}
+
+func (t *trashStorage) ListTrash(userID string) ([]*TrashItem, error) {
+	files, err := os.ReadDir(t.trashDir(userID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error leyendo papelera: %w", err)
+	}
+
+	var items []*TrashItem
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(t.trashDir(userID), f.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo papelera: %w", err)
+		}
+		var item TrashItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, fmt.Errorf("error parseando %s: %w", f.Name(), err)
+		}
+		item.file = f.Name()
+		items = append(items, &item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DeletedAt.Before(items[j].DeletedAt) })
+	return items, nil
+}
+
+func (t *trashStorage) RestoreTrash(userID, id string) (*TrashItem, error) {
+	items, err := t.ListTrash(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var item *TrashItem
+	for _, it := range items {
+		if it.ID == id {
+			item = it
+		}
+	}
+	if item == nil {
+		return nil, fmt.Errorf("no está en la papelera: %s", id)
+	}
+
+	switch item.Kind {
+	case TrashEvent:
+		if _, err := t.Storage.GetEntry(userID, item.ID); err == nil {
+			return nil, fmt.Errorf("ya existe un evento con id %s", item.ID)
+		}
+		item.Entry.UserID = userID
+		if err := t.Storage.SaveEntry(userID, item.Entry); err != nil {
+			return nil, err
+		}
+		for _, o := range item.Overrides {
+			o.UserID = userID
+			if err := t.Storage.SaveEntry(userID, o); err != nil {
+				return nil, err
+			}
+		}
+	case TrashAlarm:
+		if item.Recurrence == alarm.RecurrenceOnce {
+			if at, err := parseOneTimeFilename(item.Filename, alarmLocation(t, userID, time.Local)); err == nil && at.Before(time.Now()) {
+				return nil, fmt.Errorf("la alarma %s era para %s, que ya pasó", item.ID, at.Format("2006-01-02 15:04"))
+			}
+		}
+		if err := t.Storage.SaveAlarm(userID, time.Now(), item.Recurrence, item.Filename, item.Alarm); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("elemento de papelera desconocido: %s", item.Kind)
+	}
+
+	if err := removeFile(filepath.Join(t.trashDir(userID), item.file)); err != nil {
+		return nil, fmt.Errorf("error eliminando de la papelera: %w", err)
+	}
+	return item, nil
+}
+
+func (t *trashStorage) PurgeTrash(userID string, before time.Time) (int, error) {
+	items, err := t.ListTrash(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, item := range items {
+		if !item.DeletedAt.Before(before) {
+			continue
+		}
+		if err := removeFile(filepath.Join(t.trashDir(userID), item.file)); err != nil {
+			return purged, fmt.Errorf("error purgando papelera: %w", err)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// purgeExpired purga los elementos más viejos que la retención. Es
+// best-effort: una falla no hace fallar la operación que lo disparó.
+func (t *trashStorage) purgeExpired(userID string) {
+	if t.retention > 0 {
+		t.PurgeTrash(userID, time.Now().Add(-t.retention))
+	}
+}
+
+// after purga la papelera si err es nil y retorna err
+func (t *trashStorage) after(userID string, err error) error {
+	if err == nil {
+		t.purgeExpired(userID)
+	}
+	return err
+}
+
+func (t *trashStorage) DeleteEntry(userID, entryID string) error {
+	entry, err := t.Storage.GetEntry(userID, entryID)
+	if err != nil {
+		return err
+	}
+	var overrides []*calendar.Entry
+	if entry.IsRecurring() {
+		if overrides, err = t.Storage.ListOverrides(userID, entryID); err != nil {
+			return err
+		}
+	}
+
+	if err := t.put(userID, &TrashItem{Kind: TrashEvent, ID: entryID, Entry: entry, Overrides: overrides}); err != nil {
+		return err
+	}
+	return t.after(userID, t.Storage.DeleteEntry(userID, entryID))
+}
+
+func (t *trashStorage) CancelAlarm(userID string, alarmID string) error {
+	alarms, err := t.Storage.ListActiveAlarms(userID)
+	if err != nil {
+		return err
+	}
+	for _, alm := range alarms {
+		if alm.ID != alarmID || alm.Schedule == nil {
+			continue
+		}
+		item := &TrashItem{Kind: TrashAlarm, ID: alarmID, Alarm: alm, Recurrence: alm.Recurrence, Filename: alm.Schedule.Filename}
+		if err := t.put(userID, item); err != nil {
+			return err
+		}
+		break
+	}
+	return t.after(userID, t.Storage.CancelAlarm(userID, alarmID))
+}
+
+func (t *trashStorage) SaveEntry(userID string, entry *calendar.Entry) error {
+	return t.after(userID, t.Storage.SaveEntry(userID, entry))
+}
+
+func (t *trashStorage) UpdateEntry(userID string, entry *calendar.Entry) error {
+	return t.after(userID, t.Storage.UpdateEntry(userID, entry))
+}
+
+func (t *trashStorage) SaveUser(u *user.User) error {
+	return t.after(u.ID, t.Storage.SaveUser(u))
+}
+
+func (t *trashStorage) RestoreUser(dump *UserDump) error {
+	return t.after(dump.User.ID, t.Storage.RestoreUser(dump))
+}
+
+func (t *trashStorage) SaveReportState(userID string, state *ReportState) error {
+	return t.after(userID, t.Storage.SaveReportState(userID, state))
+}
+
+func (t *trashStorage) SaveAlarm(userID string, alarmTime time.Time, recurrence alarm.Recurrence, filename string, alm *alarm.Alarm) error {
+	return t.after(userID, t.Storage.SaveAlarm(userID, alarmTime, recurrence, filename, alm))
+}
+
+func (t *trashStorage) DeleteAlarms(userID string, recurrence alarm.Recurrence, filename string) error {
+	return t.after(userID, t.Storage.DeleteAlarms(userID, recurrence, filename))
+}
+
+func (t *trashStorage) MoveAlarmsToPast(userID string, recurrence alarm.Recurrence, filename string) error {
+	return t.after(userID, t.Storage.MoveAlarmsToPast(userID, recurrence, filename))
+}
+
+func (t *trashStorage) CheckAlarms(userID string, at time.Time) ([]*alarm.Alarm, error) {
+	alarms, err := t.Storage.CheckAlarms(userID, at)
+	return alarms, t.after(userID, err)
+}
+
+func (t *trashStorage) AckAlarm(userID string, alarmID string) error {
+	return t.after(userID, t.Storage.AckAlarm(userID, alarmID))
+}
+
+func (t *trashStorage) SnoozeAlarm(userID string, alarmID string, at time.Time, d time.Duration) (*alarm.Alarm, error) {
+	snoozed, err := t.Storage.SnoozeAlarm(userID, alarmID, at, d)
+	return snoozed, t.after(userID, err)
+}
+
+func (t *trashStorage) NagAlarms(userID string, at time.Time, every time.Duration) ([]*alarm.Alarm, error) {
+	alarms, err := t.Storage.NagAlarms(userID, at, every)
+	return alarms, t.after(userID, err)
+}
+
+// RenameUser mueve también la papelera (con el backend de filesystem ya se
+// movió junto con el resto del directorio del usuario)
+func (t *trashStorage) RenameUser(oldID, newID string) error {
+	if err := t.Storage.RenameUser(oldID, newID); err != nil {
+		return err
+	}
+	return moveUserSubdir(t.dataDir, oldID, newID, trashDirname)
+}
+
+// DeleteUser elimina también la papelera del usuario
+func (t *trashStorage) DeleteUser(userID string) error {
+	if err := t.Storage.DeleteUser(userID); err != nil {
+		return err
+	}
+	return removeUserSubdir(t.dataDir, userID, trashDirname)
+}
+
+// SyncMarkdown delega en el backend, si guarda archivos Markdown
+func (t *trashStorage) SyncMarkdown(userID string, opts SyncOptions) (*SyncResult, error) {
+	syncer, ok := t.Storage.(MarkdownSyncer)
+	if !ok {
+		return nil, fmt.Errorf("el backend de storage no guarda archivos Markdown")
+	}
+	result, err := syncer.SyncMarkdown(userID, opts)
+	return result, t.after(userID, err)
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sebasvalencia/clical/pkg/alarm"
+	"github.com/sebasvalencia/clical/pkg/calendar"
+)
+
+func newTrashTestStorage(t *testing.T, backend string, retention time.Duration) (Storage, TrashBin) {
+	base, dir := newTestStorage(t, backend)
+	s := WithTrash(base, dir, retention)
+	return s, s.(TrashBin)
+}
+
+func TestTrashEvent(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, bin := newTrashTestStorage(t, backend, 0)
+		start := time.Date(2025, 11, 17, 10, 0, 0, 0, time.UTC)
+
+		weekly := calendar.NewEntry("u1", "Weekly", start, 30)
+		weekly.RRule, _ = calendar.ParseRecurrenceRule("FREQ=WEEKLY")
+		if err := s.SaveEntry("u1", weekly); err != nil {
+			t.Fatal(err)
+		}
+		occ := weekly.Occurrence(start.AddDate(0, 0, 7))
+		occ.Title = "Weekly (moved)"
+		if err := s.SaveEntry("u1", occ); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := s.DeleteEntry("u1", weekly.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.GetEntry("u1", weekly.ID); err == nil {
+			t.Fatal("el evento sigue existiendo")
+		}
+
+		items, err := bin.ListTrash("u1")
+		if err != nil || len(items) != 1 || items[0].Kind != TrashEvent || len(items[0].Overrides) != 1 {
+			t.Fatalf("ListTrash() = %+v, %v", items, err)
+		}
+
+		if _, err := bin.RestoreTrash("u1", weekly.ID); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.GetOccurrence("u1", weekly.ID, start.AddDate(0, 0, 7))
+		if err != nil || got.Title != "Weekly (moved)" {
+			t.Errorf("override restaurado = %v, %v", got, err)
+		}
+		if items, _ := bin.ListTrash("u1"); len(items) != 0 {
+			t.Errorf("la papelera no quedó vacía: %d elementos", len(items))
+		}
+		if _, err := bin.RestoreTrash("u1", weekly.ID); err == nil {
+			t.Error("RestoreTrash() de un ID que no está en la papelera no falló")
+		}
+	})
+}
+
+func TestTrashAlarm(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, bin := newTrashTestStorage(t, backend, 0)
+
+		alm := alarm.NewAlarm("standup", alarm.RecurrenceDaily)
+		filename := alarm.DailySchedule{Hour: 9, Minute: 0}.Filename()
+		if err := s.SaveAlarm("u1", time.Now(), alarm.RecurrenceDaily, filename, alm); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.CancelAlarm("u1", alm.ID); err != nil {
+			t.Fatal(err)
+		}
+		if active, _ := s.ListActiveAlarms("u1"); len(active) != 0 {
+			t.Fatalf("alarmas activas = %d, want 0", len(active))
+		}
+
+		item, err := bin.RestoreTrash("u1", alm.ID)
+		if err != nil || item.Kind != TrashAlarm {
+			t.Fatalf("RestoreTrash() = %+v, %v", item, err)
+		}
+		active, err := s.GetAlarms("u1", alarm.RecurrenceDaily, filename)
+		if err != nil || len(active) != 1 || active[0].ID != alm.ID {
+			t.Errorf("alarma restaurada = %v, %v", active, err)
+		}
+	})
+}
+
+func TestTrashAutoPurge(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend string) {
+		s, bin := newTrashTestStorage(t, backend, time.Hour)
+		start := time.Date(2025, 11, 17, 10, 0, 0, 0, time.UTC)
+
+		old := calendar.NewEntry("u1", "Old", start, 30)
+		recent := calendar.NewEntry("u1", "Recent", start.Add(time.Hour), 30)
+		for _, e := range []*calendar.Entry{old, recent} {
+			if err := s.SaveEntry("u1", e); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.DeleteEntry("u1", old.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		// Envejecer el elemento más allá de la retención
+		items, _ := bin.ListTrash("u1")
+		items[0].DeletedAt = time.Now().Add(-2 * time.Hour)
+		data, _ := json.Marshal(items[0])
+		path := filepath.Join(s.(*trashStorage).trashDir("u1"), items[0].file)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// Cualquier operación que modifica datos purga lo vencido
+		if _, err := s.CheckAlarms("u1", time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.DeleteEntry("u1", recent.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		items, err := bin.ListTrash("u1")
+		if err != nil || len(items) != 1 || items[0].ID != recent.ID {
+			t.Fatalf("ListTrash() = %+v, %v; want solo %s", items, err, recent.ID)
+		}
+	})
+}
@@ -0,0 +1,260 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DayOff marca un día no laborable en Schedule
+const DayOff = "off"
+
+// weekdayNames son los nombres cortos de los días usados en Workdays y Schedule
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// DefaultWorkdays retorna los días laborables por defecto (lunes a viernes)
+func DefaultWorkdays() []string {
+	return []string{"mon", "tue", "wed", "thu", "fri"}
+}
+
+// Window es una franja horaria en minutos desde la medianoche, [Start, End)
+type Window struct {
+	Start int
+	End   int
+}
+
+// Minutes retorna la duración de la franja en minutos
+func (w Window) Minutes() int {
+	return w.End - w.Start
+}
+
+// String formatea la franja como HH:MM-HH:MM
+func (w Window) String() string {
+	return FormatClock(w.Start) + "-" + FormatClock(w.End)
+}
+
+// WorkWindows retorna las franjas laborables del día, ya sin el almuerzo.
+// Retorna nil si el día no es laborable.
+func (c UserConfig) WorkWindows(day time.Weekday) []Window {
+	var windows []Window
+
+	if spec, ok := c.Schedule[weekdayNames[day]]; ok {
+		windows, _ = ParseWindows(spec)
+	} else if c.isWorkday(day) {
+		windows = []Window{c.defaultWindow()}
+	}
+
+	if lunch, ok := c.lunch(); ok {
+		windows = subtractWindow(windows, lunch)
+	}
+	return windows
+}
+
+// WorkMinutes retorna los minutos laborables del día
+func (c UserConfig) WorkMinutes(day time.Weekday) int {
+	total := 0
+	for _, w := range c.WorkWindows(day) {
+		total += w.Minutes()
+	}
+	return total
+}
+
+// IsWorkday indica si el día tiene alguna franja laborable
+func (c UserConfig) IsWorkday(day time.Weekday) bool {
+	return len(c.WorkWindows(day)) > 0
+}
+
+// isWorkday indica si el día está en Workdays (lunes a viernes si está vacío)
+func (c UserConfig) isWorkday(day time.Weekday) bool {
+	workdays := c.Workdays
+	if len(workdays) == 0 {
+		workdays = DefaultWorkdays()
+	}
+	for _, name := range workdays {
+		if d, err := ParseWeekday(name); err == nil && d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultWindow retorna la jornada de WorkStart a WorkEnd. Los valores vacíos
+// o inválidos usan la jornada por defecto.
+func (c UserConfig) defaultWindow() Window {
+	start, err := ParseClock(c.WorkStart)
+	if err != nil {
+		start, _ = ParseClock(DefaultWorkStart)
+	}
+	end, err := ParseClock(c.WorkEnd)
+	if err != nil || end <= start {
+		end, _ = ParseClock(DefaultWorkEnd)
+	}
+	return Window{Start: start, End: end}
+}
+
+// lunch retorna el horario de almuerzo, si está configurado
+func (c UserConfig) lunch() (Window, bool) {
+	if c.LunchStart == "" || c.LunchEnd == "" {
+		return Window{}, false
+	}
+	w, err := ParseWindow(c.LunchStart + "-" + c.LunchEnd)
+	if err != nil {
+		return Window{}, false
+	}
+	return w, true
+}
+
+// validateSchedule valida la jornada laboral, el almuerzo y el buffer entre reuniones
+func (c UserConfig) validateSchedule() error {
+	var start, end int
+	var err error
+	if c.WorkStart != "" {
+		if start, err = ParseClock(c.WorkStart); err != nil {
+			return fmt.Errorf("work_start inválido: %w", err)
+		}
+	}
+	if c.WorkEnd != "" {
+		if end, err = ParseClock(c.WorkEnd); err != nil {
+			return fmt.Errorf("work_end inválido: %w", err)
+		}
+	}
+	if c.WorkStart != "" && c.WorkEnd != "" && end <= start {
+		return fmt.Errorf("work_end debe ser posterior a work_start")
+	}
+
+	for _, name := range c.Workdays {
+		if _, err := ParseWeekday(name); err != nil {
+			return fmt.Errorf("workdays inválido: %w", err)
+		}
+	}
+
+	for name, spec := range c.Schedule {
+		if _, err := ParseWeekday(name); err != nil {
+			return fmt.Errorf("schedule inválido: %w", err)
+		}
+		if _, err := ParseWindows(spec); err != nil {
+			return fmt.Errorf("schedule inválido para %s: %w", name, err)
+		}
+	}
+
+	if (c.LunchStart == "") != (c.LunchEnd == "") {
+		return fmt.Errorf("lunch_start y lunch_end deben configurarse juntos")
+	}
+	if c.LunchStart != "" {
+		if _, err := ParseWindow(c.LunchStart + "-" + c.LunchEnd); err != nil {
+			return fmt.Errorf("almuerzo inválido: %w", err)
+		}
+	}
+
+	if c.BufferMinutes < 0 {
+		return fmt.Errorf("buffer_minutes no puede ser negativo")
+	}
+	return nil
+}
+
+// ParseWeekday parsea un día de la semana en inglés ("mon", "monday") o
+// español ("lun", "lunes")
+func ParseWeekday(s string) (time.Weekday, error) {
+	names := map[string]time.Weekday{
+		"sun": time.Sunday, "sunday": time.Sunday, "dom": time.Sunday, "domingo": time.Sunday,
+		"mon": time.Monday, "monday": time.Monday, "lun": time.Monday, "lunes": time.Monday,
+		"tue": time.Tuesday, "tuesday": time.Tuesday, "mar": time.Tuesday, "martes": time.Tuesday,
+		"wed": time.Wednesday, "wednesday": time.Wednesday, "mie": time.Wednesday, "miercoles": time.Wednesday, "miércoles": time.Wednesday,
+		"thu": time.Thursday, "thursday": time.Thursday, "jue": time.Thursday, "jueves": time.Thursday,
+		"fri": time.Friday, "friday": time.Friday, "vie": time.Friday, "viernes": time.Friday,
+		"sat": time.Saturday, "saturday": time.Saturday, "sab": time.Saturday, "sabado": time.Saturday, "sábado": time.Saturday,
+	}
+	if d, ok := names[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return d, nil
+	}
+	return 0, fmt.Errorf("día inválido %q", s)
+}
+
+// WeekdayName retorna el nombre corto del día usado en Workdays y Schedule
+func WeekdayName(day time.Weekday) string {
+	return weekdayNames[day]
+}
+
+// ParseWindow parsea una franja horaria HH:MM-HH:MM
+func ParseWindow(s string) (Window, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "-", 2)
+	if len(parts) != 2 {
+		return Window{}, fmt.Errorf("franja inválida %q (formato HH:MM-HH:MM)", s)
+	}
+	start, err := ParseClock(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return Window{}, err
+	}
+	end, err := ParseClock(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return Window{}, err
+	}
+	if end <= start {
+		return Window{}, fmt.Errorf("franja inválida %q: el fin debe ser posterior al inicio", s)
+	}
+	return Window{Start: start, End: end}, nil
+}
+
+// ParseWindows parsea una o más franjas separadas por coma (turno partido),
+// ej: "09:00-13:00,15:00-19:00". "off" o vacío indica un día no laborable.
+func ParseWindows(s string) ([]Window, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, DayOff) {
+		return nil, nil
+	}
+
+	var windows []Window
+	for _, part := range strings.Split(s, ",") {
+		w, err := ParseWindow(part)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start < windows[j].Start
+	})
+	for i := 1; i < len(windows); i++ {
+		if windows[i].Start < windows[i-1].End {
+			return nil, fmt.Errorf("franjas superpuestas en %q", s)
+		}
+	}
+	return windows, nil
+}
+
+// FormatWindows formatea franjas como en Schedule ("off" si no hay ninguna)
+func FormatWindows(windows []Window) string {
+	if len(windows) == 0 {
+		return DayOff
+	}
+	parts := make([]string, len(windows))
+	for i, w := range windows {
+		parts[i] = w.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// FormatClock formatea minutos desde la medianoche como HH:MM
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// subtractWindow quita cut de cada franja, partiéndolas si hace falta
+func subtractWindow(windows []Window, cut Window) []Window {
+	var result []Window
+	for _, w := range windows {
+		if cut.End <= w.Start || cut.Start >= w.End {
+			result = append(result, w)
+			continue
+		}
+		if cut.Start > w.Start {
+			result = append(result, Window{Start: w.Start, End: cut.Start})
+		}
+		if cut.End < w.End {
+			result = append(result, Window{Start: cut.End, End: w.End})
+		}
+	}
+	return result
+}
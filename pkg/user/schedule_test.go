@@ -0,0 +1,107 @@
+package user
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWorkWindows(t *testing.T) {
+	config := DefaultConfig()
+	config.Workdays = []string{"mon", "tue", "wed", "thu", "fri"}
+	config.Schedule = map[string]string{
+		"fri": "09:00-14:00",
+		"sat": "09:00-13:00,15:00-19:00",
+	}
+	config.LunchStart = "13:00"
+	config.LunchEnd = "14:00"
+
+	tests := []struct {
+		name    string
+		config  UserConfig
+		day     time.Weekday
+		want    []Window
+		minutes int
+	}{
+		{"default config", DefaultConfig(), time.Monday, []Window{{480, 1080}}, 600},
+		{"default weekend", DefaultConfig(), time.Sunday, nil, 0},
+		// Usuarios creados antes de configurar la jornada no tienen los campos
+		{"empty config", UserConfig{}, time.Wednesday, []Window{{480, 1080}}, 600},
+		{"lunch splits the day", config, time.Tuesday, []Window{{480, 780}, {840, 1080}}, 540},
+		{"schedule overrides workday", config, time.Friday, []Window{{540, 780}}, 240},
+		{"split shift", config, time.Saturday, []Window{{540, 780}, {900, 1140}}, 480},
+		{"day off", config, time.Sunday, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.config.WorkWindows(tt.day)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WorkWindows(%v) = %v, want %v", tt.day, got, tt.want)
+			}
+			if minutes := tt.config.WorkMinutes(tt.day); minutes != tt.minutes {
+				t.Errorf("WorkMinutes(%v) = %d, want %d", tt.day, minutes, tt.minutes)
+			}
+		})
+	}
+}
+
+func TestParseWindows(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []Window
+		wantErr bool
+	}{
+		{"09:00-18:00", []Window{{540, 1080}}, false},
+		{"15:00-19:00, 09:00-13:00", []Window{{540, 780}, {900, 1140}}, false},
+		{"off", nil, false},
+		{"", nil, false},
+		{"18:00-09:00", nil, true},
+		{"09:00-13:00,12:00-15:00", nil, true},
+		{"09:00", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseWindows(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWindows(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWindows(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if s := FormatWindows([]Window{{540, 780}, {900, 1440}}); s != "09:00-13:00,15:00-24:00" {
+		t.Errorf("Unexpected format: %s", s)
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *UserConfig)
+		wantErr bool
+	}{
+		{"default", func(c *UserConfig) {}, false},
+		{"work_end before work_start", func(c *UserConfig) { c.WorkStart, c.WorkEnd = "17:00", "09:00" }, true},
+		{"invalid workday", func(c *UserConfig) { c.Workdays = []string{"mon", "funday"} }, true},
+		{"spanish workday", func(c *UserConfig) { c.Workdays = []string{"lunes", "mie"} }, false},
+		{"invalid schedule day", func(c *UserConfig) { c.Schedule = map[string]string{"xyz": "off"} }, true},
+		{"invalid schedule hours", func(c *UserConfig) { c.Schedule = map[string]string{"fri": "14:00-09:00"} }, true},
+		{"lunch without end", func(c *UserConfig) { c.LunchStart = "13:00" }, true},
+		{"valid lunch", func(c *UserConfig) { c.LunchStart, c.LunchEnd = "13:00", "14:00" }, false},
+		{"negative buffer", func(c *UserConfig) { c.BufferMinutes = -5 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUser("1", "Test", "UTC")
+			tt.modify(&u.Config)
+			if err := u.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,9 +2,16 @@ package user
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
+// Jornada laboral por defecto
+const (
+	DefaultWorkStart = "08:00"
+	DefaultWorkEnd   = "18:00"
+)
+
 // User representa un usuario del sistema
 type User struct {
 	ID      string     `json:"id"`
@@ -20,6 +27,17 @@ type UserConfig struct {
 	DateFormat      string `json:"date_format"`
 	TimeFormat      string `json:"time_format"`
 	FirstDayOfWeek  int    `json:"first_day_of_week"` // 0=Domingo, 1=Lunes
+
+	// Jornada laboral (HH:MM) de los días laborables, almuerzo y minutos
+	// libres a dejar entre reuniones. Schedule reemplaza la jornada de días
+	// puntuales (ej: "fri": "09:00-14:00", "sat": "off"). Ver schedule.go
+	WorkStart     string            `json:"work_start,omitempty"`
+	WorkEnd       string            `json:"work_end,omitempty"`
+	Workdays      []string          `json:"workdays,omitempty"`
+	Schedule      map[string]string `json:"schedule,omitempty"`
+	LunchStart    string            `json:"lunch_start,omitempty"`
+	LunchEnd      string            `json:"lunch_end,omitempty"`
+	BufferMinutes int               `json:"buffer_minutes,omitempty"`
 }
 
 // NewUser crea un nuevo usuario con configuración por defecto
@@ -40,6 +58,9 @@ func DefaultConfig() UserConfig {
 		DateFormat:      "2006-01-02",    // YYYY-MM-DD
 		TimeFormat:      "15:04",         // HH:MM
 		FirstDayOfWeek:  1,               // Lunes
+		WorkStart:       DefaultWorkStart,
+		WorkEnd:         DefaultWorkEnd,
+		Workdays:        DefaultWorkdays(),
 	}
 }
 
@@ -48,6 +69,9 @@ func (u *User) Validate() error {
 	if u.ID == "" {
 		return fmt.Errorf("id es requerido")
 	}
+	if err := ValidateID(u.ID); err != nil {
+		return err
+	}
 	if u.Name == "" {
 		return fmt.Errorf("name es requerido")
 	}
@@ -65,7 +89,21 @@ func (u *User) Validate() error {
 	if u.Config.DefaultDuration <= 0 {
 		return fmt.Errorf("default_duration debe ser mayor a 0")
 	}
+	if u.Config.FirstDayOfWeek < 0 || u.Config.FirstDayOfWeek > 6 {
+		return fmt.Errorf("first_day_of_week debe estar entre 0 (domingo) y 6 (sábado)")
+	}
+	if err := u.Config.validateSchedule(); err != nil {
+		return err
+	}
+
+	return nil
+}
 
+// ValidateID valida que el ID pueda usarse como nombre de directorio
+func ValidateID(id string) error {
+	if id == "" || strings.HasPrefix(id, ".") || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("id inválido: %q", id)
+	}
 	return nil
 }
 
@@ -88,3 +126,16 @@ func (u *User) FormatTime(t time.Time) string {
 func (u *User) FormatDateTime(t time.Time) string {
 	return u.FormatDate(t) + " " + u.FormatTime(t)
 }
+
+// ParseClock parsea una hora del día (HH:MM) y retorna los minutos desde la
+// medianoche. "24:00" representa el fin del día.
+func ParseClock(s string) (int, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || len(s) < 4 || len(s) > 5 {
+		return 0, fmt.Errorf("hora inválida %q (formato HH:MM)", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m > 0) {
+		return 0, fmt.Errorf("hora fuera de rango %q", s)
+	}
+	return h*60 + m, nil
+}
@@ -60,6 +60,26 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "ID with path separator",
+			user: &User{
+				ID:       "../12345",
+				Name:     "Test",
+				Timezone: "UTC",
+				Config:   DefaultConfig(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid first day of week",
+			user: &User{
+				ID:       "12345",
+				Name:     "Test",
+				Timezone: "UTC",
+				Config:   UserConfig{DefaultDuration: 60, FirstDayOfWeek: 7},
+			},
+			wantErr: true,
+		},
 		{
 			name: "empty name",
 			user: &User{
@@ -181,3 +201,31 @@ func TestDefaultConfig(t *testing.T) {
 		t.Errorf("Expected FirstDayOfWeek 1, got %d", config.FirstDayOfWeek)
 	}
 }
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"09:00", 540, false},
+		{"9:30", 570, false},
+		{"24:00", 1440, false},
+		{"00:00", 0, false},
+		{"24:30", 0, true},
+		{"12:60", 0, true},
+		{"noon", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseClock(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseClock(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseClock(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}